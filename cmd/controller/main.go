@@ -62,6 +62,7 @@ func main() {
 			op.Config,
 			op.Clock,
 			op.EC2API,
+			op.KMSAPI,
 			op.GetClient(),
 			op.EventRecorder,
 			op.UnavailableOfferingsCache,
@@ -78,6 +79,7 @@ func main() {
 			op.VersionProvider,
 			op.InstanceTypesProvider,
 			op.CapacityReservationProvider,
+			op.PermissionProvider,
 			op.AMIResolver,
 		)...).
 		Start(ctx)
@@ -0,0 +1,150 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// karpenter-aws explains how Karpenter would launch nodes for an EC2NodeClass without launching anything:
+//
+//	karpenter-aws [controller flags] explain nodeclass <name> --nodepool <nodepool> [--output text|json]
+//
+// The command is configured with the same flags and environment variables as the controller, and must be placed
+// before the explain subcommand. Insufficient capacity errors are only cached in memory by the controller, so they
+// aren't reflected in the explanation.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	coreoperator "sigs.k8s.io/karpenter/pkg/operator"
+
+	"github.com/aws/karpenter-provider-aws/pkg/cloudprovider"
+	"github.com/aws/karpenter-provider-aws/pkg/explain"
+	"github.com/aws/karpenter-provider-aws/pkg/operator"
+)
+
+const usage = `usage: karpenter-aws [controller flags] explain nodeclass <name> --nodepool <nodepool> [--output text|json]
+
+Insufficient capacity errors are only cached in memory by the controller, so offerings that recently failed to launch
+are shown as available.`
+
+func main() {
+	// The operator parses the controller flags, which stop at the first argument that isn't a flag
+	i := lo.IndexOf(os.Args, "explain")
+	if i == -1 || len(os.Args) < i+3 || os.Args[i+1] != "nodeclass" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	nodeClassName := os.Args[i+2]
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	nodePoolName := fs.String("nodepool", "", "NodePool whose requirements are explained")
+	output := fs.String("output", "text", "Output format, text or json")
+	lo.Must0(fs.Parse(os.Args[i+3:]))
+	if *nodePoolName == "" || (*output != "text" && *output != "json") {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, op := operator.NewOperator(coreoperator.NewOperator())
+	if err := run(ctx, op, nodeClassName, *nodePoolName, *output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, op *operator.Operator, nodeClassName, nodePoolName, output string) error {
+	// The manager isn't started, so its cached client can't be used
+	kubeClient, err := client.New(op.GetConfig(), client.Options{Scheme: op.GetScheme()})
+	if err != nil {
+		return fmt.Errorf("creating kube client, %w", err)
+	}
+	// Hydrate the providers that the controllers would otherwise keep up to date
+	if err := op.InstanceTypesProvider.UpdateInstanceTypes(ctx); err != nil {
+		return fmt.Errorf("updating instance types, %w", err)
+	}
+	if err := op.InstanceTypesProvider.UpdateInstanceTypeOfferings(ctx); err != nil {
+		return fmt.Errorf("updating instance type offerings, %w", err)
+	}
+	if err := op.LaunchTemplateProvider.ResolveClusterCIDR(ctx); err != nil {
+		return fmt.Errorf("resolving cluster cidr, %w", err)
+	}
+	for _, update := range []func(context.Context) error{op.PricingProvider.UpdateOnDemandPricing, op.PricingProvider.UpdateSpotPricing} {
+		if err := update(ctx); err != nil {
+			log.FromContext(ctx).Error(err, "failed updating pricing, using static pricing")
+		}
+	}
+	awsCloudProvider := cloudprovider.New(
+		op.InstanceTypesProvider,
+		op.InstanceProvider,
+		op.EventRecorder,
+		kubeClient,
+		op.AMIProvider,
+		op.SecurityGroupProvider,
+		op.CapacityReservationProvider,
+	)
+	// The controller's insufficient capacity cache is in memory, so the operator's cache is empty and isn't passed
+	explanation, err := explain.NewExplainer(kubeClient, awsCloudProvider, op.LaunchTemplateProvider, nil).Explain(ctx, nodeClassName, nodePoolName)
+	if err != nil {
+		return err
+	}
+	if output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(explanation)
+	}
+	return printExplanation(os.Stdout, explanation)
+}
+
+func printExplanation(out io.Writer, explanation *explain.Explanation) error {
+	fmt.Fprintf(out, "EC2NodeClass: %s\nNodePool: %s\nReady: %t", explanation.NodeClass, explanation.NodePool, explanation.Ready)
+	if explanation.ReadyMessage != "" {
+		fmt.Fprintf(out, " (%s)", explanation.ReadyMessage)
+	}
+	fmt.Fprintf(out, "\nCapacity Type: %s\n", lo.Ternary(explanation.CapacityType != "", explanation.CapacityType, "<none>"))
+	for _, warning := range explanation.Warnings {
+		fmt.Fprintf(out, "Warning: %s\n", warning)
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INSTANCE TYPE\tAMI\tAVAILABLE OFFERINGS\tREJECTED\tMESSAGE")
+	for _, it := range explanation.InstanceTypes {
+		offerings := lo.FilterMap(it.Offerings, func(o explain.Offering, _ int) (string, bool) {
+			return fmt.Sprintf("%s/%s", o.Zone, o.CapacityType), o.Available
+		})
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", it.Name, it.ImageID, strings.Join(offerings, ","), it.RejectReason, it.RejectMessage)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, lt := range explanation.LaunchTemplates {
+		fmt.Fprintf(out, "\nLaunch Template: %s\nAMI: %s\nInstance Types: %s\n", lt.Name, lt.ImageID, strings.Join(lt.InstanceTypes, ", "))
+		if lt.CapacityReservationID != "" {
+			fmt.Fprintf(out, "Capacity Reservation: %s\n", lt.CapacityReservationID)
+		}
+		fmt.Fprintf(out, "User Data:\n%s\n", lt.UserData)
+	}
+	return nil
+}
@@ -465,6 +465,7 @@ func (c *Client) CreateFleet(ctx context.Context, input *ec2.CreateFleetInput, _
 			nil,
 			nil,
 			nil,
+			nil,
 			// TODO: Eventually support different AMIFamilies from userData
 			"al2023",
 			nil,
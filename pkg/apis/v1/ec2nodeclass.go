@@ -54,10 +54,16 @@ type EC2NodeClassSpec struct {
 	// AssociatePublicIPAddress controls if public IP addresses are assigned to instances that are launched with the nodeclass.
 	// +optional
 	AssociatePublicIPAddress *bool `json:"associatePublicIPAddress,omitempty"`
+	// AssumeRole is an IAM role that Karpenter assumes to launch and manage instances for this EC2NodeClass in
+	// another AWS account. Subnets, security groups, AMIs, capacity reservations, and launch templates are resolved
+	// in the account of the role. Instance profiles aren't managed in other accounts, so instanceProfile must name an
+	// instance profile in the account of the role. If not specified, instances are launched in the account of the controller.
+	// +optional
+	AssumeRole *AssumeRole `json:"assumeRole,omitempty"`
 	// AMISelectorTerms is a list of or ami selector terms. The terms are ORed.
 	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id', 'name', 'alias', 'ssmParameter']",rule="self.all(x, has(x.tags) || has(x.id) || has(x.name) || has(x.alias) || has(x.ssmParameter))"
 	// +kubebuilder:validation:XValidation:message="'id' is mutually exclusive, cannot be set with a combination of other fields in amiSelectorTerms",rule="!self.exists(x, has(x.id) && (has(x.alias) || has(x.tags) || has(x.name) || has(x.owner)))"
-	// +kubebuilder:validation:XValidation:message="'alias' is mutually exclusive, cannot be set with a combination of other fields in amiSelectorTerms",rule="!self.exists(x, has(x.alias) && (has(x.id) || has(x.tags) || has(x.name) || has(x.owner)))"
+	// +kubebuilder:validation:XValidation:message="'alias' is mutually exclusive, cannot be set with a combination of other fields in amiSelectorTerms",rule="!self.exists(x, has(x.alias) && (has(x.id) || has(x.tags) || has(x.name) || has(x.owner) || has(x.minAge) || has(x.maxAge) || has(x.excludeDeprecated)))"
 	// +kubebuilder:validation:XValidation:message="'alias' is mutually exclusive, cannot be set with a combination of other amiSelectorTerms",rule="!(self.exists(x, has(x.alias)) && self.size() != 1)"
 	// +kubebuilder:validation:MinItems:=1
 	// +kubebuilder:validation:MaxItems:=30
@@ -69,21 +75,38 @@ type EC2NodeClassSpec struct {
 	// alias is specified, this field is required.
 	// NOTE: We ignore the AMIFamily for hashing here because we hash the AMIFamily dynamically by using the alias using
 	// the AMIFamily() helper function
-	// +kubebuilder:validation:Enum:={AL2,AL2023,Bottlerocket,Custom,Windows2019,Windows2022}
+	// +kubebuilder:validation:Enum:={AL2,AL2023,Bottlerocket,Custom,Ubuntu,Windows2019,Windows2022,Windows2025}
 	// +optional
 	AMIFamily *string `json:"amiFamily,omitempty" hash:"ignore"`
+	// AMIRolloutPolicy controls how newly discovered AMIs are rolled out. When set, a newly discovered AMI is a candidate
+	// which is only used for a share of new launches. Once the candidate has soaked without node health failures, it's
+	// promoted to status.amis and existing nodes drift to it. When unset, newly discovered AMIs are promoted immediately.
+	// +optional
+	AMIRolloutPolicy *AMIRolloutPolicy `json:"amiRolloutPolicy,omitempty" hash:"ignore"`
 	// UserData to be applied to the provisioned nodes.
 	// It must be in the appropriate format based on the AMIFamily in use. Karpenter will merge certain fields into
 	// this UserData to ensure nodes are being provisioned with the correct configuration.
 	// +optional
 	UserData *string `json:"userData,omitempty"`
-	// Role is the AWS identity that nodes use. This field is immutable.
+	// TemplateUserData renders userData as a Go template before it's merged with the configuration generated by Karpenter.
+	// The template can reference node and instance context, such as {{ .NodePoolName }} and {{ .AMIID }}.
+	// +optional
+	TemplateUserData *bool `json:"templateUserData,omitempty"`
+	// Bottlerocket configures typed Bottlerocket settings, which are validated at admission rather than when nodes boot.
+	// These settings take precedence over the same settings in userData, and are themselves overridden by settings that
+	// Karpenter generates. This field may only be set when using the Bottlerocket AMI family.
+	// +optional
+	Bottlerocket *BottlerocketSettings `json:"bottlerocket,omitempty"`
+	// Registries configures registry mirrors and image credential provider plugins used when nodes pull images.
+	// These are rendered into the bootstrap configuration of each AMI family, and are not applied for the Custom AMI family.
+	// +optional
+	Registries *RegistryConfiguration `json:"registries,omitempty"`
+	// Role is the AWS identity that nodes use.
 	// This field is mutually exclusive from instanceProfile.
-	// Marking this field as immutable avoids concerns around terminating managed instance profiles from running instances.
-	// This field may be made mutable in the future, assuming the correct garbage collection and drift handling is implemented
-	// for the old instance profiles on an update.
+	// Changing the role creates a new instance profile rather than modifying the one used by running instances.
+	// Nodes launched with the previous instance profile are drifted, and the previous instance profile is deleted
+	// once no instances reference it.
 	// +kubebuilder:validation:XValidation:rule="self != ''",message="role cannot be empty"
-	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="immutable field changed"
 	// +optional
 	Role string `json:"role,omitempty"`
 	// InstanceProfile is the AWS entity that instances use.
@@ -100,6 +123,7 @@ type EC2NodeClassSpec struct {
 	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.sh/nodepool",rule="self.all(k, k != 'karpenter.sh/nodepool')"
 	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.sh/nodeclaim",rule="self.all(k, k !='karpenter.sh/nodeclaim')"
 	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.k8s.aws/ec2nodeclass",rule="self.all(k, k !='karpenter.k8s.aws/ec2nodeclass')"
+	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.k8s.aws/assume-role-arn",rule="self.all(k, k !='karpenter.k8s.aws/assume-role-arn')"
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
 	// Kubelet defines args to be used when configuring kubelet on provisioned nodes.
@@ -108,6 +132,8 @@ type EC2NodeClassSpec struct {
 	// +kubebuilder:validation:XValidation:message="imageGCHighThresholdPercent must be greater than imageGCLowThresholdPercent",rule="has(self.imageGCHighThresholdPercent) && has(self.imageGCLowThresholdPercent) ?  self.imageGCHighThresholdPercent > self.imageGCLowThresholdPercent  : true"
 	// +kubebuilder:validation:XValidation:message="evictionSoft OwnerKey does not have a matching evictionSoftGracePeriod",rule="has(self.evictionSoft) ? self.evictionSoft.all(e, (e in self.evictionSoftGracePeriod)):true"
 	// +kubebuilder:validation:XValidation:message="evictionSoftGracePeriod OwnerKey does not have a matching evictionSoft",rule="has(self.evictionSoftGracePeriod) ? self.evictionSoftGracePeriod.all(e, (e in self.evictionSoft)):true"
+	// +kubebuilder:validation:XValidation:message="shutdownGracePeriodCriticalPods must be less than or equal to shutdownGracePeriod",rule="has(self.shutdownGracePeriodCriticalPods) ? has(self.shutdownGracePeriod) && duration(self.shutdownGracePeriodCriticalPods) <= duration(self.shutdownGracePeriod) : true"
+	// +kubebuilder:validation:XValidation:message="maxParallelImagePulls may only be set when serializeImagePulls is false",rule="has(self.maxParallelImagePulls) ? has(self.serializeImagePulls) && !self.serializeImagePulls : true"
 	// +optional
 	Kubelet *KubeletConfiguration `json:"kubelet,omitempty"`
 	// BlockDeviceMappings to be applied to provisioned nodes.
@@ -121,6 +147,15 @@ type EC2NodeClassSpec struct {
 	// DetailedMonitoring controls if detailed monitoring is enabled for instances that are launched
 	// +optional
 	DetailedMonitoring *bool `json:"detailedMonitoring,omitempty"`
+	// CPUOptions configures the processor of instances that are launched with this nodeclass.
+	// Instance types which don't support the requested options are not considered for launch.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+	// InstanceTypeSelector restricts the instance types that can be launched with this nodeclass, for every NodePool
+	// that references it. Instance types which don't match the selector are not considered for launch, and nodes with
+	// those instance types are drifted.
+	// +optional
+	InstanceTypeSelector *InstanceTypeSelector `json:"instanceTypeSelector,omitempty" hash:"ignore"`
 	// MetadataOptions for the generated launch template of provisioned nodes.
 	//
 	// This specifies the exposure of the Instance Metadata Service to
@@ -196,16 +231,19 @@ type CapacityReservationSelectorTerm struct {
 
 // AMISelectorTerm defines selection logic for an ami used by Karpenter to launch nodes.
 // If multiple fields are used for selection, the requirements are ANDed.
+// +kubebuilder:validation:XValidation:message="'minAge' must be less than 'maxAge'",rule="has(self.minAge) && has(self.maxAge) ? duration(self.minAge) < duration(self.maxAge) : true"
 type AMISelectorTerm struct {
 	// Alias specifies which EKS optimized AMI to select.
 	// Each alias consists of a family and an AMI version, specified as "family@version".
-	// Valid families include: al2, al2023, bottlerocket, windows2019, and windows2022.
+	// Valid families include: al2, al2023, bottlerocket, ubuntu, windows2019, windows2022, and windows2025.
 	// The version can either be pinned to a specific AMI release, with that AMIs version format (ex: "al2023@v20240625" or "bottlerocket@v1.10.0").
+	// The Windows families are pinned using the date suffix of the AMI name (ex: "windows2022@2024.09.10").
+	// The Ubuntu family selects a release and is optionally pinned to an image serial (ex: "ubuntu@24.04" or "ubuntu@24.04-20240701").
 	// The version can also be set to "latest" for any family. Setting the version to latest will result in drift when a new AMI is released. This is **not** recommended for production environments.
-	// Note: The Windows families do **not** support version pinning, and only latest may be used.
 	// +kubebuilder:validation:XValidation:message="'alias' is improperly formatted, must match the format 'family@version'",rule="self.matches('^[a-zA-Z0-9]+@.+$')"
-	// +kubebuilder:validation:XValidation:message="family is not supported, must be one of the following: 'al2', 'al2023', 'bottlerocket', 'windows2019', 'windows2022'",rule="self.split('@')[0] in ['al2','al2023','bottlerocket','windows2019','windows2022']"
-	// +kubebuilder:validation:XValidation:message="windows families may only specify version 'latest'",rule="self.split('@')[0] in ['windows2019','windows2022'] ? self.split('@')[1] == 'latest' : true"
+	// +kubebuilder:validation:XValidation:message="family is not supported, must be one of the following: 'al2', 'al2023', 'bottlerocket', 'ubuntu', 'windows2019', 'windows2022', 'windows2025'",rule="self.split('@')[0] in ['al2','al2023','bottlerocket','ubuntu','windows2019','windows2022','windows2025']"
+	// +kubebuilder:validation:XValidation:message="windows families may only specify version 'latest' or a date in the format 'YYYY.MM.DD'",rule="self.split('@')[0] in ['windows2019','windows2022','windows2025'] ? self.split('@')[1].matches('^(latest|[0-9]{4}[.][0-9]{2}[.][0-9]{2})$') : true"
+	// +kubebuilder:validation:XValidation:message="ubuntu may only specify version 'latest', a release (ex: '24.04'), an image serial (ex: '20240701'), or both (ex: '24.04-20240701')",rule="self.split('@')[0] == 'ubuntu' ? self.split('@')[1].matches('^(latest|[0-9]{2}[.][0-9]{2}|([0-9]{2}[.][0-9]{2}-)?[0-9]{8}([.][0-9]+)?)$') : true"
 	// +kubebuilder:validation:MaxLength=30
 	// +optional
 	Alias string `json:"alias,omitempty"`
@@ -230,6 +268,53 @@ type AMISelectorTerm struct {
 	//SSMParameter is the name (or ARN) of the SSM parameter containing the Image ID.
 	// +optional
 	SSMParameter string `json:"ssmParameter,omitempty"`
+	// MinAge is the minimum time since an AMI's creation date before it can be selected.
+	// AMIs created more recently are ignored until they've aged in (ex: "168h" for a one week bake time).
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	MinAge *metav1.Duration `json:"minAge,omitempty"`
+	// MaxAge is the maximum time since an AMI's creation date for it to be selected.
+	// AMIs older than this are ignored, as are AMIs without a creation date.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// ExcludeDeprecated prevents AMIs past their deprecation time from being selected.
+	// By default, deprecated AMIs are only selected when no non-deprecated AMI matches.
+	// +optional
+	ExcludeDeprecated *bool `json:"excludeDeprecated,omitempty"`
+}
+
+// AssumeRole defines the IAM role that Karpenter assumes to manage instances in another AWS account.
+type AssumeRole struct {
+	// RoleARN is the ARN of the IAM role to assume. The role must trust the role of the Karpenter controller.
+	// +kubebuilder:validation:Pattern:="^arn:aws[a-z-]*:iam::[0-9]{12}:role/.+$"
+	// +required
+	RoleARN string `json:"roleARN"`
+	// ExternalID is passed when assuming the role, for roles whose trust policy requires one.
+	// +kubebuilder:validation:MinLength:=2
+	// +kubebuilder:validation:MaxLength:=1224
+	// +optional
+	ExternalID *string `json:"externalID,omitempty"`
+}
+
+// AMIRolloutPolicy defines how candidate AMIs are canaried before they become the drift target.
+type AMIRolloutPolicy struct {
+	// CanaryPercentage is the percentage of new launches which use candidate AMIs while they soak.
+	// The remaining launches use the stable AMIs in status.amis. NodeClaims are assigned by a hash of their name, so
+	// a NodeClaim always launches with the same AMIs. Defaults to 100, using candidate AMIs for all new launches.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +kubebuilder:default:=100
+	// +optional
+	CanaryPercentage *int32 `json:"canaryPercentage,omitempty"`
+	// SoakDuration is how long candidate AMIs must run without node health failures before they're promoted.
+	// A node health failure restarts the soak period.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +required
+	SoakDuration metav1.Duration `json:"soakDuration"`
 }
 
 // KubeletConfiguration defines args to be used when configuring kubelet on provisioned nodes.
@@ -299,6 +384,387 @@ type KubeletConfiguration struct {
 	// CPUCFSQuota enables CPU CFS quota enforcement for containers that specify CPU limits.
 	// +optional
 	CPUCFSQuota *bool `json:"cpuCFSQuota,omitempty"`
+	// CPUManagerPolicy is the name of the policy to use for the CPU manager. The "static" policy grants
+	// containers in Guaranteed pods with integer CPU requests exclusive access to CPUs on the node.
+	// +kubebuilder:validation:Enum:={none,static}
+	// +optional
+	CPUManagerPolicy *string `json:"cpuManagerPolicy,omitempty"`
+	// CPUManagerReconcilePeriod is the reconciliation period for the CPU manager.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	CPUManagerReconcilePeriod *metav1.Duration `json:"cpuManagerReconcilePeriod,omitempty"`
+	// TopologyManagerPolicy is the name of the topology manager policy to use.
+	// +kubebuilder:validation:Enum:={none,best-effort,restricted,single-numa-node}
+	// +optional
+	TopologyManagerPolicy *string `json:"topologyManagerPolicy,omitempty"`
+	// TopologyManagerScope is the scope at which topology hints are generated and applied.
+	// +kubebuilder:validation:Enum:={container,pod}
+	// +optional
+	TopologyManagerScope *string `json:"topologyManagerScope,omitempty"`
+	// ShutdownGracePeriod is the total duration that the node delays shutdown by to allow pods to terminate.
+	// This is not supported by the AL2, Ubuntu, or Windows AMI families.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	ShutdownGracePeriod *metav1.Duration `json:"shutdownGracePeriod,omitempty"`
+	// ShutdownGracePeriodCriticalPods is the portion of ShutdownGracePeriod reserved for terminating critical pods.
+	// When specified, the value must be less than or equal to ShutdownGracePeriod.
+	// This is not supported by the AL2, Ubuntu, or Windows AMI families.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	ShutdownGracePeriodCriticalPods *metav1.Duration `json:"shutdownGracePeriodCriticalPods,omitempty"`
+	// ContainerLogMaxSize is the maximum size (e.g. 10Mi) of a container log file before it is rotated.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(Ki|Mi|Gi)$`
+	// +optional
+	ContainerLogMaxSize *string `json:"containerLogMaxSize,omitempty"`
+	// ContainerLogMaxFiles is the maximum number of container log files that can be present for a container.
+	// +kubebuilder:validation:Minimum:=2
+	// +optional
+	ContainerLogMaxFiles *int32 `json:"containerLogMaxFiles,omitempty"`
+	// RegistryPullQPS is the limit of registry pulls per second. Setting this to 0 means no limit.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	RegistryPullQPS *int32 `json:"registryPullQPS,omitempty"`
+	// RegistryBurst is the maximum size of bursty pulls. It is only used when RegistryPullQPS is greater than 0.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	RegistryBurst *int32 `json:"registryBurst,omitempty"`
+	// SerializeImagePulls pulls images one at a time when enabled.
+	// This is not supported by the Bottlerocket AMI family.
+	// +optional
+	SerializeImagePulls *bool `json:"serializeImagePulls,omitempty"`
+	// MaxParallelImagePulls is the maximum number of image pulls in parallel. It may only be set when
+	// SerializeImagePulls is false. This is not supported by the AL2, Bottlerocket, Ubuntu, or Windows AMI families.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxParallelImagePulls *int32 `json:"maxParallelImagePulls,omitempty"`
+	// AllowedUnsafeSysctls is a list of unsafe sysctls, or sysctl patterns ending in '*', that pods may set.
+	// This is not supported by the Windows AMI families.
+	// +kubebuilder:validation:XValidation:message="allowedUnsafeSysctls entries cannot be empty",rule="self.all(x, x != '')"
+	// +optional
+	AllowedUnsafeSysctls []string `json:"allowedUnsafeSysctls,omitempty"`
+	// InstanceTypeOverrides override maxPods, kubeReserved, and systemReserved for the instance types they match. The
+	// first override matching an instance type is applied, and each value it sets replaces the value configured above.
+	// +kubebuilder:validation:MaxItems:=20
+	// +optional
+	InstanceTypeOverrides []KubeletInstanceTypeOverride `json:"instanceTypeOverrides,omitempty"`
+}
+
+// KubeletInstanceTypeOverride overrides kubelet configuration for the instance types it matches. An instance type
+// matches when it satisfies every selector which is set.
+// +kubebuilder:validation:XValidation:message="expected at least one of instanceFamilies, instanceSizes, minVCPU, maxVCPU, minMemory, or maxMemory to be set",rule="has(self.instanceFamilies) || has(self.instanceSizes) || has(self.minVCPU) || has(self.maxVCPU) || has(self.minMemory) || has(self.maxMemory)"
+// +kubebuilder:validation:XValidation:message="expected at least one of maxPods, kubeReserved, or systemReserved to be set",rule="has(self.maxPods) || has(self.kubeReserved) || has(self.systemReserved)"
+// +kubebuilder:validation:XValidation:message="minVCPU must be less than or equal to maxVCPU",rule="!has(self.minVCPU) || !has(self.maxVCPU) || self.minVCPU <= self.maxVCPU"
+type KubeletInstanceTypeOverride struct {
+	// InstanceFamilies matches instance types in any of the given families (e.g. m7i).
+	// +kubebuilder:validation:MinItems:=1
+	// +optional
+	InstanceFamilies []string `json:"instanceFamilies,omitempty"`
+	// InstanceSizes matches instance types of any of the given sizes (e.g. 48xlarge or metal).
+	// +kubebuilder:validation:MinItems:=1
+	// +optional
+	InstanceSizes []string `json:"instanceSizes,omitempty"`
+	// MinVCPU matches instance types with at least this many vCPUs.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MinVCPU *int32 `json:"minVCPU,omitempty"`
+	// MaxVCPU matches instance types with at most this many vCPUs.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxVCPU *int32 `json:"maxVCPU,omitempty"`
+	// MinMemory matches instance types with at least this much memory.
+	// +kubebuilder:validation:Pattern:="^[0-9]+(Mi|Gi|Ti|M|G|T)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	MinMemory *resource.Quantity `json:"minMemory,omitempty" hash:"string"`
+	// MaxMemory matches instance types with at most this much memory.
+	// +kubebuilder:validation:Pattern:="^[0-9]+(Mi|Gi|Ti|M|G|T)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	MaxMemory *resource.Quantity `json:"maxMemory,omitempty" hash:"string"`
+	// MaxPods overrides the maximum number of pods that can run on the matching instance types.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	MaxPods *int32 `json:"maxPods,omitempty"`
+	// SystemReserved overrides the resources reserved for OS system daemons and kernel memory on the matching instance types.
+	// +kubebuilder:validation:XValidation:message="valid keys for systemReserved are ['cpu','memory','ephemeral-storage','pid']",rule="self.all(x, x=='cpu' || x=='memory' || x=='ephemeral-storage' || x=='pid')"
+	// +kubebuilder:validation:XValidation:message="systemReserved value cannot be a negative resource quantity",rule="self.all(x, !self[x].startsWith('-'))"
+	// +optional
+	SystemReserved map[string]string `json:"systemReserved,omitempty"`
+	// KubeReserved overrides the resources reserved for Kubernetes system components on the matching instance types.
+	// +kubebuilder:validation:XValidation:message="valid keys for kubeReserved are ['cpu','memory','ephemeral-storage','pid']",rule="self.all(x, x=='cpu' || x=='memory' || x=='ephemeral-storage' || x=='pid')"
+	// +kubebuilder:validation:XValidation:message="kubeReserved value cannot be a negative resource quantity",rule="self.all(x, !self[x].startsWith('-'))"
+	// +optional
+	KubeReserved map[string]string `json:"kubeReserved,omitempty"`
+}
+
+// Matches returns true if the override's selectors are satisfied by an instance type with the given family, size,
+// vCPUs, and memory.
+func (in *KubeletInstanceTypeOverride) Matches(family, size string, vcpus int64, memoryMiB int64) bool {
+	if len(in.InstanceFamilies) != 0 && !lo.Contains(in.InstanceFamilies, family) {
+		return false
+	}
+	if len(in.InstanceSizes) != 0 && !lo.Contains(in.InstanceSizes, size) {
+		return false
+	}
+	if in.MinVCPU != nil && vcpus < int64(*in.MinVCPU) {
+		return false
+	}
+	if in.MaxVCPU != nil && vcpus > int64(*in.MaxVCPU) {
+		return false
+	}
+	memory := memoryMiB * 1024 * 1024
+	if in.MinMemory != nil && memory < in.MinMemory.Value() {
+		return false
+	}
+	if in.MaxMemory != nil && memory > in.MaxMemory.Value() {
+		return false
+	}
+	return true
+}
+
+// ForInstanceType returns the kubelet configuration for an instance type with the given family, size, vCPUs, and
+// memory, applying the first matching instance type override. The returned configuration is a copy, and its instance
+// type overrides are cleared so they aren't rendered into user data.
+func (in *KubeletConfiguration) ForInstanceType(family, size string, vcpus int64, memoryMiB int64) *KubeletConfiguration {
+	if in == nil {
+		return nil
+	}
+	resolved := in.DeepCopy()
+	resolved.InstanceTypeOverrides = nil
+	override, ok := lo.Find(in.InstanceTypeOverrides, func(o KubeletInstanceTypeOverride) bool {
+		return o.Matches(family, size, vcpus, memoryMiB)
+	})
+	if !ok {
+		return resolved
+	}
+	if override.MaxPods != nil {
+		resolved.MaxPods = lo.ToPtr(*override.MaxPods)
+	}
+	if override.SystemReserved != nil {
+		resolved.SystemReserved = lo.Assign(override.SystemReserved)
+	}
+	if override.KubeReserved != nil {
+		resolved.KubeReserved = lo.Assign(override.KubeReserved)
+	}
+	return resolved
+}
+
+// InstanceTypeOverridesHash hashes the instance type overrides as an ordered list, since the first override matching an
+// instance type is applied. Hashes of the kubelet configuration treat slices as sets, so they don't change when the
+// overrides are reordered.
+func (in *KubeletConfiguration) InstanceTypeOverridesHash() uint64 {
+	if in == nil {
+		return 0
+	}
+	return lo.Must(hashstructure.Hash(lo.Map(in.InstanceTypeOverrides, func(o KubeletInstanceTypeOverride, _ int) uint64 {
+		return lo.Must(hashstructure.Hash(o, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true}))
+	}), hashstructure.FormatV2, nil))
+}
+
+// BottlerocketSettings is a typed subset of the Bottlerocket settings API, see more here
+// https://bottlerocket.dev/en/os/latest/api/settings/
+type BottlerocketSettings struct {
+	// Kernel configures the Bottlerocket kernel.
+	// +optional
+	Kernel *BottlerocketKernel `json:"kernel,omitempty"`
+	// ContainerRegistry configures how container images are pulled.
+	// +optional
+	ContainerRegistry *BottlerocketContainerRegistry `json:"containerRegistry,omitempty"`
+	// BootstrapContainers are containers which run before the kubelet starts, keyed by container name.
+	// +kubebuilder:validation:XValidation:message="bootstrap container names may only contain alphanumeric characters, '-' and '_'",rule="self.all(k, k.matches('^[a-zA-Z0-9_-]+$'))"
+	// +kubebuilder:validation:MaxProperties:=10
+	// +optional
+	BootstrapContainers map[string]BottlerocketBootstrapContainer `json:"bootstrapContainers,omitempty"`
+	// HostContainers are long-running containers outside of Kubernetes, keyed by container name.
+	// The "admin" and "control" host containers are provided by Bottlerocket and don't require a source.
+	// +kubebuilder:validation:XValidation:message="host container names may only contain alphanumeric characters, '-' and '_'",rule="self.all(k, k.matches('^[a-zA-Z0-9_-]+$'))"
+	// +kubebuilder:validation:XValidation:message="source is required for host containers other than 'admin' and 'control'",rule="self.all(k, k in ['admin', 'control'] || has(self[k].source))"
+	// +kubebuilder:validation:MaxProperties:=10
+	// +optional
+	HostContainers map[string]BottlerocketHostContainer `json:"hostContainers,omitempty"`
+	// NTP configures the time servers used by the host.
+	// +optional
+	NTP *BottlerocketNTP `json:"ntp,omitempty"`
+}
+
+// BottlerocketKernel configures the Bottlerocket kernel.
+type BottlerocketKernel struct {
+	// Sysctl is a map of kernel parameters to their values, set with sysctl at boot (ex: "net.core.somaxconn": "1024").
+	// +kubebuilder:validation:XValidation:message="sysctl keys must be dot or slash separated kernel parameter names",rule="self.all(k, k.matches('^[a-z0-9_]+([./][a-z0-9_-]+)+$'))"
+	// +kubebuilder:validation:XValidation:message="sysctl values cannot be empty",rule="self.all(k, self[k] != '')"
+	// +optional
+	Sysctl map[string]string `json:"sysctl,omitempty"`
+}
+
+// BottlerocketContainerRegistry configures how container images are pulled.
+type BottlerocketContainerRegistry struct {
+	// Mirrors are the registry mirrors used when pulling images.
+	// +kubebuilder:validation:XValidation:message="registries must be unique",rule="self.all(x, self.exists_one(y, y.registry == x.registry))"
+	// +kubebuilder:validation:MaxItems:=20
+	// +optional
+	Mirrors []BottlerocketRegistryMirror `json:"mirrors,omitempty"`
+}
+
+// BottlerocketRegistryMirror configures the mirror endpoints for a registry.
+type BottlerocketRegistryMirror struct {
+	// Registry is the registry being mirrored (ex: "docker.io"). The value "*" mirrors all registries.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Registry string `json:"registry"`
+	// Endpoints are the mirror endpoints for the registry, tried in order.
+	// +kubebuilder:validation:XValidation:message="endpoints must be http or https URLs",rule="self.all(x, x.matches('^https?://'))"
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:validation:MaxItems:=10
+	// +required
+	Endpoints []string `json:"endpoints"`
+}
+
+// BottlerocketBootstrapContainer is a container which runs before the kubelet starts.
+type BottlerocketBootstrapContainer struct {
+	// Source is the URI of the container image.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Source string `json:"source"`
+	// Mode controls when the container runs. Unlike Bottlerocket, which defaults to "off", this defaults to "always".
+	// +kubebuilder:validation:Enum:={always,once,off}
+	// +optional
+	Mode *string `json:"mode,omitempty"`
+	// UserData is base64 encoded data made available to the container.
+	// +optional
+	UserData *string `json:"userData,omitempty"`
+	// Essential fails the boot when the container exits unsuccessfully.
+	// +optional
+	Essential *bool `json:"essential,omitempty"`
+}
+
+// BottlerocketHostContainer is a long-running container outside of Kubernetes.
+type BottlerocketHostContainer struct {
+	// Source is the URI of the container image.
+	// +kubebuilder:validation:MinLength:=1
+	// +optional
+	Source *string `json:"source,omitempty"`
+	// Enabled controls if the container runs.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Superpowered grants the container additional privileges on the host.
+	// +optional
+	Superpowered *bool `json:"superpowered,omitempty"`
+	// UserData is base64 encoded data made available to the container.
+	// +optional
+	UserData *string `json:"userData,omitempty"`
+}
+
+// BottlerocketNTP configures the time servers used by the host.
+type BottlerocketNTP struct {
+	// TimeServers are the NTP servers used by the host.
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:validation:MaxItems:=10
+	// +required
+	TimeServers []string `json:"timeServers"`
+}
+
+// RegistryConfiguration configures how nodes pull container images.
+type RegistryConfiguration struct {
+	// Mirrors are the registry mirrors used when pulling images.
+	// +kubebuilder:validation:XValidation:message="registries must be unique",rule="self.all(x, self.exists_one(y, y.registry == x.registry))"
+	// +kubebuilder:validation:MaxItems:=20
+	// +optional
+	Mirrors []RegistryMirror `json:"mirrors,omitempty"`
+	// CredentialProviders are the kubelet image credential provider plugins used when pulling images. Plugin binaries
+	// must already be present on the AMI. The ECR credential provider is always configured, and may be overridden
+	// by specifying a provider named "ecr-credential-provider".
+	// +kubebuilder:validation:XValidation:message="credential provider names must be unique",rule="self.all(x, self.exists_one(y, y.name == x.name))"
+	// +kubebuilder:validation:MaxItems:=10
+	// +optional
+	CredentialProviders []CredentialProvider `json:"credentialProviders,omitempty"`
+}
+
+// RegistryMirror configures the mirror endpoints for a registry.
+type RegistryMirror struct {
+	// Registry is the registry being mirrored (ex: "docker.io"). The value "*" mirrors all registries.
+	// +kubebuilder:validation:Pattern=`^(\*|[a-zA-Z0-9.-]+(:[0-9]+)?)$`
+	// +required
+	Registry string `json:"registry"`
+	// Endpoints are the mirror endpoints for the registry, tried in order before falling back to the registry itself.
+	// +kubebuilder:validation:XValidation:message="endpoints must be http or https URLs",rule="self.all(x, x.matches('^https?://'))"
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:validation:MaxItems:=10
+	// +required
+	Endpoints []string `json:"endpoints"`
+}
+
+// CredentialProvider configures a kubelet image credential provider plugin.
+// https://kubernetes.io/docs/tasks/administer-cluster/kubelet-credential-provider/
+type CredentialProvider struct {
+	// Name is the name of the credential provider plugin binary.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	// +required
+	Name string `json:"name"`
+	// MatchImages are the image patterns (ex: "*.registry.example.com") for which the plugin provides credentials.
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:validation:MaxItems:=20
+	// +required
+	MatchImages []string `json:"matchImages"`
+	// DefaultCacheDuration is how long credentials are cached when the plugin doesn't specify a cache duration.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	DefaultCacheDuration *metav1.Duration `json:"defaultCacheDuration,omitempty"`
+	// Env are the environment variables passed to the plugin.
+	// +kubebuilder:validation:XValidation:message="environment variable names must be valid identifiers",rule="self.all(k, k.matches('^[a-zA-Z_][a-zA-Z0-9_]*$'))"
+	// +optional
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// CPUOptions contains parameters for configuring the processor of provisioned EC2 nodes.
+// For more information, see Optimize CPU options
+// (https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/instance-optimize-cpu.html)
+// in the Amazon Elastic Compute Cloud User Guide.
+type CPUOptions struct {
+	// CoreCount is the number of CPU cores for the instance. If not specified, the default core count
+	// of each instance type is used. Only instance types which support the core count are considered for launch.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	CoreCount *int32 `json:"coreCount,omitempty"`
+	// ThreadsPerCore is the number of threads per CPU core. Set to 1 to disable simultaneous multithreading.
+	// If not specified, the default number of threads per core of each instance type is used.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=2
+	// +optional
+	ThreadsPerCore *int32 `json:"threadsPerCore,omitempty"`
+	// AMDSEVSNP controls if AMD SEV-SNP is enabled for the instance. When enabled, only instance types which
+	// support AMD SEV-SNP are considered for launch.
+	// +kubebuilder:validation:Enum:={enabled,disabled}
+	// +optional
+	AMDSEVSNP *string `json:"amdSevSnp,omitempty"`
+}
+
+// InstanceTypeSelector restricts the instance types that can be launched with an EC2NodeClass. Instance types must
+// satisfy every field of the selector.
+type InstanceTypeSelector struct {
+	// Allow is a list of instance type name patterns, such as "m5.*" or "c6i.large". If specified, only instance types
+	// which match one of the patterns are allowed.
+	// +kubebuilder:validation:XValidation:message="patterns may only contain lowercase letters, digits, '.', '-', '*' and '?'",rule="self.all(x, x.matches('^[a-z0-9.*?-]+$'))"
+	// +kubebuilder:validation:MaxItems:=100
+	// +optional
+	Allow []string `json:"allow,omitempty"`
+	// Deny is a list of instance type name patterns, such as "*.metal" or "t2.*". Instance types which match one of
+	// the patterns aren't allowed, even if they also match allow.
+	// +kubebuilder:validation:XValidation:message="patterns may only contain lowercase letters, digits, '.', '-', '*' and '?'",rule="self.all(x, x.matches('^[a-z0-9.*?-]+$'))"
+	// +kubebuilder:validation:MaxItems:=100
+	// +optional
+	Deny []string `json:"deny,omitempty"`
+	// ExcludePreviousGeneration prevents instance types which EC2 doesn't consider current generation from being selected.
+	// +optional
+	ExcludePreviousGeneration *bool `json:"excludePreviousGeneration,omitempty"`
+	// ExcludeBareMetal prevents bare metal instance types from being selected.
+	// +optional
+	ExcludeBareMetal *bool `json:"excludeBareMetal,omitempty"`
 }
 
 // MetadataOptions contains parameters for specifying the exposure of the
@@ -351,12 +817,13 @@ type MetadataOptions struct {
 	HTTPTokens *string `json:"httpTokens,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:message="volumeSizePolicy may only be set for the root volume",rule="!has(self.ebs) || !has(self.ebs.volumeSizePolicy) || (has(self.rootVolume) && self.rootVolume)"
 type BlockDeviceMapping struct {
 	// The device name (for example, /dev/sdh or xvdh).
 	// +optional
 	DeviceName *string `json:"deviceName,omitempty"`
 	// EBS contains parameters used to automatically set up EBS volumes when an instance is launched.
-	// +kubebuilder:validation:XValidation:message="snapshotID or volumeSize must be defined",rule="has(self.snapshotID) || has(self.volumeSize)"
+	// +kubebuilder:validation:XValidation:message="snapshotID, volumeSize, or volumeSizePolicy must be defined",rule="has(self.snapshotID) || has(self.volumeSize) || has(self.volumeSizePolicy)"
 	// +kubebuilder:validation:XValidation:message="snapshotID must be set when volumeInitializationRate is set",rule="!has(self.volumeInitializationRate) || (has(self.snapshotID) && self.snapshotID != '')"
 	// +optional
 	EBS *BlockDevice `json:"ebs,omitempty"`
@@ -364,6 +831,16 @@ type BlockDeviceMapping struct {
 	// configure at most one root volume in BlockDeviceMappings.
 	// +optional
 	RootVolume bool `json:"rootVolume,omitempty"`
+	// Tags to be applied to the EBS volume, in addition to the tags applied to all of the instance's volumes.
+	// +kubebuilder:validation:XValidation:message="empty tag keys aren't supported",rule="self.all(k, k != '')"
+	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching eks:eks-cluster-name",rule="self.all(k, k !='eks:eks-cluster-name')"
+	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching kubernetes.io/cluster/",rule="self.all(k, !k.startsWith('kubernetes.io/cluster') )"
+	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.sh/nodepool",rule="self.all(k, k != 'karpenter.sh/nodepool')"
+	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.sh/nodeclaim",rule="self.all(k, k !='karpenter.sh/nodeclaim')"
+	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.k8s.aws/ec2nodeclass",rule="self.all(k, k !='karpenter.k8s.aws/ec2nodeclass')"
+	// +kubebuilder:validation:MaxProperties:=50
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 type BlockDevice struct {
@@ -433,6 +910,10 @@ type BlockDevice struct {
 	// +kubebuilder:validation:Type:=string
 	// +optional
 	VolumeSize *resource.Quantity `json:"volumeSize,omitempty" hash:"string"`
+	// VolumeSizePolicy scales the size of the root volume with the instance type it's attached to. When set, volumeSize
+	// is used as a base size which the scaled size is added to.
+	// +optional
+	VolumeSizePolicy *VolumeSizePolicy `json:"volumeSizePolicy,omitempty"`
 	// VolumeType of the block device.
 	// For more information, see Amazon EBS volume types (https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/EBSVolumeTypes.html)
 	// in the Amazon Elastic Compute Cloud User Guide.
@@ -441,8 +922,41 @@ type BlockDevice struct {
 	VolumeType *string `json:"volumeType,omitempty"`
 }
 
+// VolumeSizePolicy scales a volume's size with the vCPUs and memory of an instance type. The resolved size is
+// rounded up to the nearest Gi.
+// +kubebuilder:validation:XValidation:message="perVCPU or perGiBMemory must be defined",rule="has(self.perVCPU) || has(self.perGiBMemory)"
+// +kubebuilder:validation:XValidation:message="min must be less than or equal to max",rule="!has(self.min) || !has(self.max) || int(self.min.find('^[0-9]+')) * (self.min.endsWith('Ti') ? 1099511627776 : self.min.endsWith('Gi') ? 1073741824 : self.min.endsWith('T') ? 1000000000000 : 1000000000) <= int(self.max.find('^[0-9]+')) * (self.max.endsWith('Ti') ? 1099511627776 : self.max.endsWith('Gi') ? 1073741824 : self.max.endsWith('T') ? 1000000000000 : 1000000000)"
+type VolumeSizePolicy struct {
+	// PerVCPU is the size added to the volume for each vCPU of the instance type.
+	// +kubebuilder:validation:Pattern:="^[0-9]+(Mi|Gi|M|G)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	PerVCPU *resource.Quantity `json:"perVCPU,omitempty" hash:"string"`
+	// PerGiBMemory is the size added to the volume for each GiB of memory of the instance type.
+	// +kubebuilder:validation:Pattern:="^[0-9]+(Mi|Gi|M|G)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	PerGiBMemory *resource.Quantity `json:"perGiBMemory,omitempty" hash:"string"`
+	// Min is the minimum size of the volume.
+	// +kubebuilder:validation:MaxLength:=8
+	// +kubebuilder:validation:Pattern:="^((?:[1-9][0-9]{0,3}|[1-4][0-9]{4}|[5][0-8][0-9]{3}|59000)Gi|(?:[1-9][0-9]{0,3}|[1-5][0-9]{4}|[6][0-3][0-9]{3}|64000)G|([1-9]||[1-5][0-7]|58)Ti|([1-9]||[1-5][0-9]|6[0-3]|64)T)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	Min *resource.Quantity `json:"min,omitempty" hash:"string"`
+	// Max is the maximum size of the volume.
+	// +kubebuilder:validation:MaxLength:=8
+	// +kubebuilder:validation:Pattern:="^((?:[1-9][0-9]{0,3}|[1-4][0-9]{4}|[5][0-8][0-9]{3}|59000)Gi|(?:[1-9][0-9]{0,3}|[1-5][0-9]{4}|[6][0-3][0-9]{3}|64000)G|([1-9]||[1-5][0-7]|58)Ti|([1-9]||[1-5][0-9]|6[0-3]|64)T)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	Max *resource.Quantity `json:"max,omitempty" hash:"string"`
+}
+
 // InstanceStorePolicy enumerates options for configuring instance store disks.
-// +kubebuilder:validation:Enum={RAID0}
+// +kubebuilder:validation:Enum={RAID0,RAID10,Mount,None}
 type InstanceStorePolicy string
 
 const (
@@ -451,6 +965,15 @@ const (
 	// ephemeral storage for more and faster node ephemeral-storage. The node's ephemeral storage can be shared among
 	// pods that request ephemeral storage and container images that are downloaded to the node.
 	InstanceStorePolicyRAID0 InstanceStorePolicy = "RAID0"
+	// InstanceStorePolicyRAID10 configures a RAID-10 array that includes all ephemeral NVMe instance storage disks,
+	// which is used in the same way as InstanceStorePolicyRAID0. Half of the total disk capacity is usable, in exchange
+	// for tolerating the loss of a disk.
+	InstanceStorePolicyRAID10 InstanceStorePolicy = "RAID10"
+	// InstanceStorePolicyMount formats and mounts each ephemeral NVMe instance storage disk separately under
+	// `/mnt/k8s-disks`, for use by local volume provisioners. Node ephemeral-storage remains on the root volume.
+	InstanceStorePolicyMount InstanceStorePolicy = "Mount"
+	// InstanceStorePolicyNone leaves ephemeral NVMe instance storage disks unconfigured.
+	InstanceStorePolicyNone InstanceStorePolicy = "None"
 )
 
 // EC2NodeClass is the Schema for the EC2NodeClass API
@@ -467,12 +990,22 @@ type EC2NodeClass struct {
 
 	// +kubebuilder:validation:XValidation:message="must specify exactly one of ['role', 'instanceProfile']",rule="(has(self.role) && !has(self.instanceProfile)) || (!has(self.role) && has(self.instanceProfile))"
 	// +kubebuilder:validation:XValidation:message="changing from 'instanceProfile' to 'role' is not supported. You must delete and recreate this node class if you want to change this.",rule="(has(oldSelf.role) && has(self.role)) || (has(oldSelf.instanceProfile) && has(self.instanceProfile))"
+	// +kubebuilder:validation:XValidation:message="must specify instanceProfile when using assumeRole",rule="!has(self.assumeRole) || has(self.instanceProfile)"
 	// +kubebuilder:validation:XValidation:message="if set, amiFamily must be 'AL2' or 'Custom' when using an AL2 alias",rule="!has(self.amiFamily) || (self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'al2') ? (self.amiFamily == 'Custom' || self.amiFamily == 'AL2') : true)"
 	// +kubebuilder:validation:XValidation:message="if set, amiFamily must be 'AL2023' or 'Custom' when using an AL2023 alias",rule="!has(self.amiFamily) || (self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'al2023') ? (self.amiFamily == 'Custom' || self.amiFamily == 'AL2023') : true)"
 	// +kubebuilder:validation:XValidation:message="if set, amiFamily must be 'Bottlerocket' or 'Custom' when using a Bottlerocket alias",rule="!has(self.amiFamily) || (self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'bottlerocket') ? (self.amiFamily == 'Custom' || self.amiFamily == 'Bottlerocket') : true)"
+	// +kubebuilder:validation:XValidation:message="if set, amiFamily must be 'Ubuntu' or 'Custom' when using an Ubuntu alias",rule="!has(self.amiFamily) || (self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'ubuntu') ? (self.amiFamily == 'Custom' || self.amiFamily == 'Ubuntu') : true)"
 	// +kubebuilder:validation:XValidation:message="if set, amiFamily must be 'Windows2019' or 'Custom' when using a Windows2019 alias",rule="!has(self.amiFamily) || (self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'windows2019') ? (self.amiFamily == 'Custom' || self.amiFamily == 'Windows2019') : true)"
 	// +kubebuilder:validation:XValidation:message="if set, amiFamily must be 'Windows2022' or 'Custom' when using a Windows2022 alias",rule="!has(self.amiFamily) || (self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'windows2022') ? (self.amiFamily == 'Custom' || self.amiFamily == 'Windows2022') : true)"
+	// +kubebuilder:validation:XValidation:message="if set, amiFamily must be 'Windows2025' or 'Custom' when using a Windows2025 alias",rule="!has(self.amiFamily) || (self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'windows2025') ? (self.amiFamily == 'Custom' || self.amiFamily == 'Windows2025') : true)"
 	// +kubebuilder:validation:XValidation:message="must specify amiFamily if amiSelectorTerms does not contain an alias",rule="self.amiSelectorTerms.exists(x, has(x.alias)) ? true : has(self.amiFamily)"
+	// +kubebuilder:validation:XValidation:message="bottlerocket may only be set when using the Bottlerocket AMI family",rule="!has(self.bottlerocket) || (has(self.amiFamily) ? self.amiFamily == 'Bottlerocket' : self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'bottlerocket'))"
+	// +kubebuilder:validation:XValidation:message="kubelet shutdownGracePeriod, shutdownGracePeriodCriticalPods, and maxParallelImagePulls are not supported by the AL2, Ubuntu, or Windows AMI families",rule="!has(self.kubelet) || !(has(self.kubelet.shutdownGracePeriod) || has(self.kubelet.shutdownGracePeriodCriticalPods) || has(self.kubelet.maxParallelImagePulls)) || !(has(self.amiFamily) ? self.amiFamily in ['AL2','Ubuntu','Windows2019','Windows2022','Windows2025'] : self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') in ['al2','ubuntu','windows2019','windows2022','windows2025']))"
+	// +kubebuilder:validation:XValidation:message="kubelet cpuManagerPolicy, topologyManagerPolicy, topologyManagerScope, and allowedUnsafeSysctls are not supported by the Windows AMI families",rule="!has(self.kubelet) || !(has(self.kubelet.cpuManagerPolicy) || has(self.kubelet.topologyManagerPolicy) || has(self.kubelet.topologyManagerScope) || has(self.kubelet.allowedUnsafeSysctls)) || !(has(self.amiFamily) ? self.amiFamily in ['Windows2019','Windows2022','Windows2025'] : self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') in ['windows2019','windows2022','windows2025']))"
+	// +kubebuilder:validation:XValidation:message="kubelet serializeImagePulls and maxParallelImagePulls are not supported by the Bottlerocket AMI family",rule="!has(self.kubelet) || !(has(self.kubelet.serializeImagePulls) || has(self.kubelet.maxParallelImagePulls)) || !(has(self.amiFamily) ? self.amiFamily == 'Bottlerocket' : self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'bottlerocket'))"
+	// +kubebuilder:validation:XValidation:message="registries.credentialProviders is not supported by the Windows AMI families",rule="!has(self.registries) || !has(self.registries.credentialProviders) || !(has(self.amiFamily) ? self.amiFamily in ['Windows2019','Windows2022','Windows2025'] : self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') in ['windows2019','windows2022','windows2025']))"
+	// +kubebuilder:validation:XValidation:message="registries.mirrors and bottlerocket.containerRegistry are mutually exclusive",rule="!(has(self.registries) && has(self.registries.mirrors) && has(self.bottlerocket) && has(self.bottlerocket.containerRegistry))"
+	// +kubebuilder:validation:XValidation:message="instanceStorePolicy RAID10 and Mount are not supported by the Bottlerocket AMI family",rule="!has(self.instanceStorePolicy) || !(self.instanceStorePolicy in ['RAID10','Mount']) || !(has(self.amiFamily) ? self.amiFamily == 'Bottlerocket' : self.amiSelectorTerms.exists(x, has(x.alias) && x.alias.find('^[^@]+') == 'bottlerocket'))"
 	Spec   EC2NodeClassSpec   `json:"spec,omitempty"`
 	Status EC2NodeClassStatus `json:"status,omitempty"`
 }
@@ -484,13 +1017,19 @@ type EC2NodeClass struct {
 const EC2NodeClassHashVersion = "v4"
 
 func (in *EC2NodeClass) Hash() string {
-	return fmt.Sprint(lo.Must(hashstructure.Hash([]interface{}{
+	values := []interface{}{
 		in.Spec,
 		// AMIFamily should be hashed using the dynamically resolved value rather than the literal value of the field.
 		// This ensures that scenarios such as changing the field from nil to AL2023 with the alias "al2023@latest"
 		// doesn't trigger drift.
 		in.AMIFamily(),
-	}, hashstructure.FormatV2, &hashstructure.HashOptions{
+	}
+	// Reordering instance type overrides changes the kubelet configuration of nodes, so their order is hashed too. This
+	// is only included when overrides are set, so that the hash of other EC2NodeClasses doesn't change.
+	if in.Spec.Kubelet != nil && len(in.Spec.Kubelet.InstanceTypeOverrides) != 0 {
+		values = append(values, in.Spec.Kubelet.InstanceTypeOverridesHash())
+	}
+	return fmt.Sprint(lo.Must(hashstructure.Hash(values, hashstructure.FormatV2, &hashstructure.HashOptions{
 		SlicesAsSets:    true,
 		IgnoreZeroValue: true,
 		ZeroNil:         true,
@@ -501,6 +1040,22 @@ func (in *EC2NodeClass) InstanceProfileName(clusterName, region string) string {
 	return fmt.Sprintf("%s_%d", clusterName, lo.Must(hashstructure.Hash(fmt.Sprintf("%s%s", region, in.Name), hashstructure.FormatV2, nil)))
 }
 
+// RoleInstanceProfileName returns the name of the instance profile created when the role of the EC2NodeClass changes.
+// The role is part of the name so that a profile is never reused for a different role than it was created with.
+func (in *EC2NodeClass) RoleInstanceProfileName(clusterName, region string) string {
+	return fmt.Sprintf("%s_%d", clusterName, lo.Must(hashstructure.Hash(fmt.Sprintf("%s%s%s", region, in.Name, in.Spec.Role), hashstructure.FormatV2, nil)))
+}
+
+// InstanceProfilePath returns the IAM path of the instance profiles that Karpenter creates for the EC2NodeClass
+func (in *EC2NodeClass) InstanceProfilePath(clusterName, region string) string {
+	return fmt.Sprintf("%s%s/", InstanceProfilePathPrefix(clusterName, region), in.Name)
+}
+
+// InstanceProfilePathPrefix returns the IAM path prefix shared by the instance profiles that Karpenter creates for a cluster
+func InstanceProfilePathPrefix(clusterName, region string) string {
+	return fmt.Sprintf("/karpenter/%s/%s/", region, clusterName)
+}
+
 func (in *EC2NodeClass) InstanceProfileRole() string {
 	return in.Spec.Role
 }
@@ -565,8 +1120,10 @@ func amiFamilyFromAlias(alias string) string {
 		AMIFamilyAL2,
 		AMIFamilyAL2023,
 		AMIFamilyBottlerocket,
+		AMIFamilyUbuntu,
 		AMIFamilyWindows2019,
 		AMIFamilyWindows2022,
+		AMIFamilyWindows2025,
 	}, func(family string) bool {
 		return strings.ToLower(family) == components[0]
 	})
@@ -133,11 +133,16 @@ var _ = Describe("Hash", func() {
 		Expect(hash).ToNot(Equal(updatedHash))
 	},
 		Entry("UserData", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{UserData: aws.String("userdata-test-2")}}),
+		Entry("TemplateUserData", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{TemplateUserData: aws.Bool(true)}}),
 		Entry("Tags", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{Tags: map[string]string{"keyTag-test-3": "valueTag-test-3"}}}),
 		Entry("Context", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{Context: aws.String("context-2")}}),
 		Entry("DetailedMonitoring", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{DetailedMonitoring: aws.Bool(true)}}),
 		Entry("InstanceStorePolicy", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{InstanceStorePolicy: lo.ToPtr(v1.InstanceStorePolicyRAID0)}}),
 		Entry("AssociatePublicIPAddress", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{AssociatePublicIPAddress: lo.ToPtr(true)}}),
+		Entry("CPUOptions ThreadsPerCore", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{CPUOptions: &v1.CPUOptions{ThreadsPerCore: lo.ToPtr(int32(1))}}}),
+		Entry("Bottlerocket Kernel Sysctl", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{Bottlerocket: &v1.BottlerocketSettings{Kernel: &v1.BottlerocketKernel{Sysctl: map[string]string{"net.core.somaxconn": "1024"}}}}}),
+		Entry("Bottlerocket NTP", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{Bottlerocket: &v1.BottlerocketSettings{NTP: &v1.BottlerocketNTP{TimeServers: []string{"169.254.169.123"}}}}}),
+		Entry("Registries Mirrors", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{Registries: &v1.RegistryConfiguration{Mirrors: []v1.RegistryMirror{{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}}}}}}),
 		Entry("MetadataOptions HTTPEndpoint", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{MetadataOptions: &v1.MetadataOptions{HTTPEndpoint: lo.ToPtr("enabled")}}}),
 		Entry("MetadataOptions HTTPProtocolIPv6", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{MetadataOptions: &v1.MetadataOptions{HTTPProtocolIPv6: lo.ToPtr("enabled")}}}),
 		Entry("MetadataOptions HTTPPutResponseHopLimit", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{MetadataOptions: &v1.MetadataOptions{HTTPPutResponseHopLimit: lo.ToPtr(int64(10))}}}),
@@ -151,6 +156,10 @@ var _ = Describe("Hash", func() {
 		Entry("BlockDeviceMapping SnapshotID", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{BlockDeviceMappings: []*v1.BlockDeviceMapping{{EBS: &v1.BlockDevice{SnapshotID: lo.ToPtr("test")}}}}}),
 		Entry("BlockDeviceMapping Throughput", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{BlockDeviceMappings: []*v1.BlockDeviceMapping{{EBS: &v1.BlockDevice{Throughput: lo.ToPtr(int64(10))}}}}}),
 		Entry("BlockDeviceMapping VolumeType", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{BlockDeviceMappings: []*v1.BlockDeviceMapping{{EBS: &v1.BlockDevice{VolumeType: lo.ToPtr("io1")}}}}}),
+		Entry("BlockDeviceMapping Tags", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{BlockDeviceMappings: []*v1.BlockDeviceMapping{{Tags: map[string]string{"backup": "daily"}}}}}),
+		Entry("BlockDeviceMapping VolumeSizePolicy", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{BlockDeviceMappings: []*v1.BlockDeviceMapping{{EBS: &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{PerVCPU: lo.ToPtr(resource.MustParse("5Gi"))}}}}}}),
+		Entry("Kubelet InstanceTypeOverrides", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{Kubelet: &v1.KubeletConfiguration{InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{{InstanceSizes: []string{"metal"}, MaxPods: lo.ToPtr[int32](250)}}}}}),
+		Entry("AssumeRole", v1.EC2NodeClass{Spec: v1.EC2NodeClassSpec{AssumeRole: &v1.AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/KarpenterNodeLauncher"}}}),
 	)
 	// We create a separate test for updating blockDeviceMapping volumeSize, since resource.Quantity is a struct, and mergo.WithSliceDeepCopy
 	// doesn't work well with unexported fields, like the ones that are present in resource.Quantity
@@ -180,6 +189,16 @@ var _ = Describe("Hash", func() {
 		updatedHash := nodeClass.Hash()
 		Expect(hash).To(Equal(updatedHash))
 	})
+	It("should change hash when kubelet instanceTypeOverrides are re-ordered", func() {
+		nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{
+			{InstanceSizes: []string{"metal"}, MaxPods: lo.ToPtr[int32](250)},
+			{InstanceFamilies: []string{"m7i"}, MaxPods: lo.ToPtr[int32](110)},
+		}}
+		hash := nodeClass.Hash()
+		nodeClass.Spec.Kubelet.InstanceTypeOverrides[0], nodeClass.Spec.Kubelet.InstanceTypeOverrides[1] = nodeClass.Spec.Kubelet.InstanceTypeOverrides[1], nodeClass.Spec.Kubelet.InstanceTypeOverrides[0]
+		updatedHash := nodeClass.Hash()
+		Expect(hash).ToNot(Equal(updatedHash))
+	})
 	It("should not change hash when behavior/dynamic fields are updated", func() {
 		hash := nodeClass.Hash()
 
@@ -31,6 +31,16 @@ const (
 	ConditionTypeInstanceProfileReady      = "InstanceProfileReady"
 	ConditionTypeCapacityReservationsReady = "CapacityReservationsReady"
 	ConditionTypeValidationSucceeded       = "ValidationSucceeded"
+	// ConditionTypeBlockDeviceMappingsReady is false when a KMS key referenced by a block device mapping is known to be
+	// unusable. It isn't a readiness condition, since EC2 can use keys that the controller isn't authorized to describe.
+	ConditionTypeBlockDeviceMappingsReady = "BlockDeviceMappingsReady"
+	// ConditionTypeSubnetsHaveCapacity is false when the subnet with the most available IPs in a zone has fewer than
+	// the configured threshold. It isn't a readiness condition, since launches can still succeed in the other zones.
+	ConditionTypeSubnetsHaveCapacity = "SubnetsHaveCapacity"
+	// ConditionTypeInstanceTypesReady is false when no instance type is compatible with the AMIs, subnet zones, and
+	// available offerings of the EC2NodeClass. It isn't a readiness condition, since the instance types are only
+	// known once the rest of the status has been resolved.
+	ConditionTypeInstanceTypesReady = "InstanceTypesReady"
 )
 
 // Subnet contains resolved Subnet selector values utilized for node launch
@@ -44,6 +54,9 @@ type Subnet struct {
 	// The associated availability zone ID
 	// +optional
 	ZoneID string `json:"zoneID,omitempty"`
+	// The number of available IP addresses in the subnet, as of the last time it was discovered
+	// +optional
+	AvailableIPAddressCount *int32 `json:"availableIPAddressCount,omitempty"`
 }
 
 // SecurityGroup contains resolved SecurityGroup selector values utilized for node launch
@@ -72,6 +85,52 @@ type AMI struct {
 	Requirements []corev1.NodeSelectorRequirement `json:"requirements"`
 }
 
+// AMIRollout contains candidate AMIs which haven't been promoted yet
+type AMIRollout struct {
+	// CandidateAMIs are the newly discovered AMIs which are used for canary launches
+	// +required
+	CandidateAMIs []AMI `json:"candidateAMIs"`
+	// SoakStartTime is the time the candidate AMIs started soaking
+	// +required
+	SoakStartTime metav1.Time `json:"soakStartTime"`
+}
+
+// InstanceTypeSelection summarises the instance types which are allowed by the instance type selector
+type InstanceTypeSelection struct {
+	// Count is the number of instance types which are allowed
+	// +required
+	Count int32 `json:"count"`
+	// Families are the instance families of the instance types which are allowed
+	// +optional
+	Families []string `json:"families,omitempty"`
+}
+
+// InstanceType contains a resolved instance type that can be launched with the EC2NodeClass
+type InstanceType struct {
+	// Name of the instance type
+	// +required
+	Name string `json:"name"`
+	// Architecture of the instance type
+	// +required
+	Architecture string `json:"architecture"`
+	// Zones that the instance type has an available offering in
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+	// CapacityTypes that the instance type has an available offering for
+	// +optional
+	CapacityTypes []InstanceTypeCapacity `json:"capacityTypes,omitempty"`
+}
+
+// InstanceTypeCapacity contains the availability of an instance type for a capacity type
+type InstanceTypeCapacity struct {
+	// CapacityType of the offerings
+	// +required
+	CapacityType string `json:"capacityType"`
+	// Available is the number of available offerings for the capacity type
+	// +required
+	Available int32 `json:"available"`
+}
+
 type CapacityReservation struct {
 	// The availability zone the capacity reservation is available in.
 	// +required
@@ -112,12 +171,33 @@ type EC2NodeClassStatus struct {
 	// +optional
 	CapacityReservations []CapacityReservation `json:"capacityReservations,omitempty"`
 	// AMI contains the current AMI values that are available to the
-	// cluster under the AMI selectors.
+	// cluster under the AMI selectors. When an AMI rollout policy is set, these are the stable AMIs.
 	// +optional
 	AMIs []AMI `json:"amis,omitempty"`
+	// AMIRollout contains the candidate AMIs which are soaking before being promoted to amis
+	// +optional
+	AMIRollout *AMIRollout `json:"amiRollout,omitempty"`
 	// InstanceProfile contains the resolved instance profile for the role
 	// +optional
 	InstanceProfile string `json:"instanceProfile,omitempty"`
+	// InstanceProfileRotationTime is the time the instance profile changed to a new instance profile for a
+	// different role. Previous instance profiles aren't garbage collected until the minimum age has passed
+	// since the rotation, so that launches which were already in flight can still use them.
+	// +optional
+	InstanceProfileRotationTime *metav1.Time `json:"instanceProfileRotationTime,omitempty"`
+	// InstanceTypeSelection summarises the instance types which are allowed by spec.instanceTypeSelector. It's only
+	// set when the instance type selector is specified.
+	// +optional
+	InstanceTypeSelection *InstanceTypeSelection `json:"instanceTypeSelection,omitempty"`
+	// InstanceTypes contains the instance types that can be launched with the EC2NodeClass, after AMI compatibility,
+	// subnet zones, and offering availability have been applied. It's only set when instance type reporting is enabled.
+	// +optional
+	InstanceTypes []InstanceType `json:"instanceTypes,omitempty"`
+	// ObservedGeneration is the generation of the EC2NodeClass spec that the resolved subnets, security groups,
+	// capacity reservations, AMIs, and instance profile reflect. Nodes are not launched until it has caught up to
+	// the generation of the EC2NodeClass.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Conditions contains signals for health and readiness
 	// +optional
 	Conditions []status.Condition `json:"conditions,omitempty"`
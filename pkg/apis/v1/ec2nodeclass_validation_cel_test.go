@@ -84,7 +84,7 @@ var _ = Describe("CEL/Validation", func() {
 		})
 	})
 	Context("AMIFamily", func() {
-		amiFamilies := []string{v1.AMIFamilyAL2, v1.AMIFamilyAL2023, v1.AMIFamilyBottlerocket, v1.AMIFamilyWindows2019, v1.AMIFamilyWindows2022, v1.AMIFamilyCustom}
+		amiFamilies := []string{v1.AMIFamilyAL2, v1.AMIFamilyAL2023, v1.AMIFamilyBottlerocket, v1.AMIFamilyUbuntu, v1.AMIFamilyWindows2019, v1.AMIFamilyWindows2022, v1.AMIFamilyWindows2025, v1.AMIFamilyCustom}
 		DescribeTable("should succeed with valid families", func() []interface{} {
 			f := func(amiFamily string) {
 				// Set a custom AMI family so it's compatible with all ami family types
@@ -97,10 +97,10 @@ var _ = Describe("CEL/Validation", func() {
 			})
 			return append([]interface{}{f}, entries...)
 		}()...)
-		It("should fail with the ubuntu family", func() {
+		It("should fail with an unknown family", func() {
 			// Set a custom AMI family so it's compatible with all ami family types
 			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{ID: "ami-0123456789abcdef"}}
-			nc.Spec.AMIFamily = lo.ToPtr(v1.AMIFamilyUbuntu)
+			nc.Spec.AMIFamily = lo.ToPtr("Debian")
 			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
 		})
 		DescribeTable("should succeed when the amiFamily matches amiSelectorTerms[].alias", func() []interface{} {
@@ -206,6 +206,10 @@ var _ = Describe("CEL/Validation", func() {
 				"karpenter.sh/nodeclaim": "test",
 			}
 			Expect(env.Client.Create(ctx, nc)).To(Not(Succeed()))
+			nc.Spec.Tags = map[string]string{
+				v1.AssumeRoleARNTagKey: "test",
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Not(Succeed()))
 		})
 	})
 	Context("SubnetSelectorTerms", func() {
@@ -690,7 +694,33 @@ var _ = Describe("CEL/Validation", func() {
 			}),
 			Entry("name", v1.AMISelectorTerm{Name: "my-custom-ami"}),
 			Entry("owner", v1.AMISelectorTerm{Owner: "123456789"}),
+			Entry("minAge", v1.AMISelectorTerm{MinAge: &metav1.Duration{Duration: time.Hour}}),
+			Entry("maxAge", v1.AMISelectorTerm{MaxAge: &metav1.Duration{Duration: time.Hour}}),
+			Entry("excludeDeprecated", v1.AMISelectorTerm{ExcludeDeprecated: lo.ToPtr(true)}),
 		)
+		It("should succeed when specifying age constraints with other fields", func() {
+			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{
+				Tags:              map[string]string{"test": "testvalue"},
+				MinAge:            &metav1.Duration{Duration: 7 * 24 * time.Hour},
+				MaxAge:            &metav1.Duration{Duration: 90 * 24 * time.Hour},
+				ExcludeDeprecated: lo.ToPtr(true),
+			}}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when minAge is not less than maxAge", func() {
+			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{
+				Tags:   map[string]string{"test": "testvalue"},
+				MinAge: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+				MaxAge: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			}}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when only specifying age constraints", func() {
+			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{
+				MinAge: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			}}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
 		It("should fail when specifying alias with other terms", func() {
 			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{
 				{Alias: "al2023@latest"},
@@ -711,8 +741,17 @@ var _ = Describe("CEL/Validation", func() {
 			Entry("al2023 (pinned)", "al2023@v20240625", v1.AMIFamilyAL2023),
 			Entry("bottlerocket (latest)", "bottlerocket@latest", v1.AMIFamilyBottlerocket),
 			Entry("bottlerocket (pinned)", "bottlerocket@1.10.0", v1.AMIFamilyBottlerocket),
+			Entry("ubuntu (latest)", "ubuntu@latest", v1.AMIFamilyUbuntu),
+			Entry("ubuntu (pinned)", "ubuntu@20240701", v1.AMIFamilyUbuntu),
+			Entry("ubuntu (release)", "ubuntu@24.04", v1.AMIFamilyUbuntu),
+			Entry("ubuntu (release, pinned)", "ubuntu@24.04-20240701", v1.AMIFamilyUbuntu),
+			Entry("ubuntu (release, pinned with build)", "ubuntu@24.04-20240701.1", v1.AMIFamilyUbuntu),
 			Entry("windows2019 (latest)", "windows2019@latest", v1.AMIFamilyWindows2019),
+			Entry("windows2019 (pinned)", "windows2019@2024.09.10", v1.AMIFamilyWindows2019),
 			Entry("windows2022 (latest)", "windows2022@latest", v1.AMIFamilyWindows2022),
+			Entry("windows2022 (pinned)", "windows2022@2024.09.10", v1.AMIFamilyWindows2022),
+			Entry("windows2025 (latest)", "windows2025@latest", v1.AMIFamilyWindows2025),
+			Entry("windows2025 (pinned)", "windows2025@2025.03.12", v1.AMIFamilyWindows2025),
 		)
 		DescribeTable(
 			"should fail for incorrectly formatted aliases",
@@ -727,17 +766,29 @@ var _ = Describe("CEL/Validation", func() {
 			Entry("invalid separator", "al2023-latest"),
 		)
 		It("should fail for an alias with an invalid family", func() {
-			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@latest"}}
+			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "debian@latest"}}
 			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
 		})
 		DescribeTable(
-			"should fail when specifying non-latest versions with Windows aliases",
+			"should fail when specifying improperly formatted versions with Ubuntu aliases",
+			func(alias string) {
+				nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: alias}}
+				Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+			},
+			Entry("semantic version", "ubuntu@v1.0.0"),
+			Entry("release without minor version", "ubuntu@24"),
+			Entry("release with a codename", "ubuntu@noble"),
+			Entry("release with an invalid serial", "ubuntu@24.04-2024"),
+		)
+		DescribeTable(
+			"should fail when specifying improperly formatted versions with Windows aliases",
 			func(alias string) {
 				nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: alias}}
 				Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
 			},
 			Entry("Windows2019", "windows2019@v1.0.0"),
 			Entry("Windows2022", "windows2022@v1.0.0"),
+			Entry("Windows2025", "windows2025@20250312"),
 		)
 	})
 	Context("Kubelet", func() {
@@ -942,6 +993,344 @@ var _ = Describe("CEL/Validation", func() {
 				Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
 			})
 		})
+		Context("InstanceTypeOverrides", func() {
+			It("should succeed when specifying instance type overrides", func() {
+				nc.Spec.Kubelet = &v1.KubeletConfiguration{
+					InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{
+						{
+							InstanceFamilies: []string{"m7i", "c7i"},
+							InstanceSizes:    []string{"48xlarge", "metal"},
+							MaxPods:          lo.ToPtr[int32](250),
+						},
+						{
+							MinVCPU:        lo.ToPtr[int32](64),
+							MaxVCPU:        lo.ToPtr[int32](128),
+							MinMemory:      lo.ToPtr(resource.MustParse("256Gi")),
+							KubeReserved:   map[string]string{"cpu": "500m", "memory": "8Gi"},
+							SystemReserved: map[string]string{"memory": "2Gi"},
+						},
+					},
+				}
+				Expect(env.Client.Create(ctx, nc)).To(Succeed())
+			})
+			DescribeTable(
+				"should fail on invalid instance type overrides",
+				func(override v1.KubeletInstanceTypeOverride) {
+					nc.Spec.Kubelet = &v1.KubeletConfiguration{
+						InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{override},
+					}
+					Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+				},
+				Entry("without a selector", v1.KubeletInstanceTypeOverride{MaxPods: lo.ToPtr[int32](250)}),
+				Entry("without an override", v1.KubeletInstanceTypeOverride{InstanceFamilies: []string{"m7i"}}),
+				Entry("with minVCPU greater than maxVCPU", v1.KubeletInstanceTypeOverride{MinVCPU: lo.ToPtr[int32](64), MaxVCPU: lo.ToPtr[int32](32), MaxPods: lo.ToPtr[int32](250)}),
+				Entry("with an invalid kubeReserved key", v1.KubeletInstanceTypeOverride{InstanceFamilies: []string{"m7i"}, KubeReserved: map[string]string{"gpu": "1"}}),
+				Entry("with a negative systemReserved value", v1.KubeletInstanceTypeOverride{InstanceFamilies: []string{"m7i"}, SystemReserved: map[string]string{"memory": "-1Gi"}}),
+				Entry("with an invalid minMemory", v1.KubeletInstanceTypeOverride{MinMemory: lo.ToPtr(resource.MustParse("1.5Gi")), MaxPods: lo.ToPtr[int32](250)}),
+			)
+		})
+		It("should succeed when specifying the expanded kubelet configuration with AL2023", func() {
+			nc.Spec.Kubelet = &v1.KubeletConfiguration{
+				CPUManagerPolicy:                lo.ToPtr("static"),
+				CPUManagerReconcilePeriod:       &metav1.Duration{Duration: 10 * time.Second},
+				TopologyManagerPolicy:           lo.ToPtr("single-numa-node"),
+				TopologyManagerScope:            lo.ToPtr("pod"),
+				ShutdownGracePeriod:             &metav1.Duration{Duration: time.Minute},
+				ShutdownGracePeriodCriticalPods: &metav1.Duration{Duration: 20 * time.Second},
+				ContainerLogMaxSize:             lo.ToPtr("50Mi"),
+				ContainerLogMaxFiles:            lo.ToPtr[int32](5),
+				RegistryPullQPS:                 lo.ToPtr[int32](10),
+				RegistryBurst:                   lo.ToPtr[int32](20),
+				SerializeImagePulls:             lo.ToPtr(false),
+				MaxParallelImagePulls:           lo.ToPtr[int32](4),
+				AllowedUnsafeSysctls:            []string{"net.core.somaxconn", "kernel.msg*"},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail on an invalid cpuManagerPolicy", func() {
+			nc.Spec.Kubelet = &v1.KubeletConfiguration{CPUManagerPolicy: lo.ToPtr("dynamic")}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail on an invalid containerLogMaxSize", func() {
+			nc.Spec.Kubelet = &v1.KubeletConfiguration{ContainerLogMaxSize: lo.ToPtr("50MB")}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when shutdownGracePeriodCriticalPods exceeds shutdownGracePeriod", func() {
+			nc.Spec.Kubelet = &v1.KubeletConfiguration{
+				ShutdownGracePeriod:             &metav1.Duration{Duration: 30 * time.Second},
+				ShutdownGracePeriodCriticalPods: &metav1.Duration{Duration: time.Minute},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when shutdownGracePeriodCriticalPods is set without shutdownGracePeriod", func() {
+			nc.Spec.Kubelet = &v1.KubeletConfiguration{
+				ShutdownGracePeriodCriticalPods: &metav1.Duration{Duration: time.Minute},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when maxParallelImagePulls is set without disabling serializeImagePulls", func() {
+			nc.Spec.Kubelet = &v1.KubeletConfiguration{MaxParallelImagePulls: lo.ToPtr[int32](4)}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		DescribeTable(
+			"should fail when specifying kubelet fields unsupported by the AMI family",
+			func(family *string, alias string, kubelet v1.KubeletConfiguration) {
+				nc.Spec.AMIFamily = family
+				nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: alias}}
+				if alias == "" {
+					nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Tags: map[string]string{"*": "*"}}}
+				}
+				nc.Spec.Kubelet = &kubelet
+				Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+			},
+			Entry("shutdownGracePeriod with AL2", nil, "al2@latest", v1.KubeletConfiguration{ShutdownGracePeriod: &metav1.Duration{Duration: time.Minute}}),
+			Entry("shutdownGracePeriod with Ubuntu", &v1.AMIFamilyUbuntu, "", v1.KubeletConfiguration{ShutdownGracePeriod: &metav1.Duration{Duration: time.Minute}}),
+			Entry("maxParallelImagePulls with Windows2022", nil, "windows2022@latest", v1.KubeletConfiguration{SerializeImagePulls: lo.ToPtr(false), MaxParallelImagePulls: lo.ToPtr[int32](2)}),
+			Entry("cpuManagerPolicy with Windows2019", nil, "windows2019@latest", v1.KubeletConfiguration{CPUManagerPolicy: lo.ToPtr("static")}),
+			Entry("allowedUnsafeSysctls with Windows2025", nil, "windows2025@latest", v1.KubeletConfiguration{AllowedUnsafeSysctls: []string{"net.core.somaxconn"}}),
+			Entry("serializeImagePulls with Bottlerocket", nil, "bottlerocket@latest", v1.KubeletConfiguration{SerializeImagePulls: lo.ToPtr(true)}),
+		)
+	})
+	Context("AMIRolloutPolicy", func() {
+		It("should succeed for valid inputs", func() {
+			nc.Spec.AMIRolloutPolicy = &v1.AMIRolloutPolicy{
+				CanaryPercentage: lo.ToPtr[int32](10),
+				SoakDuration:     metav1.Duration{Duration: 24 * time.Hour},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail for an invalid canaryPercentage", func() {
+			nc.Spec.AMIRolloutPolicy = &v1.AMIRolloutPolicy{
+				CanaryPercentage: lo.ToPtr[int32](101),
+				SoakDuration:     metav1.Duration{Duration: time.Hour},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("Registries", func() {
+		BeforeEach(func() {
+			nc.Spec.Registries = &v1.RegistryConfiguration{
+				Mirrors: []v1.RegistryMirror{
+					{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}},
+					{Registry: "*", Endpoints: []string{"http://mirror.internal:5000"}},
+				},
+				CredentialProviders: []v1.CredentialProvider{{
+					Name:                 "registry-credential-provider",
+					MatchImages:          []string{"*.registry.example.com"},
+					DefaultCacheDuration: &metav1.Duration{Duration: time.Hour},
+					Env:                  map[string]string{"REGISTRY_REGION": "us-west-2"},
+				}},
+			}
+		})
+		It("should succeed with valid registry configuration", func() {
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when a mirror registry is invalid", func() {
+			nc.Spec.Registries.Mirrors[0].Registry = "https://docker.io"
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when mirror registries are duplicated", func() {
+			nc.Spec.Registries.Mirrors[1].Registry = "docker.io"
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when a mirror endpoint isn't a URL", func() {
+			nc.Spec.Registries.Mirrors[0].Endpoints = []string{"mirror.example.com"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when credential provider names are duplicated", func() {
+			nc.Spec.Registries.CredentialProviders = append(nc.Spec.Registries.CredentialProviders, nc.Spec.Registries.CredentialProviders[0])
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when a credential provider doesn't match any images", func() {
+			nc.Spec.Registries.CredentialProviders[0].MatchImages = nil
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when specifying credential providers with a Windows AMI family", func() {
+			nc.Spec.AMIFamily = nil
+			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "windows2022@latest"}}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should succeed when specifying mirrors with a Windows AMI family", func() {
+			nc.Spec.AMIFamily = nil
+			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "windows2022@latest"}}
+			nc.Spec.Registries.CredentialProviders = nil
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when specifying mirrors alongside bottlerocket container registry settings", func() {
+			nc.Spec.AMIFamily = nil
+			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "bottlerocket@latest"}}
+			nc.Spec.Bottlerocket = &v1.BottlerocketSettings{
+				ContainerRegistry: &v1.BottlerocketContainerRegistry{
+					Mirrors: []v1.BottlerocketRegistryMirror{{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}}},
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("Bottlerocket", func() {
+		BeforeEach(func() {
+			nc.Spec.AMIFamily = nil
+			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "bottlerocket@latest"}}
+			nc.Spec.Bottlerocket = &v1.BottlerocketSettings{
+				Kernel: &v1.BottlerocketKernel{
+					Sysctl: map[string]string{"net.core.somaxconn": "1024", "net/ipv4/ip_forward": "1"},
+				},
+				ContainerRegistry: &v1.BottlerocketContainerRegistry{
+					Mirrors: []v1.BottlerocketRegistryMirror{
+						{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}},
+						{Registry: "*", Endpoints: []string{"http://mirror.internal:5000"}},
+					},
+				},
+				BootstrapContainers: map[string]v1.BottlerocketBootstrapContainer{
+					"setup-disks": {Source: "public.ecr.aws/example/setup:latest", Mode: lo.ToPtr("once")},
+				},
+				HostContainers: map[string]v1.BottlerocketHostContainer{
+					"admin":   {Enabled: lo.ToPtr(true)},
+					"monitor": {Source: lo.ToPtr("public.ecr.aws/example/monitor:latest"), Enabled: lo.ToPtr(true)},
+				},
+				NTP: &v1.BottlerocketNTP{TimeServers: []string{"169.254.169.123"}},
+			}
+		})
+		It("should succeed with valid settings when using a bottlerocket alias", func() {
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with valid settings when using the Bottlerocket amiFamily", func() {
+			nc.Spec.AMIFamily = &v1.AMIFamilyBottlerocket
+			nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Tags: map[string]string{"*": "*"}}}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		DescribeTable(
+			"should fail when not using the Bottlerocket AMI family",
+			func(family *string, alias string) {
+				nc.Spec.AMIFamily = family
+				nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: alias}}
+				Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+			},
+			Entry("AL2023 alias", nil, "al2023@latest"),
+			Entry("Custom family with a Bottlerocket alias", &v1.AMIFamilyCustom, "bottlerocket@latest"),
+		)
+		It("should fail with an invalid sysctl key", func() {
+			nc.Spec.Bottlerocket.Kernel.Sysctl = map[string]string{"somaxconn": "1024"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with an empty sysctl value", func() {
+			nc.Spec.Bottlerocket.Kernel.Sysctl = map[string]string{"net.core.somaxconn": ""}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with duplicate registry mirrors", func() {
+			nc.Spec.Bottlerocket.ContainerRegistry.Mirrors = append(nc.Spec.Bottlerocket.ContainerRegistry.Mirrors, v1.BottlerocketRegistryMirror{
+				Registry:  "docker.io",
+				Endpoints: []string{"https://other-mirror.example.com"},
+			})
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with a mirror endpoint which isn't a URL", func() {
+			nc.Spec.Bottlerocket.ContainerRegistry.Mirrors[0].Endpoints = []string{"mirror.example.com"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with an invalid bootstrap container mode", func() {
+			nc.Spec.Bottlerocket.BootstrapContainers["setup-disks"] = v1.BottlerocketBootstrapContainer{
+				Source: "public.ecr.aws/example/setup:latest",
+				Mode:   lo.ToPtr("sometimes"),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with an invalid container name", func() {
+			nc.Spec.Bottlerocket.BootstrapContainers["setup disks"] = v1.BottlerocketBootstrapContainer{Source: "public.ecr.aws/example/setup:latest"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with a host container without a source", func() {
+			nc.Spec.Bottlerocket.HostContainers["monitor"] = v1.BottlerocketHostContainer{Enabled: lo.ToPtr(true)}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail without NTP time servers", func() {
+			nc.Spec.Bottlerocket.NTP.TimeServers = nil
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("InstanceStorePolicy", func() {
+		DescribeTable(
+			"should succeed with supported policies",
+			func(policy v1.InstanceStorePolicy, alias string) {
+				nc.Spec.AMIFamily = nil
+				nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: alias}}
+				nc.Spec.InstanceStorePolicy = lo.ToPtr(policy)
+				Expect(env.Client.Create(ctx, nc)).To(Succeed())
+			},
+			Entry("RAID0 on AL2023", v1.InstanceStorePolicyRAID0, "al2023@latest"),
+			Entry("RAID10 on AL2023", v1.InstanceStorePolicyRAID10, "al2023@latest"),
+			Entry("Mount on AL2023", v1.InstanceStorePolicyMount, "al2023@latest"),
+			Entry("None on AL2023", v1.InstanceStorePolicyNone, "al2023@latest"),
+			Entry("RAID10 on AL2", v1.InstanceStorePolicyRAID10, "al2@latest"),
+			Entry("RAID0 on Bottlerocket", v1.InstanceStorePolicyRAID0, "bottlerocket@latest"),
+			Entry("None on Bottlerocket", v1.InstanceStorePolicyNone, "bottlerocket@latest"),
+		)
+		DescribeTable(
+			"should fail with policies which aren't supported by Bottlerocket",
+			func(policy v1.InstanceStorePolicy) {
+				nc.Spec.AMIFamily = nil
+				nc.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "bottlerocket@latest"}}
+				nc.Spec.InstanceStorePolicy = lo.ToPtr(policy)
+				Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+			},
+			Entry("RAID10", v1.InstanceStorePolicyRAID10),
+			Entry("Mount", v1.InstanceStorePolicyMount),
+		)
+		It("should fail with an unknown policy", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1.InstanceStorePolicy("RAID5"))
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("CPUOptions", func() {
+		It("should succeed for valid inputs", func() {
+			nc.Spec.CPUOptions = &v1.CPUOptions{
+				CoreCount:      aws.Int32(2),
+				ThreadsPerCore: aws.Int32(1),
+				AMDSEVSNP:      aws.String("enabled"),
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail for invalid for CoreCount", func() {
+			nc.Spec.CPUOptions = &v1.CPUOptions{
+				CoreCount: aws.Int32(0),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail for invalid for ThreadsPerCore", func() {
+			nc.Spec.CPUOptions = &v1.CPUOptions{
+				ThreadsPerCore: aws.Int32(3),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail for invalid for AMDSEVSNP", func() {
+			nc.Spec.CPUOptions = &v1.CPUOptions{
+				AMDSEVSNP: aws.String("test"),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("InstanceTypeSelector", func() {
+		It("should succeed for valid inputs", func() {
+			nc.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{
+				Allow:                     []string{"m5.*", "c6?.large", "t3.large"},
+				Deny:                      []string{"*.metal"},
+				ExcludePreviousGeneration: lo.ToPtr(true),
+				ExcludeBareMetal:          lo.ToPtr(true),
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail for an invalid allow pattern", func() {
+			nc.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{
+				Allow: []string{"m5.[a-z]large"},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail for an invalid deny pattern", func() {
+			nc.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{
+				Deny: []string{"M5.LARGE"},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
 	})
 	Context("MetadataOptions", func() {
 		It("should succeed for valid inputs", func() {
@@ -1210,18 +1599,97 @@ var _ = Describe("CEL/Validation", func() {
 			}
 			Expect(env.Client.Create(ctx, nodeClass)).To(Not(Succeed()))
 		})
+		DescribeTable(
+			"should validate volumeSizePolicy",
+			func(bdm *v1.BlockDeviceMapping, expected bool) {
+				nodeClass := &v1.EC2NodeClass{
+					ObjectMeta: test.ObjectMeta(metav1.ObjectMeta{}),
+					Spec: v1.EC2NodeClassSpec{
+						AMISelectorTerms:           nc.Spec.AMISelectorTerms,
+						SubnetSelectorTerms:        nc.Spec.SubnetSelectorTerms,
+						SecurityGroupSelectorTerms: nc.Spec.SecurityGroupSelectorTerms,
+						Role:                       nc.Spec.Role,
+						BlockDeviceMappings:        []*v1.BlockDeviceMapping{bdm},
+					},
+				}
+				if expected {
+					Expect(env.Client.Create(ctx, nodeClass)).To(Succeed())
+				} else {
+					Expect(env.Client.Create(ctx, nodeClass)).ToNot(Succeed())
+				}
+			},
+			Entry("should succeed for the root volume without a volume size", &v1.BlockDeviceMapping{
+				DeviceName: aws.String("/dev/xvda"),
+				EBS: &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{
+					PerVCPU: lo.ToPtr(resource.MustParse("5Gi")),
+					Min:     lo.ToPtr(resource.MustParse("20Gi")),
+					Max:     lo.ToPtr(resource.MustParse("500Gi")),
+				}},
+				RootVolume: true,
+			}, true),
+			Entry("should succeed for the root volume with a base volume size", &v1.BlockDeviceMapping{
+				DeviceName: aws.String("/dev/xvda"),
+				EBS: &v1.BlockDevice{
+					VolumeSize:       lo.ToPtr(resource.MustParse("20Gi")),
+					VolumeSizePolicy: &v1.VolumeSizePolicy{PerGiBMemory: lo.ToPtr(resource.MustParse("512Mi"))},
+				},
+				RootVolume: true,
+			}, true),
+			Entry("should fail for a volume which isn't the root volume", &v1.BlockDeviceMapping{
+				DeviceName: aws.String("/dev/xvdb"),
+				EBS:        &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{PerVCPU: lo.ToPtr(resource.MustParse("5Gi"))}},
+			}, false),
+			Entry("should fail without perVCPU or perGiBMemory", &v1.BlockDeviceMapping{
+				DeviceName: aws.String("/dev/xvda"),
+				EBS:        &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{Max: lo.ToPtr(resource.MustParse("500Gi"))}},
+				RootVolume: true,
+			}, false),
+			Entry("should succeed with a minimum below the maximum in different units", &v1.BlockDeviceMapping{
+				DeviceName: aws.String("/dev/xvda"),
+				EBS: &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{
+					PerVCPU: lo.ToPtr(resource.MustParse("5Gi")),
+					Min:     lo.ToPtr(resource.MustParse("900Gi")),
+					Max:     lo.ToPtr(resource.MustParse("1Ti")),
+				}},
+				RootVolume: true,
+			}, true),
+			Entry("should fail with a minimum above the maximum", &v1.BlockDeviceMapping{
+				DeviceName: aws.String("/dev/xvda"),
+				EBS: &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{
+					PerVCPU: lo.ToPtr(resource.MustParse("5Gi")),
+					Min:     lo.ToPtr(resource.MustParse("200Gi")),
+					Max:     lo.ToPtr(resource.MustParse("100Gi")),
+				}},
+				RootVolume: true,
+			}, false),
+			Entry("should fail with a maximum above the EBS limit", &v1.BlockDeviceMapping{
+				DeviceName: aws.String("/dev/xvda"),
+				EBS: &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{
+					PerVCPU: lo.ToPtr(resource.MustParse("5Gi")),
+					Max:     lo.ToPtr(resource.MustParse("100Ti")),
+				}},
+				RootVolume: true,
+			}, false),
+		)
 	})
-	Context("Role Immutability", func() {
+	Context("Role", func() {
 		It("should fail if role is not defined", func() {
 			nc.Spec.Role = ""
 			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
 		})
-		It("should fail when updating the role", func() {
+		It("should succeed when updating the role", func() {
 			nc.Spec.Role = "test-role"
 			Expect(env.Client.Create(ctx, nc)).To(Succeed())
 
 			nc.Spec.Role = "test-role2"
-			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+			Expect(env.Client.Update(ctx, nc)).To(Succeed())
+		})
+		It("should fail when updating the role to empty", func() {
+			nc.Spec.Role = "test-role"
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+
+			nc.Spec.Role = ""
+			Expect(env.Client.Update(ctx, nc)).ToNot(Succeed())
 		})
 		It("should fail to switch between an unmanaged and managed instance profile", func() {
 			nc.Spec.Role = ""
@@ -1242,4 +1710,40 @@ var _ = Describe("CEL/Validation", func() {
 			Expect(env.Client.Update(ctx, nc)).ToNot(Succeed())
 		})
 	})
+	Context("AssumeRole", func() {
+		BeforeEach(func() {
+			nc.Spec.Role = ""
+			nc.Spec.InstanceProfile = lo.ToPtr("test-instance-profile")
+		})
+		It("should succeed with a role ARN", func() {
+			nc.Spec.AssumeRole = &v1.AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/KarpenterNodeLauncher"}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with a role ARN and external ID", func() {
+			nc.Spec.AssumeRole = &v1.AssumeRole{
+				RoleARN:    "arn:aws-cn:iam::123456789012:role/path/KarpenterNodeLauncher",
+				ExternalID: lo.ToPtr("external-id"),
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when the role ARN isn't an IAM role ARN", func() {
+			nc.Spec.AssumeRole = &v1.AssumeRole{RoleARN: "KarpenterNodeLauncher"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+			nc.Spec.AssumeRole = &v1.AssumeRole{RoleARN: "arn:aws:iam::123456789012:user/KarpenterNodeLauncher"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when the external ID is too short", func() {
+			nc.Spec.AssumeRole = &v1.AssumeRole{
+				RoleARN:    "arn:aws:iam::123456789012:role/KarpenterNodeLauncher",
+				ExternalID: lo.ToPtr("a"),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when using a role instead of an instance profile", func() {
+			nc.Spec.Role = "test-role"
+			nc.Spec.InstanceProfile = nil
+			nc.Spec.AssumeRole = &v1.AssumeRole{RoleARN: "arn:aws:iam::123456789012:role/KarpenterNodeLauncher"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
 })
@@ -38,6 +38,7 @@ func init() {
 		LabelInstanceSize,
 		LabelInstanceLocalNVME,
 		LabelInstanceCPU,
+		LabelInstanceThreadsPerCore,
 		LabelInstanceCPUManufacturer,
 		LabelInstanceCPUSustainedClockSpeedMhz,
 		LabelInstanceMemory,
@@ -76,6 +77,7 @@ var (
 		regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(EKSClusterNameTagKey))),
 		regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(NodeClassTagKey))),
 		regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(NodeClaimTagKey))),
+		regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(AssumeRoleARNTagKey))),
 	}
 	AMIFamilyBottlerocket                          = "Bottlerocket"
 	AMIFamilyAL2                                   = "AL2"
@@ -83,12 +85,15 @@ var (
 	AMIFamilyUbuntu                                = "Ubuntu"
 	AMIFamilyWindows2019                           = "Windows2019"
 	AMIFamilyWindows2022                           = "Windows2022"
+	AMIFamilyWindows2025                           = "Windows2025"
 	AMIFamilyCustom                                = "Custom"
 	Windows2019                                    = "2019"
 	Windows2022                                    = "2022"
+	Windows2025                                    = "2025"
 	WindowsCore                                    = "Core"
 	Windows2019Build                               = "10.0.17763"
 	Windows2022Build                               = "10.0.20348"
+	Windows2025Build                               = "10.0.26100"
 	ResourceNVIDIAGPU          corev1.ResourceName = "nvidia.com/gpu"
 	ResourceAMDGPU             corev1.ResourceName = "amd.com/gpu"
 	ResourceAWSNeuron          corev1.ResourceName = "aws.amazon.com/neuron"
@@ -97,6 +102,9 @@ var (
 	ResourceAWSPodENI          corev1.ResourceName = "vpc.amazonaws.com/pod-eni"
 	ResourcePrivateIPv4Address corev1.ResourceName = "vpc.amazonaws.com/PrivateIPv4Address"
 	ResourceEFA                corev1.ResourceName = "vpc.amazonaws.com/efa"
+	// ResourceInstanceLocalNVMe is the number of instance store NVMe disks which are mounted individually, when using
+	// the Mount instance store policy
+	ResourceInstanceLocalNVMe corev1.ResourceName = apis.Group + "/instance-local-nvme"
 
 	LabelCapacityReservationID                = apis.Group + "/capacity-reservation-id"
 	LabelInstanceHypervisor                   = apis.Group + "/instance-hypervisor"
@@ -107,6 +115,7 @@ var (
 	LabelInstanceLocalNVME                    = apis.Group + "/instance-local-nvme"
 	LabelInstanceSize                         = apis.Group + "/instance-size"
 	LabelInstanceCPU                          = apis.Group + "/instance-cpu"
+	LabelInstanceThreadsPerCore               = apis.Group + "/instance-threads-per-core"
 	LabelInstanceCPUManufacturer              = apis.Group + "/instance-cpu-manufacturer"
 	LabelInstanceCPUSustainedClockSpeedMhz    = apis.Group + "/instance-cpu-sustained-clock-speed-mhz"
 	LabelInstanceMemory                       = apis.Group + "/instance-memory"
@@ -127,11 +136,13 @@ var (
 	AnnotationClusterNameTaggedCompatability = apis.CompatibilityGroup + "/cluster-name-tagged"
 	AnnotationEC2NodeClassHashVersion        = apis.Group + "/ec2nodeclass-hash-version"
 	AnnotationInstanceTagged                 = apis.Group + "/tagged"
+	AnnotationAssumeRoleARN                  = apis.Group + "/assume-role-arn"
 
 	NodeClaimTagKey          = coreapis.Group + "/nodeclaim"
 	NameTagKey               = "Name"
 	NodePoolTagKey           = karpv1.NodePoolLabelKey
 	NodeClassTagKey          = LabelNodeClass
+	AssumeRoleARNTagKey      = AnnotationAssumeRoleARN
 	LaunchTemplateNamePrefix = apis.Group
 	EKSClusterNameTagKey     = "eks:eks-cluster-name"
 )
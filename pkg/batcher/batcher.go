@@ -26,6 +26,8 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"sigs.k8s.io/karpenter/pkg/metrics"
+
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
 )
 
 // Options allows for configuration of the Batcher
@@ -100,7 +102,7 @@ func NewBatcher[T input, U output](ctx context.Context, options Options[T, U]) *
 func (b *Batcher[T, U]) Add(ctx context.Context, input *T) Result[U] {
 	request := &request[T, U]{
 		ctx:   ctx,
-		hash:  b.options.RequestHasher(ctx, input),
+		hash:  hashWithAccount(ctx, b.options.RequestHasher(ctx, input)),
 		input: input,
 		// The requestor channel is buffered to ensure that the exec runner can always write the result out preventing
 		// any single caller from blocking the others. Specifically since we register our request and then trigger, the
@@ -114,6 +116,16 @@ func (b *Batcher[T, U]) Add(ctx context.Context, input *T) Result[U] {
 	return <-request.requestor
 }
 
+// hashWithAccount buckets requests by the account of their context. A batch is executed with the context of its first
+// request, so requests made on behalf of different accounts can't share a batch.
+func hashWithAccount(ctx context.Context, hash uint64) uint64 {
+	role := account.FromContext(ctx)
+	if role == nil {
+		return hash
+	}
+	return lo.Must(hashstructure.Hash([]any{role, hash}, hashstructure.FormatV2, nil))
+}
+
 // DefaultHasher will hash the entire input
 func DefaultHasher[T input](_ context.Context, input *T) uint64 {
 	hash, err := hashstructure.Hash(input, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
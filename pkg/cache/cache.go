@@ -47,6 +47,9 @@ const (
 	DiscoveredCapacityCacheTTL = 60 * 24 * time.Hour
 	// ValidationTTL is time to check authorization errors with validation controller
 	ValidationTTL = 10 * time.Minute
+	// AssumeRoleCredentialsTTL is the time to drop the cached credentials of a role that is no longer used by any
+	// EC2NodeClass. Credentials of roles in use are refreshed by the credentials cache before they expire.
+	AssumeRoleCredentialsTTL = time.Hour
 )
 
 const (
@@ -45,6 +45,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cloudproviderevents "github.com/aws/karpenter-provider-aws/pkg/cloudprovider/events"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
@@ -107,6 +108,12 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *karpv1.NodeClaim)
 	if nodeClassReady.IsUnknown() {
 		return nil, cloudprovider.NewCreateError(fmt.Errorf("resolving NodeClass readiness, NodeClass is in Ready=Unknown, %s", nodeClassReady.Message), "NodeClassReadinessUnknown", "NodeClass is in Ready=Unknown")
 	}
+	// Launching with a status that hasn't caught up to a spec change would launch a node that is immediately drifted.
+	// An unset observedGeneration was written by a version of Karpenter that didn't track it, so we don't gate on it.
+	if nodeClass.Status.ObservedGeneration != 0 && nodeClass.Status.ObservedGeneration != nodeClass.Generation {
+		return nil, cloudprovider.NewNodeClassNotReadyError(fmt.Errorf("NodeClass status is stale, observed generation %d, generation %d", nodeClass.Status.ObservedGeneration, nodeClass.Generation))
+	}
+	ctx = account.IntoContext(ctx, nodeClass.Spec.AssumeRole)
 	instanceTypes, err := c.resolveInstanceTypes(ctx, nodeClaim, nodeClass)
 	if err != nil {
 		return nil, cloudprovider.NewCreateError(fmt.Errorf("resolving instance types, %w", err), "InstanceTypeResolutionFailed", "Error resolving instance types")
@@ -137,10 +144,21 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *karpv1.NodeClaim)
 }
 
 func (c *CloudProvider) List(ctx context.Context) ([]*karpv1.NodeClaim, error) {
-	instances, err := c.instanceProvider.List(ctx)
+	roles, err := account.Roles(ctx, c.kubeClient)
 	if err != nil {
-		return nil, fmt.Errorf("listing instances, %w", err)
+		return nil, fmt.Errorf("resolving assumed roles, %w", err)
+	}
+	var instances []*instance.Instance
+	// Instances are listed in the account of the controller as well as in the account of every role that's assumed
+	for _, role := range append([]*v1.AssumeRole{nil}, roles...) {
+		out, err := c.instanceProvider.List(account.IntoContext(ctx, role))
+		if err != nil {
+			return nil, fmt.Errorf("listing instances, %w", err)
+		}
+		instances = append(instances, out...)
 	}
+	// Multiple roles may be assumed in the same account
+	instances = lo.UniqBy(instances, func(i *instance.Instance) string { return i.ID })
 	var nodeClaims []*karpv1.NodeClaim
 	for _, instance := range instances {
 		instanceType, err := c.resolveInstanceTypeFromInstance(ctx, instance)
@@ -162,7 +180,7 @@ func (c *CloudProvider) Get(ctx context.Context, providerID string) (*karpv1.Nod
 		return nil, fmt.Errorf("getting instance ID, %w", err)
 	}
 	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("id", id))
-	instance, err := c.instanceProvider.Get(ctx, id)
+	instance, err := c.findInstance(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("getting instance, %w", err)
 	}
@@ -202,6 +220,10 @@ func (c *CloudProvider) Delete(ctx context.Context, nodeClaim *karpv1.NodeClaim)
 		return fmt.Errorf("getting instance ID, %w", err)
 	}
 	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("id", id))
+	ctx, err = c.withNodeClaimAccount(ctx, nodeClaim)
+	if err != nil {
+		return err
+	}
 	err = c.instanceProvider.Delete(ctx, id)
 	if id := nodeClaim.Labels[cloudprovider.ReservationIDLabel]; id != "" && cloudprovider.IsNodeClaimNotFoundError(err) {
 		c.capacityReservationProvider.MarkTerminated(id)
@@ -235,6 +257,10 @@ func (c *CloudProvider) IsDrifted(ctx context.Context, nodeClaim *karpv1.NodeCla
 		}
 		return "", fmt.Errorf("resolving node class, %w", err)
 	}
+	ctx, err = c.withNodeClaimAccount(ctx, nodeClaim)
+	if err != nil {
+		return "", err
+	}
 	driftReason, err := c.isNodeClassDrifted(ctx, nodeClaim, nodePool, nodeClass)
 	if err != nil {
 		return "", err
@@ -308,6 +334,34 @@ func (c *CloudProvider) resolveNodeClassFromNodeClaim(ctx context.Context, nodeC
 	return nodeClass, nil
 }
 
+// findInstance gets the instance from the account of the controller, falling back to the account of every role that's
+// assumed since the providerID doesn't identify the account of the instance
+func (c *CloudProvider) findInstance(ctx context.Context, id string) (*instance.Instance, error) {
+	instance, err := c.instanceProvider.Get(ctx, id)
+	if !cloudprovider.IsNodeClaimNotFoundError(err) {
+		return instance, err
+	}
+	roles, rolesErr := account.Roles(ctx, c.kubeClient)
+	if rolesErr != nil {
+		return nil, fmt.Errorf("resolving assumed roles, %w", rolesErr)
+	}
+	for _, role := range roles {
+		if instance, roleErr := c.instanceProvider.Get(account.IntoContext(ctx, role), id); !cloudprovider.IsNodeClaimNotFoundError(roleErr) {
+			return instance, roleErr
+		}
+	}
+	return nil, err
+}
+
+// withNodeClaimAccount scopes the context to the account that the instance of the NodeClaim was launched in
+func (c *CloudProvider) withNodeClaimAccount(ctx context.Context, nodeClaim *karpv1.NodeClaim) (context.Context, error) {
+	role, err := account.Role(ctx, c.kubeClient, nodeClaim.Annotations[v1.AnnotationAssumeRoleARN])
+	if err != nil {
+		return nil, fmt.Errorf("resolving assumed role, %w", err)
+	}
+	return account.IntoContext(ctx, role), nil
+}
+
 func (c *CloudProvider) resolveNodeClassFromNodePool(ctx context.Context, nodePool *karpv1.NodePool) (*v1.EC2NodeClass, error) {
 	nodeClass := &v1.EC2NodeClass{}
 	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodePool.Spec.Template.Spec.NodeClassRef.Name}, nodeClass); err != nil {
@@ -439,6 +493,9 @@ func (c *CloudProvider) instanceToNodeClaim(i *instance.Instance, instanceType *
 	if v, ok := i.Tags[karpv1.NodePoolLabelKey]; ok {
 		labels[karpv1.NodePoolLabelKey] = v
 	}
+	if v, ok := i.Tags[v1.AssumeRoleARNTagKey]; ok {
+		annotations[v1.AnnotationAssumeRoleARN] = v
+	}
 	nodeClaim.Labels = labels
 	nodeClaim.Annotations = annotations
 	nodeClaim.CreationTimestamp = metav1.Time{Time: i.LaunchTime}
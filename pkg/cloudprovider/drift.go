@@ -17,6 +17,7 @@ package cloudprovider
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/awslabs/operatorpkg/serrors"
 	"github.com/samber/lo"
@@ -37,6 +38,8 @@ const (
 	SubnetDrift              cloudprovider.DriftReason = "SubnetDrift"
 	SecurityGroupDrift       cloudprovider.DriftReason = "SecurityGroupDrift"
 	CapacityReservationDrift cloudprovider.DriftReason = "CapacityReservationDrift"
+	InstanceProfileDrift     cloudprovider.DriftReason = "InstanceProfileDrift"
+	InstanceTypeDrift        cloudprovider.DriftReason = "InstanceTypeDrift"
 	NodeClassDrift           cloudprovider.DriftReason = "NodeClassDrift"
 )
 
@@ -49,6 +52,14 @@ func (c *CloudProvider) isNodeClassDrifted(ctx context.Context, nodeClaim *karpv
 	if err != nil {
 		return "", err
 	}
+	// Instance types which are no longer selected can't be resolved when checking for AMI drift
+	instanceTypeDrifted, err := c.isInstanceTypeDrifted(ctx, nodeClaim, nodeClass)
+	if err != nil {
+		return "", fmt.Errorf("calculating instance type drift, %w", err)
+	}
+	if instanceTypeDrifted != "" {
+		return instanceTypeDrifted, nil
+	}
 	amiDrifted, err := c.isAMIDrifted(ctx, nodeClaim, nodePool, instance, nodeClass)
 	if err != nil {
 		return "", fmt.Errorf("calculating ami drift, %w", err)
@@ -62,11 +73,13 @@ func (c *CloudProvider) isNodeClassDrifted(ctx context.Context, nodeClaim *karpv
 		return "", fmt.Errorf("calculating subnet drift, %w", err)
 	}
 	capacityReservationsDrifted := c.isCapacityReservationDrifted(instance, nodeClass)
+	instanceProfileDrifted := c.isInstanceProfileDrifted(instance, nodeClass)
 	drifted := lo.FindOrElse([]cloudprovider.DriftReason{
 		amiDrifted,
 		securitygroupDrifted,
 		subnetDrifted,
 		capacityReservationsDrifted,
+		instanceProfileDrifted,
 	}, "", func(i cloudprovider.DriftReason) bool {
 		return string(i) != ""
 	})
@@ -88,13 +101,36 @@ func (c *CloudProvider) isAMIDrifted(ctx context.Context, nodeClaim *karpv1.Node
 	if len(nodeClass.Status.AMIs) == 0 {
 		return "", fmt.Errorf("no amis exist given constraints")
 	}
-	mappedAMIs := amifamily.MapToInstanceTypes([]*cloudprovider.InstanceType{nodeInstanceType}, nodeClass.Status.AMIs)
+	amis := nodeClass.Status.AMIs
+	// Nodes launched with candidate AMIs aren't drifted while the candidates soak
+	if nodeClass.Status.AMIRollout != nil {
+		amis = slices.Concat(amis, nodeClass.Status.AMIRollout.CandidateAMIs)
+	}
+	mappedAMIs := amifamily.MapToInstanceTypes([]*cloudprovider.InstanceType{nodeInstanceType}, amis)
 	if !lo.Contains(lo.Keys(mappedAMIs), instance.ImageID) {
 		return AMIDrift, nil
 	}
 	return "", nil
 }
 
+// Checks if the instance type is drifted, by checking that the instance type of the NodeClaim is still selected by
+// the NodeClass's instance type selector
+func (c *CloudProvider) isInstanceTypeDrifted(ctx context.Context, nodeClaim *karpv1.NodeClaim, nodeClass *v1.EC2NodeClass) (cloudprovider.DriftReason, error) {
+	if nodeClass.Spec.InstanceTypeSelector == nil {
+		return "", nil
+	}
+	instanceTypes, err := c.instanceTypeProvider.List(ctx, nodeClass)
+	if err != nil {
+		return "", fmt.Errorf("listing instance types, %w", err)
+	}
+	if !lo.ContainsBy(instanceTypes, func(it *cloudprovider.InstanceType) bool {
+		return it.Name == nodeClaim.Labels[corev1.LabelInstanceTypeStable]
+	}) {
+		return InstanceTypeDrift, nil
+	}
+	return "", nil
+}
+
 // Checks if the security groups are drifted, by comparing the subnet returned from the subnetProvider
 // to the ec2 instance subnets
 func (c *CloudProvider) isSubnetDrifted(instance *instance.Instance, nodeClass *v1.EC2NodeClass) (cloudprovider.DriftReason, error) {
@@ -140,6 +176,19 @@ func (c *CloudProvider) isCapacityReservationDrifted(instance *instance.Instance
 	return ""
 }
 
+// Checks if the instance profile is drifted, by comparing the instance profile persisted to the NodeClass to the
+// instance's instance profile. Instances launched with an instance profile from before a role change are drifted
+// so that the previous instance profile can be garbage collected.
+func (c *CloudProvider) isInstanceProfileDrifted(instance *instance.Instance, nodeClass *v1.EC2NodeClass) cloudprovider.DriftReason {
+	if nodeClass.Spec.Role == "" || nodeClass.Status.InstanceProfile == "" || instance.InstanceProfile == "" {
+		return ""
+	}
+	if instance.InstanceProfile != nodeClass.Status.InstanceProfile {
+		return InstanceProfileDrift
+	}
+	return ""
+}
+
 func (c *CloudProvider) areStaticFieldsDrifted(nodeClaim *karpv1.NodeClaim, nodeClass *v1.EC2NodeClass) cloudprovider.DriftReason {
 	nodeClassHash, foundNodeClassHash := nodeClass.Annotations[v1.AnnotationEC2NodeClassHash]
 	nodeClassHashVersion, foundNodeClassHashVersion := nodeClass.Annotations[v1.AnnotationEC2NodeClassHashVersion]
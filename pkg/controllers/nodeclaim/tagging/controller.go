@@ -38,6 +38,7 @@ import (
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/utils"
 
@@ -73,9 +74,17 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClaim *karpv1.NodeClaim)
 		log.FromContext(ctx).Error(err, "failed parsing instance id")
 		return reconcile.Result{}, nil
 	}
+	role, err := account.Role(ctx, c.kubeClient, nodeClaim.Annotations[v1.AnnotationAssumeRoleARN])
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("resolving assumed role, %w", err)
+	}
+	ctx = account.IntoContext(ctx, role)
 	if err = c.tagInstance(ctx, nodeClaim, id); err != nil {
 		return reconcile.Result{}, cloudprovider.IgnoreNodeClaimNotFoundError(err)
 	}
+	if err = c.tagVolumes(ctx, nodeClaim, id); err != nil {
+		return reconcile.Result{}, cloudprovider.IgnoreNodeClaimNotFoundError(err)
+	}
 	nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{
 		v1.AnnotationInstanceTagged:                 "true",
 		v1.AnnotationClusterNameTaggedCompatability: "true",
@@ -128,6 +137,42 @@ func (c *Controller) tagInstance(ctx context.Context, nc *karpv1.NodeClaim, id s
 	return nil
 }
 
+// tagVolumes applies the tags from the EC2NodeClass' block device mappings to the corresponding volumes. Tags which
+// apply to all of an instance's volumes are applied at launch, but launch templates don't support per-volume tags.
+func (c *Controller) tagVolumes(ctx context.Context, nc *karpv1.NodeClaim, id string) error {
+	nodeClass := &v1.EC2NodeClass{}
+	if err := c.kubeClient.Get(ctx, client.ObjectKey{Name: nc.Spec.NodeClassRef.Name}, nodeClass); err != nil {
+		// The volumes can't be tagged if the EC2NodeClass has already been deleted
+		return client.IgnoreNotFound(err)
+	}
+	bdms := lo.Filter(nodeClass.Spec.BlockDeviceMappings, func(bdm *v1.BlockDeviceMapping, _ int) bool {
+		return bdm.DeviceName != nil && len(bdm.Tags) > 0
+	})
+	if len(bdms) == 0 {
+		return nil
+	}
+	instance, err := c.instanceProvider.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("tagging volumes, %w", err)
+	}
+	for _, bdm := range bdms {
+		volumeID, ok := instance.Volumes[*bdm.DeviceName]
+		if !ok {
+			continue
+		}
+		if err := c.tagVolume(ctx, volumeID, bdm.Tags); err != nil {
+			return fmt.Errorf("tagging volume %s, %w", volumeID, err)
+		}
+	}
+	return nil
+}
+
+func (c *Controller) tagVolume(ctx context.Context, volumeID string, tags map[string]string) error {
+	// Rate limited for the same reason as instance tagging
+	defer time.Sleep(time.Second)
+	return c.instanceProvider.CreateTags(ctx, volumeID, tags)
+}
+
 func isTaggable(nc *karpv1.NodeClaim) bool {
 	// Instance has already been tagged
 	instanceTagged := nc.Annotations[v1.AnnotationInstanceTagged]
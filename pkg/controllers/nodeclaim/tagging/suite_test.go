@@ -17,14 +17,18 @@ package tagging_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/karpenter/pkg/events"
 	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/awslabs/operatorpkg/object"
 	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/resource"
 	corev1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -236,4 +240,103 @@ var _ = Describe("TaggingController", func() {
 		Entry("with the karpenter.sh/nodeclaim and Name tags", v1.EKSClusterNameTagKey),
 		Entry("with nothing to tag", v1.NodeClaimTagKey, v1.EKSClusterNameTagKey, v1.NameTagKey),
 	)
+
+	Context("Volume Tags", func() {
+		var nodeClass *v1.EC2NodeClass
+		var nodeClaim *karpv1.NodeClaim
+
+		BeforeEach(func() {
+			nodeClass = test.EC2NodeClass(v1.EC2NodeClass{
+				Spec: v1.EC2NodeClassSpec{
+					BlockDeviceMappings: []*v1.BlockDeviceMapping{
+						{
+							DeviceName: aws.String("/dev/xvda"),
+							EBS:        &v1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("20Gi"))},
+							RootVolume: true,
+						},
+						{
+							DeviceName: aws.String("/dev/xvdb"),
+							EBS:        &v1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("100Gi"))},
+							Tags:       map[string]string{"backup": "daily"},
+						},
+					},
+				},
+			})
+			nodeClaim = coretest.NodeClaim(karpv1.NodeClaim{
+				Spec: karpv1.NodeClaimSpec{
+					NodeClassRef: &karpv1.NodeClassReference{
+						Group: object.GVK(nodeClass).Group,
+						Kind:  object.GVK(nodeClass).Kind,
+						Name:  nodeClass.Name,
+					},
+				},
+				Status: karpv1.NodeClaimStatus{
+					ProviderID: fake.ProviderID(*ec2Instance.InstanceId),
+					NodeName:   "default",
+				},
+			})
+			ec2Instance.BlockDeviceMappings = []ec2types.InstanceBlockDeviceMapping{
+				{DeviceName: aws.String("/dev/xvda"), Ebs: &ec2types.EbsInstanceBlockDevice{VolumeId: aws.String("vol-root")}},
+				{DeviceName: aws.String("/dev/xvdb"), Ebs: &ec2types.EbsInstanceBlockDevice{VolumeId: aws.String("vol-data")}},
+			}
+			awsEnv.EC2API.Instances.Store(aws.ToString(ec2Instance.InstanceId), ec2Instance)
+		})
+		volumeTagCalls := func() []*ec2.CreateTagsInput {
+			var calls []*ec2.CreateTagsInput
+			awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.ForEach(func(input *ec2.CreateTagsInput) {
+				if lo.ContainsBy(input.Resources, func(id string) bool { return strings.HasPrefix(id, "vol-") }) {
+					calls = append(calls, input)
+				}
+			})
+			return calls
+		}
+
+		It("should tag volumes with their block device mapping's tags", func() {
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKey(v1.AnnotationInstanceTagged))
+
+			calls := volumeTagCalls()
+			Expect(calls).To(HaveLen(1))
+			Expect(calls[0].Resources).To(ConsistOf("vol-data"))
+			Expect(calls[0].Tags).To(ConsistOf(ec2types.Tag{Key: aws.String("backup"), Value: aws.String("daily")}))
+		})
+		It("shouldn't tag volumes which aren't attached to the instance", func() {
+			ec2Instance.BlockDeviceMappings = ec2Instance.BlockDeviceMappings[:1]
+			awsEnv.EC2API.Instances.Store(aws.ToString(ec2Instance.InstanceId), ec2Instance)
+
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKey(v1.AnnotationInstanceTagged))
+			Expect(volumeTagCalls()).To(BeEmpty())
+		})
+		It("should tag the instance when the EC2NodeClass has been deleted", func() {
+			ExpectApplied(ctx, env.Client, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKey(v1.AnnotationInstanceTagged))
+			Expect(volumeTagCalls()).To(BeEmpty())
+		})
+		It("shouldn't mark the instance as tagged when volume tagging fails", func() {
+			// Pre-populate the instance's tags so that the volume is the only resource which is tagged
+			ec2Instance.Tags = append(ec2Instance.Tags,
+				ec2types.Tag{Key: aws.String(v1.NameTagKey), Value: aws.String("default")},
+				ec2types.Tag{Key: aws.String(v1.NodeClaimTagKey), Value: aws.String(nodeClaim.Name)},
+			)
+			awsEnv.EC2API.Instances.Store(aws.ToString(ec2Instance.InstanceId), ec2Instance)
+			awsEnv.EC2API.CreateTagsBehavior.Error.Set(fmt.Errorf("failed"), fake.MaxCalls(1))
+
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			_ = ExpectObjectReconcileFailed(ctx, env.Client, taggingController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).ToNot(HaveKey(v1.AnnotationInstanceTagged))
+
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKey(v1.AnnotationInstanceTagged))
+			Expect(volumeTagCalls()).To(HaveLen(1))
+		})
+	})
 })
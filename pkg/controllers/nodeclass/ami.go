@@ -22,25 +22,41 @@ import (
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 	"sigs.k8s.io/karpenter/pkg/utils/pretty"
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
 )
 
+const amiRolloutPollPeriod = time.Minute
+
 type AMI struct {
-	amiProvider amifamily.Provider
-	cm          *pretty.ChangeMonitor
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+	amiProvider   amifamily.Provider
+	clk           clock.Clock
+	cm            *pretty.ChangeMonitor
 }
 
-func NewAMIReconciler(provider amifamily.Provider) *AMI {
+func NewAMIReconciler(clk clock.Clock, kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, provider amifamily.Provider) *AMI {
 	return &AMI{
-		amiProvider: provider,
-		cm:          pretty.NewChangeMonitor(),
+		kubeClient:    kubeClient,
+		cloudProvider: cloudProvider,
+		amiProvider:   provider,
+		clk:           clk,
+		cm:            pretty.NewChangeMonitor(),
 	}
 }
 
@@ -51,6 +67,7 @@ func (a *AMI) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass) (reconc
 	}
 	if len(amis) == 0 {
 		nodeClass.Status.AMIs = nil
+		nodeClass.Status.AMIRollout = nil
 		nodeClass.StatusConditions().SetFalse(v1.ConditionTypeAMIsReady, "AMINotFound", "AMISelector did not match any AMIs")
 		// If users have omitted the necessary tags from their AMIs and later add them, we need to reprocess the information.
 		// Returning 'ok' in this case means that the nodeclass will remain in an unready state until the component is restarted.
@@ -62,7 +79,7 @@ func (a *AMI) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass) (reconc
 		log.FromContext(ctx).WithValues("ids", uniqueAMIs).V(1).Info("discovered amis")
 	}
 
-	nodeClass.Status.AMIs = lo.Map(amis, func(ami amifamily.AMI, _ int) v1.AMI {
+	statusAMIs := lo.Map(amis, func(ami amifamily.AMI, _ int) v1.AMI {
 		reqs := lo.Map(ami.Requirements.NodeSelectorRequirements(), func(item karpv1.NodeSelectorRequirementWithMinValues, _ int) corev1.NodeSelectorRequirement {
 			return item.NodeSelectorRequirement
 		})
@@ -82,5 +99,76 @@ func (a *AMI) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass) (reconc
 	})
 
 	nodeClass.StatusConditions().SetTrue(v1.ConditionTypeAMIsReady)
+	// Without a rollout policy, or on initial discovery, there's nothing to canary against so AMIs are promoted immediately
+	if nodeClass.Spec.AMIRolloutPolicy == nil || len(nodeClass.Status.AMIs) == 0 || sameAMIs(nodeClass.Status.AMIs, statusAMIs) {
+		nodeClass.Status.AMIs = statusAMIs
+		nodeClass.Status.AMIRollout = nil
+		return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+	}
+	return a.rollout(ctx, nodeClass, statusAMIs)
+}
+
+// rollout soaks the discovered AMIs as candidates, promoting them to status.amis once they've run for the soak duration
+// without any nodes launched from them failing a health check.
+func (a *AMI) rollout(ctx context.Context, nodeClass *v1.EC2NodeClass, candidates []v1.AMI) (reconcile.Result, error) {
+	ids := lo.Map(candidates, func(ami v1.AMI, _ int) string { return ami.ID })
+	if nodeClass.Status.AMIRollout == nil || !sameAMIs(nodeClass.Status.AMIRollout.CandidateAMIs, candidates) {
+		log.FromContext(ctx).WithValues("ids", lo.Uniq(ids)).Info("soaking candidate amis")
+		nodeClass.Status.AMIRollout = &v1.AMIRollout{
+			CandidateAMIs: candidates,
+			SoakStartTime: metav1.NewTime(a.clk.Now()),
+		}
+		return reconcile.Result{RequeueAfter: amiRolloutPollPeriod}, nil
+	}
+	nodeClass.Status.AMIRollout.CandidateAMIs = candidates
+	unhealthy, err := a.unhealthyNodes(ctx, nodeClass, sets.New(ids...))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(unhealthy) != 0 {
+		log.FromContext(ctx).WithValues("ids", lo.Uniq(ids), "nodes", unhealthy).Info("restarting candidate ami soak, nodes failed health checks")
+		nodeClass.Status.AMIRollout.SoakStartTime = metav1.NewTime(a.clk.Now())
+		return reconcile.Result{RequeueAfter: amiRolloutPollPeriod}, nil
+	}
+	if a.clk.Since(nodeClass.Status.AMIRollout.SoakStartTime.Time) < nodeClass.Spec.AMIRolloutPolicy.SoakDuration.Duration {
+		return reconcile.Result{RequeueAfter: amiRolloutPollPeriod}, nil
+	}
+	log.FromContext(ctx).WithValues("ids", lo.Uniq(ids)).Info("promoting candidate amis")
+	nodeClass.Status.AMIs = candidates
+	nodeClass.Status.AMIRollout = nil
 	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
 }
+
+// unhealthyNodes returns the names of nodes launched from the given AMIs which have matched one of the cloudprovider's
+// repair policies for longer than its toleration duration
+func (a *AMI) unhealthyNodes(ctx context.Context, nodeClass *v1.EC2NodeClass, amiIDs sets.Set[string]) ([]string, error) {
+	nodeClaims := &karpv1.NodeClaimList{}
+	if err := a.kubeClient.List(ctx, nodeClaims, nodeclaimutils.ForNodeClass(nodeClass)); err != nil {
+		return nil, fmt.Errorf("listing nodeclaims for nodeclass, %w", err)
+	}
+	var unhealthy []string
+	for _, nodeClaim := range nodeClaims.Items {
+		if nodeClaim.Status.NodeName == "" || !amiIDs.Has(nodeClaim.Status.ImageID) {
+			continue
+		}
+		node := &corev1.Node{}
+		if err := a.kubeClient.Get(ctx, types.NamespacedName{Name: nodeClaim.Status.NodeName}, node); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("getting node, %w", err)
+		}
+		if lo.ContainsBy(a.cloudProvider.RepairPolicies(), func(policy cloudprovider.RepairPolicy) bool {
+			return lo.ContainsBy(node.Status.Conditions, func(c corev1.NodeCondition) bool {
+				return c.Type == policy.ConditionType && c.Status == policy.ConditionStatus && a.clk.Since(c.LastTransitionTime.Time) >= policy.TolerationDuration
+			})
+		}) {
+			unhealthy = append(unhealthy, node.Name)
+		}
+	}
+	return unhealthy, nil
+}
+
+func sameAMIs(a, b []v1.AMI) bool {
+	return sets.New(lo.Map(a, func(ami v1.AMI, _ int) string { return ami.ID })...).Equal(sets.New(lo.Map(b, func(ami v1.AMI, _ int) string { return ami.ID })...))
+}
@@ -21,9 +21,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/awslabs/operatorpkg/object"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
@@ -781,4 +784,132 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 			Expect(nodeClass.StatusConditions().IsTrue(v1.ConditionTypeAMIsReady)).To(BeTrue())
 		})
 	})
+	Context("AMI Rollout", func() {
+		BeforeEach(func() {
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{ID: "ami-amd64-standard"}}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-amd64-standard"))
+		})
+		It("should promote newly discovered AMIs immediately without a rollout policy", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{ID: "ami-amd64-standard-new"}}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-amd64-standard-new"))
+			Expect(nodeClass.Status.AMIRollout).To(BeNil())
+		})
+		It("should soak newly discovered AMIs as candidates", func() {
+			nodeClass.Spec.AMIRolloutPolicy = &v1.AMIRolloutPolicy{SoakDuration: metav1.Duration{Duration: time.Hour}}
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{ID: "ami-amd64-standard-new"}}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-amd64-standard"))
+			Expect(nodeClass.Status.AMIRollout).ToNot(BeNil())
+			Expect(nodeClass.Status.AMIRollout.CandidateAMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIRollout.CandidateAMIs[0].ID).To(Equal("ami-amd64-standard-new"))
+
+			// The candidate shouldn't be promoted before the soak duration has elapsed
+			awsEnv.Clock.Step(30 * time.Minute)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-amd64-standard"))
+			Expect(nodeClass.Status.AMIRollout).ToNot(BeNil())
+		})
+		It("should promote candidate AMIs after the soak duration", func() {
+			nodeClass.Spec.AMIRolloutPolicy = &v1.AMIRolloutPolicy{SoakDuration: metav1.Duration{Duration: time.Hour}}
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{ID: "ami-amd64-standard-new"}}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+
+			awsEnv.Clock.Step(time.Hour)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-amd64-standard-new"))
+			Expect(nodeClass.Status.AMIRollout).To(BeNil())
+		})
+		It("should restart the soak when a node launched from a candidate AMI is unhealthy", func() {
+			nodeClass.Spec.AMIRolloutPolicy = &v1.AMIRolloutPolicy{SoakDuration: metav1.Duration{Duration: time.Hour}}
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{ID: "ami-amd64-standard-new"}}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+
+			node := coretest.Node()
+			nodeClaim := coretest.NodeClaim(karpv1.NodeClaim{
+				Spec: karpv1.NodeClaimSpec{
+					NodeClassRef: &karpv1.NodeClassReference{
+						Group: object.GVK(nodeClass).Group,
+						Kind:  object.GVK(nodeClass).Kind,
+						Name:  nodeClass.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, node, nodeClaim)
+			nodeClaim.Status.ImageID = "ami-amd64-standard-new"
+			nodeClaim.Status.NodeName = node.Name
+			ExpectApplied(ctx, env.Client, nodeClaim)
+			node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(awsEnv.Clock.Now())}}
+			ExpectApplied(ctx, env.Client, node)
+
+			awsEnv.Clock.Step(time.Hour)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-amd64-standard"))
+			Expect(nodeClass.Status.AMIRollout).ToNot(BeNil())
+			Expect(nodeClass.Status.AMIRollout.SoakStartTime.Time).To(BeTemporally("==", awsEnv.Clock.Now().Truncate(time.Second)))
+		})
+		It("should not restart the soak when a node hasn't been unhealthy for the repair toleration duration", func() {
+			nodeClass.Spec.AMIRolloutPolicy = &v1.AMIRolloutPolicy{SoakDuration: metav1.Duration{Duration: time.Hour}}
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{ID: "ami-amd64-standard-new"}}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+
+			node := coretest.Node()
+			nodeClaim := coretest.NodeClaim(karpv1.NodeClaim{
+				Spec: karpv1.NodeClaimSpec{
+					NodeClassRef: &karpv1.NodeClassReference{
+						Group: object.GVK(nodeClass).Group,
+						Kind:  object.GVK(nodeClass).Kind,
+						Name:  nodeClass.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, node, nodeClaim)
+			nodeClaim.Status.ImageID = "ami-amd64-standard-new"
+			nodeClaim.Status.NodeName = node.Name
+			ExpectApplied(ctx, env.Client, nodeClaim)
+
+			// The node becomes NotReady a minute before the soak ends, which is within the 30 minute repair toleration
+			awsEnv.Clock.Step(59 * time.Minute)
+			node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(awsEnv.Clock.Now())}}
+			ExpectApplied(ctx, env.Client, node)
+
+			awsEnv.Clock.Step(time.Minute)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-amd64-standard-new"))
+			Expect(nodeClass.Status.AMIRollout).To(BeNil())
+		})
+		It("should promote newly discovered AMIs immediately when the rollout policy is removed", func() {
+			nodeClass.Spec.AMIRolloutPolicy = &v1.AMIRolloutPolicy{SoakDuration: metav1.Duration{Duration: time.Hour}}
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{ID: "ami-amd64-standard-new"}}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIRollout).ToNot(BeNil())
+
+			nodeClass.Spec.AMIRolloutPolicy = nil
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-amd64-standard-new"))
+			Expect(nodeClass.Status.AMIRollout).To(BeNil())
+		})
+	})
 })
@@ -0,0 +1,120 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeclass
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
+	awserrors "github.com/aws/karpenter-provider-aws/pkg/errors"
+)
+
+const (
+	ConditionReasonKMSKeyNotFound       = "KMSKeyNotFound"
+	ConditionReasonKMSKeyUnauthorized   = "KMSKeyUnauthorized"
+	ConditionReasonKMSKeyInvalid        = "KMSKeyInvalid"
+	ConditionReasonKMSKeyUnknown        = "KMSKeyUnknown"
+	ConditionReasonEncryptionIsDisabled = "EncryptionIsDisabled"
+)
+
+type BlockDeviceMapping struct {
+	kmsapi sdk.KMSAPI
+}
+
+func NewBlockDeviceMappingReconciler(kmsapi sdk.KMSAPI) *BlockDeviceMapping {
+	return &BlockDeviceMapping{
+		kmsapi: kmsapi,
+	}
+}
+
+// Reconcile validates the KMS keys referenced by the EC2NodeClass' block device mappings. EC2 doesn't validate these
+// keys at launch, and instances with volumes that can't be encrypted are terminated shortly after they're launched
+// with a Client.InternalError state reason. BlockDeviceMappingsReady isn't a readiness condition: the controller isn't
+// required to be able to describe keys that EC2 uses through grants, so it's only set to false when a key is known to be
+// unusable.
+func (b *BlockDeviceMapping) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass) (reconcile.Result, error) {
+	var unknownReason, unknownMessage string
+	for _, bdm := range nodeClass.Spec.BlockDeviceMappings {
+		if bdm.EBS == nil || lo.FromPtr(bdm.EBS.KMSKeyID) == "" {
+			continue
+		}
+		deviceName := lo.FromPtr(bdm.DeviceName)
+		if bdm.EBS.Encrypted != nil && !*bdm.EBS.Encrypted {
+			nodeClass.StatusConditions().SetFalse(
+				v1.ConditionTypeBlockDeviceMappingsReady,
+				ConditionReasonEncryptionIsDisabled,
+				fmt.Sprintf("Block device %q specifies a kmsKeyID but disables encryption", deviceName),
+			)
+			return reconcile.Result{}, nil
+		}
+		out, err := b.kmsapi.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: bdm.EBS.KMSKeyID})
+		if err != nil {
+			if awserrors.IsKMSNotFound(err) {
+				nodeClass.StatusConditions().SetFalse(
+					v1.ConditionTypeBlockDeviceMappingsReady,
+					ConditionReasonKMSKeyNotFound,
+					fmt.Sprintf("Block device %q, kms key %q not found", deviceName, *bdm.EBS.KMSKeyID),
+				)
+				// Key policies and grants can be updated outside of the EC2NodeClass, so we need to revalidate periodically
+				return reconcile.Result{RequeueAfter: time.Minute}, nil
+			}
+			if unknownReason == "" {
+				unknownReason, unknownMessage = ConditionReasonKMSKeyUnknown, fmt.Sprintf("Block device %q, unable to describe kms key %q", deviceName, *bdm.EBS.KMSKeyID)
+				if awserrors.IsKMSAccessDenied(err) {
+					unknownReason, unknownMessage = ConditionReasonKMSKeyUnauthorized, fmt.Sprintf("Block device %q, controller is not authorized to describe kms key %q", deviceName, *bdm.EBS.KMSKeyID)
+				}
+			}
+			log.FromContext(ctx).WithValues("kms-key-id", *bdm.EBS.KMSKeyID, "error", err).V(1).Info("unable to validate kms key")
+			continue
+		}
+		if message := invalidKMSKeyMessage(*bdm.EBS.KMSKeyID, out.KeyMetadata); message != "" {
+			nodeClass.StatusConditions().SetFalse(
+				v1.ConditionTypeBlockDeviceMappingsReady,
+				ConditionReasonKMSKeyInvalid,
+				fmt.Sprintf("Block device %q, %s", deviceName, message),
+			)
+			return reconcile.Result{RequeueAfter: time.Minute}, nil
+		}
+	}
+	if unknownReason != "" {
+		nodeClass.StatusConditions().SetUnknownWithReason(v1.ConditionTypeBlockDeviceMappingsReady, unknownReason, unknownMessage)
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+	nodeClass.StatusConditions().SetTrue(v1.ConditionTypeBlockDeviceMappingsReady)
+	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// invalidKMSKeyMessage returns a message if the described key can't be used to encrypt EBS volumes. Key policies
+// commonly restrict cryptographic operations to requests made through EC2 (kms:ViaService), so we can't call them
+// directly to validate that EBS is able to use the key, and only validate the key's state and spec.
+func invalidKMSKeyMessage(keyID string, metadata *kmstypes.KeyMetadata) string {
+	if metadata.KeyState != kmstypes.KeyStateEnabled {
+		return fmt.Sprintf("kms key %q is %s", keyID, metadata.KeyState)
+	}
+	// EBS only supports symmetric encryption keys
+	if metadata.KeyUsage != kmstypes.KeyUsageTypeEncryptDecrypt || metadata.KeySpec != kmstypes.KeySpecSymmetricDefault {
+		return fmt.Sprintf("kms key %q is not a symmetric encryption key", keyID)
+	}
+	return ""
+}
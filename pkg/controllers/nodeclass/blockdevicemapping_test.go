@@ -0,0 +1,156 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeclass_test
+
+import (
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/smithy-go"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclass"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+)
+
+var _ = Describe("NodeClass Block Device Mapping Status Controller", func() {
+	const keyID = "1234abcd-12ab-34cd-56ef-1234567890ab"
+
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass(v1.EC2NodeClass{
+			Spec: v1.EC2NodeClassSpec{
+				BlockDeviceMappings: []*v1.BlockDeviceMapping{
+					{
+						DeviceName: lo.ToPtr("/dev/xvda"),
+						EBS: &v1.BlockDevice{
+							VolumeSize: lo.ToPtr(resource.MustParse("20Gi")),
+							Encrypted:  lo.ToPtr(true),
+							KMSKeyID:   lo.ToPtr(keyID),
+						},
+						RootVolume: true,
+					},
+				},
+			},
+		})
+	})
+	It("should set BlockDeviceMappingsReady when no KMS keys are specified", func() {
+		nodeClass.Spec.BlockDeviceMappings[0].EBS.KMSKeyID = nil
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).IsTrue()).To(BeTrue())
+		Expect(awsEnv.KMSAPI.DescribeKeyBehavior.Calls()).To(Equal(0))
+	})
+	It("should set BlockDeviceMappingsReady when the KMS key is usable", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).IsTrue()).To(BeTrue())
+
+		input := awsEnv.KMSAPI.DescribeKeyBehavior.CalledWithInput.Pop()
+		Expect(lo.FromPtr(input.KeyId)).To(Equal(keyID))
+	})
+	It("should set BlockDeviceMappingsReady to false when the KMS key doesn't exist", func() {
+		awsEnv.KMSAPI.DescribeKeyBehavior.Error.Set(&kmstypes.NotFoundException{Message: lo.ToPtr("key not found")})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).Reason).To(Equal(nodeclass.ConditionReasonKMSKeyNotFound))
+		// BlockDeviceMappingsReady doesn't affect the readiness of the EC2NodeClass
+		Expect(nodeClass.StatusConditions().Root().IsTrue()).To(BeTrue())
+	})
+	It("should set BlockDeviceMappingsReady to unknown when the controller isn't authorized to describe the KMS key", func() {
+		awsEnv.KMSAPI.DescribeKeyBehavior.Error.Set(&smithy.GenericAPIError{
+			Code:    "AccessDeniedException",
+			Message: "User is not authorized to perform: kms:DescribeKey",
+		})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).IsUnknown()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).Reason).To(Equal(nodeclass.ConditionReasonKMSKeyUnauthorized))
+		Expect(nodeClass.StatusConditions().Root().IsTrue()).To(BeTrue())
+	})
+	It("should set BlockDeviceMappingsReady to unknown when the KMS key can't be described", func() {
+		awsEnv.KMSAPI.DescribeKeyBehavior.Error.Set(&smithy.GenericAPIError{Code: "KMSInternalException"})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).IsUnknown()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).Reason).To(Equal(nodeclass.ConditionReasonKMSKeyUnknown))
+		Expect(nodeClass.Status.ObservedGeneration).To(Equal(nodeClass.Generation))
+	})
+	DescribeTable(
+		"should set BlockDeviceMappingsReady to false when the KMS key can't encrypt volumes",
+		func(metadata kmstypes.KeyMetadata) {
+			awsEnv.KMSAPI.Keys[keyID] = &metadata
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).IsFalse()).To(BeTrue())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).Reason).To(Equal(nodeclass.ConditionReasonKMSKeyInvalid))
+		},
+		Entry("when the key is disabled", kmstypes.KeyMetadata{
+			KeyId:    lo.ToPtr(keyID),
+			KeyState: kmstypes.KeyStateDisabled,
+			KeyUsage: kmstypes.KeyUsageTypeEncryptDecrypt,
+			KeySpec:  kmstypes.KeySpecSymmetricDefault,
+		}),
+		Entry("when the key is pending deletion", kmstypes.KeyMetadata{
+			KeyId:    lo.ToPtr(keyID),
+			KeyState: kmstypes.KeyStatePendingDeletion,
+			KeyUsage: kmstypes.KeyUsageTypeEncryptDecrypt,
+			KeySpec:  kmstypes.KeySpecSymmetricDefault,
+		}),
+		Entry("when the key is asymmetric", kmstypes.KeyMetadata{
+			KeyId:    lo.ToPtr(keyID),
+			KeyState: kmstypes.KeyStateEnabled,
+			KeyUsage: kmstypes.KeyUsageTypeEncryptDecrypt,
+			KeySpec:  kmstypes.KeySpecRsa2048,
+		}),
+		Entry("when the key is a signing key", kmstypes.KeyMetadata{
+			KeyId:    lo.ToPtr(keyID),
+			KeyState: kmstypes.KeyStateEnabled,
+			KeyUsage: kmstypes.KeyUsageTypeSignVerify,
+			KeySpec:  kmstypes.KeySpecEccNistP256,
+		}),
+	)
+	It("should set BlockDeviceMappingsReady to false when a KMS key is specified with encryption disabled", func() {
+		nodeClass.Spec.BlockDeviceMappings[0].EBS.Encrypted = lo.ToPtr(false)
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).Reason).To(Equal(nodeclass.ConditionReasonEncryptionIsDisabled))
+		Expect(awsEnv.KMSAPI.DescribeKeyBehavior.Calls()).To(Equal(0))
+	})
+	It("should set BlockDeviceMappingsReady once the KMS key becomes usable", func() {
+		awsEnv.KMSAPI.DescribeKeyBehavior.Error.Set(&kmstypes.NotFoundException{Message: lo.ToPtr("key not found")})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).IsFalse()).To(BeTrue())
+
+		awsEnv.KMSAPI.DescribeKeyBehavior.Error.Reset()
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeBlockDeviceMappingsReady).IsTrue()).To(BeTrue())
+	})
+})
@@ -28,6 +28,7 @@ import (
 	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 	"sigs.k8s.io/karpenter/pkg/utils/result"
 
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -50,11 +51,13 @@ import (
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/permission"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 )
@@ -82,11 +85,13 @@ func NewController(
 	instanceTypeProvider instancetype.Provider,
 	launchTemplateProvider launchtemplate.Provider,
 	capacityReservationProvider capacityreservation.Provider,
+	permissionProvider permission.Provider,
 	ec2api sdk.EC2API,
+	kmsapi sdk.KMSAPI,
 	validationCache *cache.Cache,
 	amiResolver amifamily.Resolver,
 ) *Controller {
-	validation := NewValidationReconciler(kubeClient, cloudProvider, ec2api, amiResolver, instanceTypeProvider, launchTemplateProvider, validationCache)
+	validation := NewValidationReconciler(kubeClient, cloudProvider, recorder, ec2api, subnetProvider, securityGroupProvider, amiResolver, instanceTypeProvider, launchTemplateProvider, permissionProvider, kmsapi, validationCache)
 	return &Controller{
 		kubeClient:              kubeClient,
 		recorder:                recorder,
@@ -95,11 +100,13 @@ func NewController(
 		instanceProfileProvider: instanceProfileProvider,
 		validation:              validation,
 		reconcilers: []reconcile.TypedReconciler[*v1.EC2NodeClass]{
-			NewAMIReconciler(amiProvider),
+			NewAMIReconciler(clk, kubeClient, cloudProvider, amiProvider),
 			NewCapacityReservationReconciler(clk, capacityReservationProvider),
-			NewSubnetReconciler(subnetProvider),
+			NewSubnetReconciler(subnetProvider, recorder),
 			NewSecurityGroupReconciler(securityGroupProvider),
-			NewInstanceProfileReconciler(instanceProfileProvider, region),
+			NewInstanceTypeReconciler(instanceTypeProvider),
+			NewInstanceProfileReconciler(clk, instanceProfileProvider, region),
+			NewBlockDeviceMappingReconciler(kmsapi),
 			validation,
 			NewReadinessReconciler(launchTemplateProvider),
 		},
@@ -113,6 +120,7 @@ func (c *Controller) Name() string {
 //nolint:gocyclo
 func (c *Controller) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass) (reconcile.Result, error) {
 	ctx = injection.WithControllerName(ctx, c.Name())
+	ctx = account.IntoContext(ctx, nodeClass.Spec.AssumeRole)
 
 	if !nodeClass.GetDeletionTimestamp().IsZero() {
 		return c.finalize(ctx, nodeClass)
@@ -140,6 +148,10 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass)
 		if _, ok := reconciler.(*CapacityReservation); ok && !karpoptions.FromContext(ctx).FeatureGates.ReservedCapacity {
 			continue
 		}
+		// The resolved status reflects the current spec once every reconciler ahead of validation has succeeded
+		if _, ok := reconciler.(*Validation); ok && errs == nil {
+			nodeClass.Status.ObservedGeneration = nodeClass.Generation
+		}
 		res, err := reconciler.Reconcile(ctx, nodeClass)
 		errs = multierr.Append(errs, err)
 		results = append(results, res)
@@ -176,8 +188,20 @@ func (c *Controller) finalize(ctx context.Context, nodeClass *v1.EC2NodeClass) (
 		return reconcile.Result{RequeueAfter: time.Minute * 10}, nil // periodically fire the event
 	}
 	if nodeClass.Spec.Role != "" {
-		if err := c.instanceProfileProvider.Delete(ctx, nodeClass.InstanceProfileName(options.FromContext(ctx).ClusterName, c.region)); err != nil {
-			return reconcile.Result{}, fmt.Errorf("deleting instance profile, %w", err)
+		// Instance profiles left behind by a role change are created under the path of the EC2NodeClass, while the
+		// original instance profile may have been created before paths were used
+		profiles, err := c.instanceProfileProvider.List(ctx, nodeClass.InstanceProfilePath(options.FromContext(ctx).ClusterName, c.region))
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("listing instance profiles, %w", err)
+		}
+		names := lo.Uniq(append(
+			lo.Map(profiles, func(p iamtypes.InstanceProfile, _ int) string { return lo.FromPtr(p.InstanceProfileName) }),
+			nodeClass.InstanceProfileName(options.FromContext(ctx).ClusterName, c.region),
+		))
+		for _, name := range names {
+			if err := c.instanceProfileProvider.Delete(ctx, name); err != nil {
+				return reconcile.Result{}, fmt.Errorf("deleting instance profile, %w", err)
+			}
 		}
 	}
 	if err := c.launchTemplateProvider.DeleteAll(ctx, nodeClass); err != nil {
@@ -16,6 +16,7 @@ package nodeclass
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -34,3 +35,37 @@ func WaitingOnNodeClaimTerminationEvent(nodeClass *v1.EC2NodeClass, names []stri
 		DedupeValues:   []string{string(nodeClass.UID)},
 	}
 }
+
+func SubnetPublicIPMismatchEvent(nodeClass *v1.EC2NodeClass, associatePublicIPAddress bool, subnetIDs []string) events.Event {
+	message := fmt.Sprintf("associatePublicIPAddress is true, but subnets %s don't assign public IPs on launch; nodes launched in them will have public IPs", utils.PrettySlice(subnetIDs, 5))
+	if !associatePublicIPAddress {
+		message = fmt.Sprintf("associatePublicIPAddress is false, but subnets %s assign public IPs on launch; nodes launched in them will need a NAT gateway to reach the internet", utils.PrettySlice(subnetIDs, 5))
+	}
+	return events.Event{
+		InvolvedObject: nodeClass,
+		Type:           corev1.EventTypeWarning,
+		Reason:         "SubnetPublicIPMismatch",
+		Message:        message,
+		DedupeValues:   []string{string(nodeClass.UID), fmt.Sprint(associatePublicIPAddress)},
+	}
+}
+
+func SubnetsLowOnIPsEvent(nodeClass *v1.EC2NodeClass, threshold int, subnets []string) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClass,
+		Type:           corev1.EventTypeWarning,
+		Reason:         "SubnetsLowOnIPs",
+		Message:        fmt.Sprintf("Subnets are below %d available IPs, launches in their zones may fail once they run out, %s", threshold, utils.PrettySlice(subnets, 5)),
+		DedupeValues:   []string{string(nodeClass.UID)},
+	}
+}
+
+func PermissionsMissingEvent(nodeClass *v1.EC2NodeClass, missing []string) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClass,
+		Type:           corev1.EventTypeWarning,
+		Reason:         "PermissionsMissing",
+		Message:        fmt.Sprintf("Controller is missing permissions required to launch nodes, %s", strings.Join(missing, "; ")),
+		DedupeValues:   append([]string{string(nodeClass.UID)}, missing...),
+	}
+}
@@ -19,20 +19,25 @@ import (
 	"fmt"
 
 	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	awserrors "github.com/aws/karpenter-provider-aws/pkg/errors"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 )
 
 type InstanceProfile struct {
+	clk                     clock.Clock
 	instanceProfileProvider instanceprofile.Provider
 	region                  string
 }
 
-func NewInstanceProfileReconciler(instanceProfileProvider instanceprofile.Provider, region string) *InstanceProfile {
+func NewInstanceProfileReconciler(clk clock.Clock, instanceProfileProvider instanceprofile.Provider, region string) *InstanceProfile {
 	return &InstanceProfile{
+		clk:                     clk,
 		instanceProfileProvider: instanceProfileProvider,
 		region:                  region,
 	}
@@ -40,15 +45,24 @@ func NewInstanceProfileReconciler(instanceProfileProvider instanceprofile.Provid
 
 func (ip *InstanceProfile) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass) (reconcile.Result, error) {
 	if nodeClass.Spec.Role != "" {
-		profileName := nodeClass.InstanceProfileName(options.FromContext(ctx).ClusterName, ip.region)
+		profileName, err := ip.profileName(ctx, nodeClass)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 		if err := ip.instanceProfileProvider.Create(
 			ctx,
 			profileName,
+			nodeClass.InstanceProfilePath(options.FromContext(ctx).ClusterName, ip.region),
 			nodeClass.InstanceProfileRole(),
 			nodeClass.InstanceProfileTags(options.FromContext(ctx).ClusterName, ip.region),
 		); err != nil {
 			return reconcile.Result{}, fmt.Errorf("creating instance profile, %w", err)
 		}
+		// The rotation time delays garbage collection of the previous instance profile, which may still be referenced
+		// by launch templates that are in use
+		if nodeClass.Status.InstanceProfile != "" && nodeClass.Status.InstanceProfile != profileName {
+			nodeClass.Status.InstanceProfileRotationTime = lo.ToPtr(metav1.NewTime(ip.clk.Now()))
+		}
 		nodeClass.Status.InstanceProfile = profileName
 	} else {
 		nodeClass.Status.InstanceProfile = lo.FromPtr(nodeClass.Spec.InstanceProfile)
@@ -56,3 +70,26 @@ func (ip *InstanceProfile) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeC
 	nodeClass.StatusConditions().SetTrue(v1.ConditionTypeInstanceProfileReady)
 	return reconcile.Result{}, nil
 }
+
+// profileName returns the name of the instance profile that should be used for the role of the EC2NodeClass. The
+// instance profile in the status continues to be used for as long as it has the role attached. When the role changes,
+// a new instance profile is created rather than swapping the role on the existing one, since the running instances
+// that use it would otherwise assume the new role. The previous instance profile is garbage collected once no
+// instances reference it.
+func (ip *InstanceProfile) profileName(ctx context.Context, nodeClass *v1.EC2NodeClass) (string, error) {
+	clusterName := options.FromContext(ctx).ClusterName
+	if nodeClass.Status.InstanceProfile == "" {
+		return nodeClass.InstanceProfileName(clusterName, ip.region), nil
+	}
+	profile, err := ip.instanceProfileProvider.Get(ctx, nodeClass.Status.InstanceProfile)
+	if err != nil {
+		if !awserrors.IsNotFound(err) {
+			return "", fmt.Errorf("getting instance profile, %w", err)
+		}
+		return nodeClass.RoleInstanceProfileName(clusterName, ip.region), nil
+	}
+	if len(profile.Roles) == 1 && lo.FromPtr(profile.Roles[0].RoleName) == instanceprofile.RoleName(nodeClass.Spec.Role) {
+		return nodeClass.Status.InstanceProfile, nil
+	}
+	return nodeClass.RoleInstanceProfileName(clusterName, ip.region), nil
+}
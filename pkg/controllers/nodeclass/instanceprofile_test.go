@@ -16,6 +16,7 @@ package nodeclass_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
@@ -164,4 +165,81 @@ var _ = Describe("NodeClass InstanceProfile Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.StatusConditions().IsTrue(v1.ConditionTypeInstanceProfileReady)).To(BeTrue())
 	})
+	It("should create the instance profile under the path of the nodeClass", func() {
+		nodeClass.Spec.Role = "test-role"
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+
+		Expect(aws.ToString(awsEnv.IAMAPI.InstanceProfiles[profileName].Path)).To(Equal(
+			fmt.Sprintf("/karpenter/%s/%s/%s/", fake.DefaultRegion, options.FromContext(ctx).ClusterName, nodeClass.Name),
+		))
+	})
+	Context("Role Rotation", func() {
+		It("should create a new instance profile when the role changes", func() {
+			nodeClass.Spec.Role = "test-role"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.InstanceProfile).To(Equal(profileName))
+			Expect(nodeClass.Status.InstanceProfileRotationTime).To(BeNil())
+
+			nodeClass.Spec.Role = "other-role"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			rotatedProfileName := nodeClass.RoleInstanceProfileName(options.FromContext(ctx).ClusterName, fake.DefaultRegion)
+			Expect(rotatedProfileName).ToNot(Equal(profileName))
+			Expect(nodeClass.Status.InstanceProfile).To(Equal(rotatedProfileName))
+			Expect(nodeClass.Status.InstanceProfileRotationTime).ToNot(BeNil())
+			Expect(nodeClass.Status.InstanceProfileRotationTime.Time.Unix()).To(Equal(awsEnv.Clock.Now().Unix()))
+			Expect(nodeClass.StatusConditions().IsTrue(v1.ConditionTypeInstanceProfileReady)).To(BeTrue())
+
+			// The previous instance profile keeps its role for the instances that are still using it
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(2))
+			Expect(*awsEnv.IAMAPI.InstanceProfiles[profileName].Roles[0].RoleName).To(Equal("test-role"))
+			Expect(*awsEnv.IAMAPI.InstanceProfiles[rotatedProfileName].Roles[0].RoleName).To(Equal("other-role"))
+			Expect(awsEnv.IAMAPI.RemoveRoleFromInstanceProfileBehavior.Calls()).To(BeZero())
+		})
+		It("should keep using the instance profile in the status when the role is unchanged", func() {
+			nodeClass.Spec.Role = "test-role"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass.Spec.Role = "other-role"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			rotatedProfileName := nodeClass.Status.InstanceProfile
+			rotationTime := nodeClass.Status.InstanceProfileRotationTime
+
+			awsEnv.Clock.Step(time.Hour)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.InstanceProfile).To(Equal(rotatedProfileName))
+			Expect(nodeClass.Status.InstanceProfileRotationTime.Equal(rotationTime)).To(BeTrue())
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(2))
+		})
+		It("should not consider the role path when comparing roles", func() {
+			nodeClass.Spec.Role = "CustomPath/test-role"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.InstanceProfile).To(Equal(profileName))
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(1))
+		})
+		It("should delete all instance profiles of the nodeClass when it's deleted", func() {
+			nodeClass.Spec.Role = "test-role"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass.Spec.Role = "other-role"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(2))
+
+			Expect(env.Client.Delete(ctx, nodeClass)).To(Succeed())
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(0))
+		})
+	})
 })
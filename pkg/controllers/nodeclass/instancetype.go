@@ -0,0 +1,117 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeclass
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
+)
+
+const instanceTypePollPeriod = 5 * time.Minute
+
+type InstanceType struct {
+	instanceTypeProvider instancetype.Provider
+}
+
+func NewInstanceTypeReconciler(instanceTypeProvider instancetype.Provider) *InstanceType {
+	return &InstanceType{
+		instanceTypeProvider: instanceTypeProvider,
+	}
+}
+
+func (i *InstanceType) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass) (reconcile.Result, error) {
+	if nodeClass.Spec.InstanceTypeSelector == nil {
+		nodeClass.Status.InstanceTypeSelection = nil
+	}
+	if !options.FromContext(ctx).ReportInstanceTypes {
+		nodeClass.Status.InstanceTypes = nil
+	}
+	// Instance types can't be resolved until subnets and AMIs have been discovered
+	if len(nodeClass.Status.Subnets) == 0 || len(nodeClass.Status.AMIs) == 0 {
+		nodeClass.StatusConditions().SetUnknownWithReason(v1.ConditionTypeInstanceTypesReady, ConditionReasonDependenciesNotReady, "Waiting for subnets and AMIs to be resolved")
+		return reconcile.Result{}, nil
+	}
+	// Errors aren't returned since the status reported by this reconciler doesn't affect launches, and returning an
+	// error would prevent the EC2NodeClass's observed generation from being updated
+	instanceTypes, err := i.instanceTypeProvider.List(ctx, nodeClass)
+	if err != nil {
+		nodeClass.StatusConditions().SetUnknownWithReason(v1.ConditionTypeInstanceTypesReady, "InstanceTypesUnresolved", fmt.Sprintf("Failed to list instance types, %s", err))
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+	if nodeClass.Spec.InstanceTypeSelector != nil {
+		nodeClass.Status.InstanceTypeSelection = selection(instanceTypes)
+	}
+
+	launchable := lo.Filter(lo.Flatten(lo.Values(amifamily.MapToInstanceTypes(instanceTypes, nodeClass.Status.AMIs))), func(it *cloudprovider.InstanceType, _ int) bool {
+		return len(it.Offerings.Available()) > 0
+	})
+	sort.Slice(launchable, func(x, y int) bool { return launchable[x].Name < launchable[y].Name })
+	if len(launchable) == 0 {
+		nodeClass.StatusConditions().SetFalse(v1.ConditionTypeInstanceTypesReady, "InstanceTypesNotFound", "No instance types are compatible with the AMIs and subnet zones, or have available offerings")
+	} else {
+		nodeClass.StatusConditions().SetTrue(v1.ConditionTypeInstanceTypesReady)
+	}
+	if options.FromContext(ctx).ReportInstanceTypes {
+		nodeClass.Status.InstanceTypes = lo.Map(launchable, func(it *cloudprovider.InstanceType, _ int) v1.InstanceType {
+			return inventory(it)
+		})
+	}
+	// Instance types and their offerings are refreshed periodically, so the status is too
+	return reconcile.Result{RequeueAfter: instanceTypePollPeriod}, nil
+}
+
+// selection summarizes the instance types which are selected by the instance type selector
+func selection(instanceTypes []*cloudprovider.InstanceType) *v1.InstanceTypeSelection {
+	families := lo.Uniq(lo.Map(instanceTypes, func(it *cloudprovider.InstanceType, _ int) string {
+		family, _, _ := strings.Cut(it.Name, ".")
+		return family
+	}))
+	sort.Strings(families)
+	return &v1.InstanceTypeSelection{
+		Count:    int32(len(instanceTypes)), //nolint:gosec
+		Families: families,
+	}
+}
+
+// inventory summarizes the zones and capacity types that an instance type has available offerings for
+func inventory(it *cloudprovider.InstanceType) v1.InstanceType {
+	available := it.Offerings.Available()
+	zones := lo.Uniq(lo.Map(available, func(o *cloudprovider.Offering, _ int) string { return o.Zone() }))
+	sort.Strings(zones)
+	counts := lo.CountValuesBy(available, func(o *cloudprovider.Offering) string { return o.CapacityType() })
+	capacityTypes := lo.MapToSlice(counts, func(capacityType string, count int) v1.InstanceTypeCapacity {
+		return v1.InstanceTypeCapacity{CapacityType: capacityType, Available: int32(count)} //nolint:gosec
+	})
+	sort.Slice(capacityTypes, func(i, j int) bool { return capacityTypes[i].CapacityType < capacityTypes[j].CapacityType })
+	return v1.InstanceType{
+		Name:          it.Name,
+		Architecture:  it.Requirements.Get(corev1.LabelArchStable).Any(),
+		Zones:         zones,
+		CapacityTypes: capacityTypes,
+	}
+}
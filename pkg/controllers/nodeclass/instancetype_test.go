@@ -0,0 +1,147 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeclass_test
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/samber/lo"
+	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclass"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+)
+
+var _ = Describe("NodeClass Instance Type Status Controller", func() {
+	It("should summarize the instance types selected by the instance type selector", func() {
+		nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{
+			Allow:            []string{"m5.*", "t3.*"},
+			ExcludeBareMetal: lo.ToPtr(true),
+		}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.InstanceTypeSelection).ToNot(BeNil())
+		Expect(nodeClass.Status.InstanceTypeSelection.Families).To(Equal([]string{"m5", "t3"}))
+
+		instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodeClass.Status.InstanceTypeSelection.Count).To(BeNumerically("==", len(instanceTypes)))
+		Expect(nodeClass.Status.InstanceTypeSelection.Count).To(BeNumerically(">", 0))
+	})
+	It("should report an empty selection when no instance types are selected", func() {
+		nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{Allow: []string{"x9z.*"}}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.InstanceTypeSelection).ToNot(BeNil())
+		Expect(nodeClass.Status.InstanceTypeSelection.Count).To(BeNumerically("==", 0))
+		Expect(nodeClass.Status.InstanceTypeSelection.Families).To(BeEmpty())
+	})
+	It("should clear the selection when the instance type selector is removed", func() {
+		nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{Allow: []string{"m5.*"}}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.InstanceTypeSelection).ToNot(BeNil())
+
+		nodeClass.Spec.InstanceTypeSelector = nil
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.InstanceTypeSelection).To(BeNil())
+	})
+	Context("Inventory", func() {
+		It("should set InstanceTypesReady without reporting the instance types by default", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeInstanceTypesReady).IsTrue()).To(BeTrue())
+			Expect(nodeClass.Status.InstanceTypes).To(BeNil())
+		})
+		It("should report the zones and capacity types of the instance types when enabled", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{ReportInstanceTypes: lo.ToPtr(true)}))
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeInstanceTypesReady).IsTrue()).To(BeTrue())
+			Expect(nodeClass.Status.InstanceTypes).ToNot(BeEmpty())
+			Expect(lo.IsSortedByKey(nodeClass.Status.InstanceTypes, func(it v1.InstanceType) string { return it.Name })).To(BeTrue())
+
+			m5, ok := lo.Find(nodeClass.Status.InstanceTypes, func(it v1.InstanceType) bool { return it.Name == "m5.large" })
+			Expect(ok).To(BeTrue())
+			Expect(m5.Architecture).To(Equal(karpv1.ArchitectureAmd64))
+			Expect(m5.Zones).ToNot(BeEmpty())
+			Expect(lo.Every(lo.Map(nodeClass.Status.Subnets, func(s v1.Subnet, _ int) string { return s.Zone }), m5.Zones)).To(BeTrue())
+			Expect(m5.CapacityTypes).To(ContainElement(HaveField("CapacityType", karpv1.CapacityTypeOnDemand)))
+			Expect(lo.EveryBy(m5.CapacityTypes, func(c v1.InstanceTypeCapacity) bool { return c.Available > 0 })).To(BeTrue())
+		})
+		It("should set InstanceTypesReady to unknown without blocking the observed generation when instance types can't be listed", func() {
+			awsEnv.InstanceTypesProvider.Reset()
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeInstanceTypesReady).IsUnknown()).To(BeTrue())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeInstanceTypesReady).Reason).To(Equal("InstanceTypesUnresolved"))
+			Expect(nodeClass.Status.ObservedGeneration).To(Equal(nodeClass.Generation))
+		})
+		It("should set InstanceTypesReady to unknown when the subnets haven't been resolved", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeInstanceTypesReady).IsTrue()).To(BeTrue())
+
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{}})
+			awsEnv.SubnetCache.Flush()
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeInstanceTypesReady).IsUnknown()).To(BeTrue())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeInstanceTypesReady).Reason).To(Equal(nodeclass.ConditionReasonDependenciesNotReady))
+		})
+		It("should set InstanceTypesReady to false when the AMIs exclude every instance type", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{ReportInstanceTypes: lo.ToPtr(true)}))
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+				Images: []ec2types.Image{
+					{
+						Name:         aws.String("arm64-standard"),
+						ImageId:      aws.String("ami-arm64-standard"),
+						CreationDate: aws.String("2021-08-31T00:12:42.000Z"),
+						Architecture: "arm64",
+						Tags: []ec2types.Tag{
+							{Key: aws.String("Name"), Value: aws.String("arm64-standard")},
+						},
+						State: ec2types.ImageStateAvailable,
+					},
+				},
+			})
+			nodeClass.Spec.AMIFamily = lo.ToPtr(v1.AMIFamilyCustom)
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Tags: map[string]string{"Name": "arm64-standard"}}}
+			nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{Allow: []string{"m5.*"}}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeInstanceTypesReady).IsFalse()).To(BeTrue())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeInstanceTypesReady).Reason).To(Equal("InstanceTypesNotFound"))
+			Expect(nodeClass.Status.InstanceTypes).To(BeEmpty())
+		})
+	})
+})
@@ -15,11 +15,14 @@ limitations under the License.
 package nodeclass_test
 
 import (
+	"fmt"
+
 	"github.com/awslabs/operatorpkg/status"
 	"github.com/samber/lo"
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -76,4 +79,32 @@ var _ = Describe("NodeClass Status Condition Controller", func() {
 		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
 		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).Message).To(Equal("ValidationSucceeded=False, SecurityGroupsReady=False"))
 	})
+	Context("Observed Generation", func() {
+		It("should set the observed generation once the status is resolved", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.ObservedGeneration).To(Equal(nodeClass.Generation))
+		})
+		It("should not advance the observed generation or revalidate when resolution fails", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			observedGeneration := nodeClass.Status.ObservedGeneration
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsTrue()).To(BeTrue())
+
+			nodeClass.Spec.SubnetSelectorTerms = []v1.SubnetSelectorTerm{{ID: "subnet-test1"}}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			awsEnv.EC2API.DescribeSubnetsBehavior.Error.Set(fmt.Errorf("unable to describe subnets"), fake.MaxCalls(1))
+			_ = ExpectObjectReconcileFailed(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Generation).To(BeNumerically(">", observedGeneration))
+			Expect(nodeClass.Status.ObservedGeneration).To(Equal(observedGeneration))
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsTrue()).To(BeTrue())
+
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.ObservedGeneration).To(Equal(nodeClass.Generation))
+		})
+	})
 })
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -25,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 )
 
@@ -59,6 +61,19 @@ func (sg *SecurityGroup) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeCla
 			Name: *securityGroup.GroupName,
 		}
 	})
+	if options.FromContext(ctx).ValidateSecurityGroups && len(securityGroups) > 0 {
+		clusterSecurityGroups, err := sg.securityGroupProvider.ListClusterSecurityGroups(ctx)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("getting cluster security groups, %w", err)
+		}
+		// Without the security groups of the control plane, there's nothing to validate the rules against
+		if len(clusterSecurityGroups) > 0 {
+			if missing := securitygroup.ValidateClusterConnectivity(securityGroups, clusterSecurityGroups); len(missing) > 0 {
+				nodeClass.StatusConditions().SetFalse(v1.ConditionTypeSecurityGroupsReady, "ClusterConnectivityNotAllowed", fmt.Sprintf("Security group rules don't allow %s; nodes launched with these security groups won't be able to join the cluster", strings.Join(missing, ", ")))
+				return reconcile.Result{RequeueAfter: time.Minute}, nil
+			}
+		}
+	}
 	nodeClass.StatusConditions().SetTrue(v1.ConditionTypeSecurityGroupsReady)
 	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
 }
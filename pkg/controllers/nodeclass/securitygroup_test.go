@@ -15,9 +15,15 @@ limitations under the License.
 package nodeclass_test
 
 import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/samber/lo"
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -228,4 +234,70 @@ var _ = Describe("NodeClass Security Group Status Controller", func() {
 		Expect(nodeClass.Status.SecurityGroups).To(BeNil())
 		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeSecurityGroupsReady).IsFalse()).To(BeTrue())
 	})
+	Context("Cluster Connectivity", func() {
+		var nodeSecurityGroup, clusterSecurityGroup ec2types.SecurityGroup
+
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{ValidateSecurityGroups: lo.ToPtr(true)}))
+			awsEnv.EKSAPI.DescribeClusterBehavior.Output.Set(&eks.DescribeClusterOutput{
+				Cluster: &ekstypes.Cluster{
+					ResourcesVpcConfig: &ekstypes.VpcConfigResponse{ClusterSecurityGroupId: aws.String("sg-cluster")},
+				},
+			})
+			allEgress := []ec2types.IpPermission{{IpProtocol: aws.String("-1"), IpRanges: []ec2types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}}
+			nodeSecurityGroup = ec2types.SecurityGroup{
+				GroupId:   aws.String("sg-node"),
+				GroupName: aws.String("node"),
+				IpPermissions: []ec2types.IpPermission{{
+					IpProtocol:       aws.String("tcp"),
+					FromPort:         aws.Int32(10250),
+					ToPort:           aws.Int32(10250),
+					UserIdGroupPairs: []ec2types.UserIdGroupPair{{GroupId: aws.String("sg-cluster")}},
+				}},
+				IpPermissionsEgress: allEgress,
+			}
+			clusterSecurityGroup = ec2types.SecurityGroup{
+				GroupId:   aws.String("sg-cluster"),
+				GroupName: aws.String("cluster"),
+				IpPermissions: []ec2types.IpPermission{{
+					IpProtocol:       aws.String("tcp"),
+					FromPort:         aws.Int32(443),
+					ToPort:           aws.Int32(443),
+					UserIdGroupPairs: []ec2types.UserIdGroupPair{{GroupId: aws.String("sg-node")}},
+				}},
+				IpPermissionsEgress: allEgress,
+			}
+		})
+		It("should set SecurityGroupsReady when the rules allow the control plane and nodes to connect", func() {
+			awsEnv.EC2API.DescribeSecurityGroupsBehavior.Output.Set(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []ec2types.SecurityGroup{nodeSecurityGroup, clusterSecurityGroup}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeSecurityGroupsReady).IsTrue()).To(BeTrue())
+			Expect(awsEnv.EKSAPI.DescribeClusterBehavior.Calls()).To(Equal(1))
+		})
+		It("should not set SecurityGroupsReady when the control plane can't reach the kubelet", func() {
+			nodeSecurityGroup.IpPermissions = nil
+			awsEnv.EC2API.DescribeSecurityGroupsBehavior.Output.Set(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []ec2types.SecurityGroup{nodeSecurityGroup, clusterSecurityGroup}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			condition := nodeClass.StatusConditions().Get(v1.ConditionTypeSecurityGroupsReady)
+			Expect(condition.IsFalse()).To(BeTrue())
+			Expect(condition.Reason).To(Equal("ClusterConnectivityNotAllowed"))
+			Expect(condition.Message).To(ContainSubstring("ingress to nodes from the control plane on tcp/10250"))
+			// The security groups are still resolved so that they can be inspected
+			Expect(nodeClass.Status.SecurityGroups).To(HaveLen(2))
+		})
+		It("should not validate the rules when validation is disabled", func() {
+			ctx = options.ToContext(ctx, test.Options())
+			nodeSecurityGroup.IpPermissions = nil
+			awsEnv.EC2API.DescribeSecurityGroupsBehavior.Output.Set(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []ec2types.SecurityGroup{nodeSecurityGroup, clusterSecurityGroup}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeSecurityGroupsReady).IsTrue()).To(BeTrue())
+			Expect(awsEnv.EKSAPI.DescribeClusterBehavior.Calls()).To(BeZero())
+		})
+	})
 })
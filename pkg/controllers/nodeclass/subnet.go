@@ -23,18 +23,23 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/samber/lo"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/karpenter/pkg/events"
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
+	"github.com/aws/karpenter-provider-aws/pkg/utils"
 )
 
 type Subnet struct {
 	subnetProvider subnet.Provider
+	recorder       events.Recorder
 }
 
-func NewSubnetReconciler(subnetProvider subnet.Provider) *Subnet {
+func NewSubnetReconciler(subnetProvider subnet.Provider, recorder events.Recorder) *Subnet {
 	return &Subnet{
 		subnetProvider: subnetProvider,
+		recorder:       recorder,
 	}
 }
 
@@ -58,11 +63,34 @@ func (s *Subnet) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass) (rec
 	})
 	nodeClass.Status.Subnets = lo.Map(subnets, func(ec2subnet ec2types.Subnet, _ int) v1.Subnet {
 		return v1.Subnet{
-			ID:     *ec2subnet.SubnetId,
-			Zone:   *ec2subnet.AvailabilityZone,
-			ZoneID: *ec2subnet.AvailabilityZoneId,
+			ID:                      *ec2subnet.SubnetId,
+			Zone:                    *ec2subnet.AvailabilityZone,
+			ZoneID:                  *ec2subnet.AvailabilityZoneId,
+			AvailableIPAddressCount: ec2subnet.AvailableIpAddressCount,
 		}
 	})
 	nodeClass.StatusConditions().SetTrue(v1.ConditionTypeSubnetsReady)
+	s.reconcileCapacity(ctx, nodeClass)
 	return reconcile.Result{RequeueAfter: time.Minute}, nil
 }
+
+// reconcileCapacity warns when the subnet with the most available IPs in a zone falls below the threshold, since
+// launches in that zone will start failing once it runs out
+func (s *Subnet) reconcileCapacity(ctx context.Context, nodeClass *v1.EC2NodeClass) {
+	threshold := options.FromContext(ctx).SubnetIPThreshold
+	if threshold == 0 {
+		_ = nodeClass.StatusConditions().Clear(v1.ConditionTypeSubnetsHaveCapacity)
+		return
+	}
+	// Subnets are sorted by available IPs, so the first subnet in each zone is the one that's launched into
+	zonalSubnets := lo.UniqBy(nodeClass.Status.Subnets, func(s v1.Subnet) string { return s.Zone })
+	exhausted := lo.FilterMap(zonalSubnets, func(s v1.Subnet, _ int) (string, bool) {
+		return fmt.Sprintf("%s (%s, %d available IPs)", s.ID, s.Zone, lo.FromPtr(s.AvailableIPAddressCount)), int(lo.FromPtr(s.AvailableIPAddressCount)) < threshold
+	})
+	if len(exhausted) == 0 {
+		nodeClass.StatusConditions().SetTrue(v1.ConditionTypeSubnetsHaveCapacity)
+		return
+	}
+	nodeClass.StatusConditions().SetFalse(v1.ConditionTypeSubnetsHaveCapacity, "SubnetsLowOnIPs", fmt.Sprintf("Subnets are below %d available IPs, %s", threshold, utils.PrettySlice(exhausted, 5)))
+	s.recorder.Publish(SubnetsLowOnIPsEvent(nodeClass, threshold, exhausted))
+}
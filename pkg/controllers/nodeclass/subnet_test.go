@@ -21,6 +21,7 @@ import (
 	"github.com/samber/lo"
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -57,24 +58,28 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(Equal([]v1.Subnet{
 			{
-				ID:     "subnet-test1",
-				Zone:   "test-zone-1a",
-				ZoneID: "tstz1-1a",
+				ID:                      "subnet-test1",
+				Zone:                    "test-zone-1a",
+				ZoneID:                  "tstz1-1a",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test2",
-				Zone:   "test-zone-1b",
-				ZoneID: "tstz1-1b",
+				ID:                      "subnet-test2",
+				Zone:                    "test-zone-1b",
+				ZoneID:                  "tstz1-1b",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test3",
-				Zone:   "test-zone-1c",
-				ZoneID: "tstz1-1c",
+				ID:                      "subnet-test3",
+				Zone:                    "test-zone-1c",
+				ZoneID:                  "tstz1-1c",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test4",
-				Zone:   "test-zone-1a-local",
-				ZoneID: "tstz1-1alocal",
+				ID:                      "subnet-test4",
+				Zone:                    "test-zone-1a-local",
+				ZoneID:                  "tstz1-1alocal",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 		}))
 		Expect(nodeClass.StatusConditions().IsTrue(v1.ConditionTypeSubnetsReady)).To(BeTrue())
@@ -90,19 +95,22 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(Equal([]v1.Subnet{
 			{
-				ID:     "subnet-test2",
-				Zone:   "test-zone-1b",
-				ZoneID: "tstz1-1b",
+				ID:                      "subnet-test2",
+				Zone:                    "test-zone-1b",
+				ZoneID:                  "tstz1-1b",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test3",
-				Zone:   "test-zone-1c",
-				ZoneID: "tstz1-1c",
+				ID:                      "subnet-test3",
+				Zone:                    "test-zone-1c",
+				ZoneID:                  "tstz1-1c",
+				AvailableIPAddressCount: lo.ToPtr[int32](50),
 			},
 			{
-				ID:     "subnet-test1",
-				Zone:   "test-zone-1a",
-				ZoneID: "tstz1-1a",
+				ID:                      "subnet-test1",
+				Zone:                    "test-zone-1a",
+				ZoneID:                  "tstz1-1a",
+				AvailableIPAddressCount: lo.ToPtr[int32](20),
 			},
 		}))
 		Expect(nodeClass.StatusConditions().IsTrue(v1.ConditionTypeSubnetsReady)).To(BeTrue())
@@ -121,14 +129,16 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(Equal([]v1.Subnet{
 			{
-				ID:     "subnet-test1",
-				Zone:   "test-zone-1a",
-				ZoneID: "tstz1-1a",
+				ID:                      "subnet-test1",
+				Zone:                    "test-zone-1a",
+				ZoneID:                  "tstz1-1a",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test2",
-				Zone:   "test-zone-1b",
-				ZoneID: "tstz1-1b",
+				ID:                      "subnet-test2",
+				Zone:                    "test-zone-1b",
+				ZoneID:                  "tstz1-1b",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 		}))
 		Expect(nodeClass.StatusConditions().IsTrue(v1.ConditionTypeSubnetsReady)).To(BeTrue())
@@ -144,9 +154,10 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(Equal([]v1.Subnet{
 			{
-				ID:     "subnet-test1",
-				Zone:   "test-zone-1a",
-				ZoneID: "tstz1-1a",
+				ID:                      "subnet-test1",
+				Zone:                    "test-zone-1a",
+				ZoneID:                  "tstz1-1a",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 		}))
 		Expect(nodeClass.StatusConditions().IsTrue(v1.ConditionTypeSubnetsReady)).To(BeTrue())
@@ -157,24 +168,28 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(Equal([]v1.Subnet{
 			{
-				ID:     "subnet-test1",
-				Zone:   "test-zone-1a",
-				ZoneID: "tstz1-1a",
+				ID:                      "subnet-test1",
+				Zone:                    "test-zone-1a",
+				ZoneID:                  "tstz1-1a",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test2",
-				Zone:   "test-zone-1b",
-				ZoneID: "tstz1-1b",
+				ID:                      "subnet-test2",
+				Zone:                    "test-zone-1b",
+				ZoneID:                  "tstz1-1b",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test3",
-				Zone:   "test-zone-1c",
-				ZoneID: "tstz1-1c",
+				ID:                      "subnet-test3",
+				Zone:                    "test-zone-1c",
+				ZoneID:                  "tstz1-1c",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test4",
-				Zone:   "test-zone-1a-local",
-				ZoneID: "tstz1-1alocal",
+				ID:                      "subnet-test4",
+				Zone:                    "test-zone-1a-local",
+				ZoneID:                  "tstz1-1alocal",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 		}))
 
@@ -195,14 +210,16 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(Equal([]v1.Subnet{
 			{
-				ID:     "subnet-test1",
-				Zone:   "test-zone-1a",
-				ZoneID: "tstz1-1a",
+				ID:                      "subnet-test1",
+				Zone:                    "test-zone-1a",
+				ZoneID:                  "tstz1-1a",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test2",
-				Zone:   "test-zone-1b",
-				ZoneID: "tstz1-1b",
+				ID:                      "subnet-test2",
+				Zone:                    "test-zone-1b",
+				ZoneID:                  "tstz1-1b",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 		}))
 		Expect(nodeClass.StatusConditions().IsTrue(v1.ConditionTypeSubnetsReady)).To(BeTrue())
@@ -213,24 +230,28 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(Equal([]v1.Subnet{
 			{
-				ID:     "subnet-test1",
-				Zone:   "test-zone-1a",
-				ZoneID: "tstz1-1a",
+				ID:                      "subnet-test1",
+				Zone:                    "test-zone-1a",
+				ZoneID:                  "tstz1-1a",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test2",
-				Zone:   "test-zone-1b",
-				ZoneID: "tstz1-1b",
+				ID:                      "subnet-test2",
+				Zone:                    "test-zone-1b",
+				ZoneID:                  "tstz1-1b",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test3",
-				Zone:   "test-zone-1c",
-				ZoneID: "tstz1-1c",
+				ID:                      "subnet-test3",
+				Zone:                    "test-zone-1c",
+				ZoneID:                  "tstz1-1c",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test4",
-				Zone:   "test-zone-1a-local",
-				ZoneID: "tstz1-1alocal",
+				ID:                      "subnet-test4",
+				Zone:                    "test-zone-1a-local",
+				ZoneID:                  "tstz1-1alocal",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 		}))
 
@@ -244,9 +265,10 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(Equal([]v1.Subnet{
 			{
-				ID:     "subnet-test1",
-				Zone:   "test-zone-1a",
-				ZoneID: "tstz1-1a",
+				ID:                      "subnet-test1",
+				Zone:                    "test-zone-1a",
+				ZoneID:                  "tstz1-1a",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 		}))
 		Expect(nodeClass.StatusConditions().IsTrue(v1.ConditionTypeSubnetsReady)).To(BeTrue())
@@ -269,24 +291,28 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(Equal([]v1.Subnet{
 			{
-				ID:     "subnet-test1",
-				Zone:   "test-zone-1a",
-				ZoneID: "tstz1-1a",
+				ID:                      "subnet-test1",
+				Zone:                    "test-zone-1a",
+				ZoneID:                  "tstz1-1a",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test2",
-				Zone:   "test-zone-1b",
-				ZoneID: "tstz1-1b",
+				ID:                      "subnet-test2",
+				Zone:                    "test-zone-1b",
+				ZoneID:                  "tstz1-1b",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test3",
-				Zone:   "test-zone-1c",
-				ZoneID: "tstz1-1c",
+				ID:                      "subnet-test3",
+				Zone:                    "test-zone-1c",
+				ZoneID:                  "tstz1-1c",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 			{
-				ID:     "subnet-test4",
-				Zone:   "test-zone-1a-local",
-				ZoneID: "tstz1-1alocal",
+				ID:                      "subnet-test4",
+				Zone:                    "test-zone-1a-local",
+				ZoneID:                  "tstz1-1alocal",
+				AvailableIPAddressCount: lo.ToPtr[int32](100),
 			},
 		}))
 
@@ -301,4 +327,64 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		Expect(nodeClass.Status.Subnets).To(BeNil())
 		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeSubnetsReady).IsFalse()).To(BeTrue())
 	})
+	Context("Subnet Capacity", func() {
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{SubnetIPThreshold: lo.ToPtr(100)}))
+		})
+		It("should set SubnetsHaveCapacity when every zone has a subnet above the threshold", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeSubnetsHaveCapacity).IsTrue()).To(BeTrue())
+			Expect(recorder.Calls("SubnetsLowOnIPs")).To(BeZero())
+		})
+		It("should only consider the subnet with the most available IPs in each zone", func() {
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+				{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailabilityZoneId: aws.String("tstz1-1a"), AvailableIpAddressCount: aws.Int32(10)},
+				{SubnetId: aws.String("subnet-test2"), AvailabilityZone: aws.String("test-zone-1a"), AvailabilityZoneId: aws.String("tstz1-1a"), AvailableIpAddressCount: aws.Int32(200)},
+			}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeSubnetsHaveCapacity).IsTrue()).To(BeTrue())
+		})
+		It("should warn when the best subnet in a zone falls below the threshold", func() {
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+				{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailabilityZoneId: aws.String("tstz1-1a"), AvailableIpAddressCount: aws.Int32(20)},
+				{SubnetId: aws.String("subnet-test2"), AvailabilityZone: aws.String("test-zone-1b"), AvailabilityZoneId: aws.String("tstz1-1b"), AvailableIpAddressCount: aws.Int32(200)},
+			}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			condition := nodeClass.StatusConditions().Get(v1.ConditionTypeSubnetsHaveCapacity)
+			Expect(condition.IsFalse()).To(BeTrue())
+			Expect(condition.Reason).To(Equal("SubnetsLowOnIPs"))
+			Expect(condition.Message).To(ContainSubstring("subnet-test1 (test-zone-1a, 20 available IPs)"))
+			Expect(condition.Message).ToNot(ContainSubstring("subnet-test2"))
+			Expect(recorder.Calls("SubnetsLowOnIPs")).To(Equal(1))
+			// Low capacity in a zone shouldn't block launches in the other zones
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeSubnetsReady).IsTrue()).To(BeTrue())
+		})
+		It("should use the configured threshold", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{SubnetIPThreshold: lo.ToPtr(10)}))
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+				{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailabilityZoneId: aws.String("tstz1-1a"), AvailableIpAddressCount: aws.Int32(20)},
+			}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeSubnetsHaveCapacity).IsTrue()).To(BeTrue())
+		})
+		It("should not set SubnetsHaveCapacity by default", func() {
+			ctx = options.ToContext(ctx, test.Options())
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+				{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailabilityZoneId: aws.String("tstz1-1a"), AvailableIpAddressCount: aws.Int32(0)},
+			}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeSubnetsHaveCapacity)).To(BeNil())
+			Expect(recorder.Calls("SubnetsLowOnIPs")).To(BeZero())
+		})
+	})
 })
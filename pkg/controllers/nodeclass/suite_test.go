@@ -55,6 +55,7 @@ var awsEnv *test.Environment
 var nodeClass *v1.EC2NodeClass
 var controller *nodeclass.Controller
 var cloudProvider *cloudprovider.CloudProvider
+var recorder *coretest.EventRecorder
 
 func TestAPIs(t *testing.T) {
 	ctx = TestContextWithLogger(t)
@@ -75,11 +76,12 @@ var _ = BeforeSuite(func() {
 	cloudProvider = cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, events.NewRecorder(&record.FakeRecorder{}),
 		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.CapacityReservationProvider)
 
+	recorder = coretest.NewEventRecorder()
 	controller = nodeclass.NewController(
 		awsEnv.Clock,
 		env.Client,
 		cloudProvider,
-		events.NewRecorder(&record.FakeRecorder{}),
+		recorder,
 		fake.DefaultRegion,
 		awsEnv.SubnetProvider,
 		awsEnv.SecurityGroupProvider,
@@ -88,7 +90,9 @@ var _ = BeforeSuite(func() {
 		awsEnv.InstanceTypesProvider,
 		awsEnv.LaunchTemplateProvider,
 		awsEnv.CapacityReservationProvider,
+		awsEnv.PermissionProvider,
 		awsEnv.EC2API,
+		awsEnv.KMSAPI,
 		awsEnv.ValidationCache,
 		awsEnv.AMIResolver,
 	)
@@ -100,8 +104,10 @@ var _ = AfterSuite(func() {
 
 var _ = BeforeEach(func() {
 	ctx = coreoptions.ToContext(ctx, coretest.Options(coretest.OptionsFields{FeatureGates: coretest.FeatureGates{ReservedCapacity: lo.ToPtr(true)}}))
+	ctx = options.ToContext(ctx, test.Options())
 	nodeClass = test.EC2NodeClass()
 	awsEnv.Reset()
+	recorder.Reset()
 	Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypes(ctx)).To(Succeed())
 	Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypeOfferings(ctx)).To(Succeed())
 })
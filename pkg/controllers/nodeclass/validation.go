@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/mitchellh/hashstructure/v2"
@@ -26,12 +27,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/events"
+	karpoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
 	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -41,9 +46,13 @@ import (
 	awserrors "github.com/aws/karpenter-provider-aws/pkg/errors"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily/bootstrap"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/permission"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 	"github.com/aws/karpenter-provider-aws/pkg/utils"
 )
 
@@ -53,46 +62,95 @@ const (
 	ConditionReasonRunInstancesAuthFailed         = "RunInstancesAuthCheckFailed"
 	ConditionReasonDependenciesNotReady           = "DependenciesNotReady"
 	ConditionReasonTagValidationFailed            = "TagValidationFailed"
+	ConditionReasonUserDataTemplateInvalid        = "UserDataTemplateInvalid"
+	ConditionReasonUserDataTooLarge               = "UserDataTooLarge"
+	ConditionReasonSubnetsInMultipleVPCs          = "SubnetsInMultipleVPCs"
+	ConditionReasonSecurityGroupsNotInSubnetVPC   = "SecurityGroupsNotInSubnetVPC"
+	ConditionReasonPermissionsMissing             = "PermissionsMissing"
 )
 
 var ValidationConditionMessages = map[string]string{
 	ConditionReasonCreateFleetAuthFailed:          "Controller isn't authorized to call ec2:CreateFleet",
 	ConditionReasonCreateLaunchTemplateAuthFailed: "Controller isn't authorized to call ec2:CreateLaunchTemplate",
 	ConditionReasonRunInstancesAuthFailed:         "Controller isn't authorized to call ec2:RunInstances",
+	ConditionReasonUserDataTooLarge:               fmt.Sprintf("Generated userData exceeds the EC2 limit of %d bytes, even after compression", bootstrap.MaxUserDataSize),
 }
 
 type Validation struct {
 	kubeClient             client.Client
 	cloudProvider          cloudprovider.CloudProvider
+	recorder               events.Recorder
 	ec2api                 sdk.EC2API
+	subnetProvider         subnet.Provider
+	securityGroupProvider  securitygroup.Provider
 	amiResolver            amifamily.Resolver
 	instanceTypeProvider   instancetype.Provider
 	launchTemplateProvider launchtemplate.Provider
+	permissionProvider     permission.Provider
+	kmsapi                 sdk.KMSAPI
 	cache                  *cache.Cache
 }
 
 func NewValidationReconciler(
 	kubeClient client.Client,
 	cloudProvider cloudprovider.CloudProvider,
+	recorder events.Recorder,
 	ec2api sdk.EC2API,
+	subnetProvider subnet.Provider,
+	securityGroupProvider securitygroup.Provider,
 	amiResolver amifamily.Resolver,
 	instanceTypeProvider instancetype.Provider,
 	launchTemplateProvider launchtemplate.Provider,
+	permissionProvider permission.Provider,
+	kmsapi sdk.KMSAPI,
 	cache *cache.Cache,
 ) *Validation {
 	return &Validation{
 		kubeClient:             kubeClient,
 		cloudProvider:          cloudProvider,
+		recorder:               recorder,
 		ec2api:                 ec2api,
+		subnetProvider:         subnetProvider,
+		securityGroupProvider:  securityGroupProvider,
 		amiResolver:            amiResolver,
 		instanceTypeProvider:   instanceTypeProvider,
 		launchTemplateProvider: launchTemplateProvider,
+		permissionProvider:     permissionProvider,
+		kmsapi:                 kmsapi,
 		cache:                  cache,
 	}
 }
 
 // nolint:gocyclo
 func (v *Validation) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass) (reconcile.Result, error) {
+	// Permissions only depend on the spec, so they're checked ahead of the resolved status. Missing permissions for AMI
+	// or capacity reservation resolution would otherwise keep the status from ever catching up.
+	missing, err := v.validatePermissions(ctx, nodeClass)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(missing) > 0 {
+		v.recorder.Publish(PermissionsMissingEvent(nodeClass, missing))
+		nodeClass.StatusConditions().SetFalse(
+			v1.ConditionTypeValidationSucceeded,
+			ConditionReasonPermissionsMissing,
+			fmt.Sprintf("Controller isn't authorized to call %s", strings.Join(missing, "; ")),
+		)
+		return reconcile.Result{}, nil
+	}
+	// Validating against a status that hasn't been resolved for the current spec would report the result for a stale
+	// generation. We retain the previous result until the status catches up, unless it's for permissions which have
+	// since been granted.
+	if nodeClass.Status.ObservedGeneration != nodeClass.Generation {
+		if lo.FromPtr(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded)).Reason == ConditionReasonPermissionsMissing {
+			nodeClass.StatusConditions().SetUnknownWithReason(
+				v1.ConditionTypeValidationSucceeded,
+				ConditionReasonDependenciesNotReady,
+				"Awaiting AMI, Instance Profile, Security Group, and Subnet resolution",
+			)
+		}
+		return reconcile.Result{}, nil
+	}
 	if _, ok := lo.Find(v.requiredConditions(), func(cond string) bool {
 		return nodeClass.StatusConditions().Get(cond).IsFalse()
 	}); ok {
@@ -117,18 +175,22 @@ func (v *Validation) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass)
 		return reconcile.Result{}, nil
 	}
 
-	nodeClaim := &karpv1.NodeClaim{
-		Spec: karpv1.NodeClaimSpec{
-			NodeClassRef: &karpv1.NodeClassReference{
-				Name: nodeClass.ObjectMeta.Name,
-			},
-		},
-	}
+	nodeClaim := mockNodeClaim(nodeClass)
 	tags, err := utils.GetTags(nodeClass, nodeClaim, options.FromContext(ctx).ClusterName)
 	if err != nil {
 		nodeClass.StatusConditions().SetFalse(v1.ConditionTypeValidationSucceeded, ConditionReasonTagValidationFailed, err.Error())
 		return reconcile.Result{}, reconcile.TerminalError(fmt.Errorf("validating tags, %w", err))
 	}
+	if err := validateUserDataTemplate(ctx, nodeClass); err != nil {
+		nodeClass.StatusConditions().SetFalse(v1.ConditionTypeValidationSucceeded, ConditionReasonUserDataTemplateInvalid, err.Error())
+		return reconcile.Result{}, nil
+	}
+	if reason, message, err := v.validateNetwork(ctx, nodeClass); err != nil {
+		return reconcile.Result{}, err
+	} else if reason != "" {
+		nodeClass.StatusConditions().SetFalse(v1.ConditionTypeValidationSucceeded, reason, message)
+		return reconcile.Result{}, nil
+	}
 
 	if val, ok := v.cache.Get(v.cacheKey(nodeClass, tags)); ok {
 		// We still update the status condition even if it's cached since we may have had a conflict error previously
@@ -144,6 +206,7 @@ func (v *Validation) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass)
 		return reconcile.Result{}, nil
 	}
 	for _, isValid := range []validatorFunc{
+		v.validateUserDataSize,
 		v.validateCreateFleetAuthorization,
 		v.validateCreateLaunchTemplateAuthorization,
 		v.validateRunInstancesAuthorization,
@@ -168,8 +231,103 @@ func (v *Validation) Reconcile(ctx context.Context, nodeClass *v1.EC2NodeClass)
 	return reconcile.Result{}, nil
 }
 
+// validateUserDataTemplate renders the EC2NodeClass's userData template against sample data, so that invalid
+// templates are surfaced before they fail a launch
+func validateUserDataTemplate(ctx context.Context, nodeClass *v1.EC2NodeClass) error {
+	if nodeClass.Spec.UserData == nil || !lo.FromPtr(nodeClass.Spec.TemplateUserData) {
+		return nil
+	}
+	_, err := amifamily.RenderUserData(*nodeClass.Spec.UserData, amifamily.UserDataTemplateData{
+		ClusterName:   options.FromContext(ctx).ClusterName,
+		NodeClassName: nodeClass.Name,
+		NodePoolName:  "default",
+		CapacityType:  karpv1.CapacityTypeOnDemand,
+		Zone:          "test-zone-1a",
+		InstanceTypes: []string{string(ec2types.InstanceTypeM5Large)},
+		AMIID:         "ami-1234567890abcdef0",
+		Labels:        map[string]string{karpv1.CapacityTypeLabelKey: karpv1.CapacityTypeOnDemand},
+	})
+	return err
+}
+
+// validateNetwork checks that the resolved subnets and security groups belong to a single VPC, since EC2 rejects
+// launches that mix VPCs with errors that don't identify the offending resources. It also warns when the subnets'
+// public IP settings contradict associatePublicIPAddress.
+func (v *Validation) validateNetwork(ctx context.Context, nodeClass *v1.EC2NodeClass) (reason string, message string, err error) {
+	subnetIDs := sets.New(lo.Map(nodeClass.Status.Subnets, func(s v1.Subnet, _ int) string { return s.ID })...)
+	securityGroupIDs := sets.New(lo.Map(nodeClass.Status.SecurityGroups, func(sg v1.SecurityGroup, _ int) string { return sg.ID })...)
+	subnets, err := v.subnetProvider.List(ctx, nodeClass)
+	if err != nil {
+		return "", "", fmt.Errorf("getting subnets, %w", err)
+	}
+	subnets = lo.Filter(subnets, func(s ec2types.Subnet, _ int) bool { return subnetIDs.Has(lo.FromPtr(s.SubnetId)) })
+	securityGroups, err := v.securityGroupProvider.List(ctx, nodeClass)
+	if err != nil {
+		return "", "", fmt.Errorf("getting security groups, %w", err)
+	}
+	securityGroups = lo.Filter(securityGroups, func(sg ec2types.SecurityGroup, _ int) bool {
+		return securityGroupIDs.Has(lo.FromPtr(sg.GroupId))
+	})
+
+	subnetsByVPC := lo.GroupBy(lo.Filter(subnets, func(s ec2types.Subnet, _ int) bool { return s.VpcId != nil }), func(s ec2types.Subnet) string {
+		return lo.FromPtr(s.VpcId)
+	})
+	if len(subnetsByVPC) > 1 {
+		vpcs := lo.Keys(subnetsByVPC)
+		sort.Strings(vpcs)
+		return ConditionReasonSubnetsInMultipleVPCs, fmt.Sprintf("Subnets must belong to a single VPC, found %s; update subnetSelectorTerms to select subnets from one VPC",
+			strings.Join(lo.Map(vpcs, func(vpc string, _ int) string {
+				return fmt.Sprintf("%s (%s)", vpc, utils.PrettySlice(lo.Map(subnetsByVPC[vpc], func(s ec2types.Subnet, _ int) string { return lo.FromPtr(s.SubnetId) }), 5))
+			}), ", ")), nil
+	}
+	for vpc := range subnetsByVPC {
+		// Security groups without a VPC ID can't be checked, so we only report the ones known to be in a different VPC
+		mismatched := lo.FilterMap(securityGroups, func(sg ec2types.SecurityGroup, _ int) (string, bool) {
+			return fmt.Sprintf("%s (%s)", lo.FromPtr(sg.GroupId), lo.FromPtr(sg.VpcId)), sg.VpcId != nil && lo.FromPtr(sg.VpcId) != vpc
+		})
+		if len(mismatched) > 0 {
+			sort.Strings(mismatched)
+			return ConditionReasonSecurityGroupsNotInSubnetVPC, fmt.Sprintf("Security groups %s don't belong to %s, the VPC of the selected subnets; update securityGroupSelectorTerms to select security groups from %s",
+				utils.PrettySlice(mismatched, 5), vpc, vpc), nil
+		}
+	}
+
+	if associate := nodeClass.Spec.AssociatePublicIPAddress; associate != nil {
+		mismatched := lo.FilterMap(subnets, func(s ec2types.Subnet, _ int) (string, bool) {
+			return lo.FromPtr(s.SubnetId), s.MapPublicIpOnLaunch != nil && *s.MapPublicIpOnLaunch != *associate
+		})
+		if len(mismatched) > 0 {
+			sort.Strings(mismatched)
+			v.recorder.Publish(SubnetPublicIPMismatchEvent(nodeClass, *associate, mismatched))
+		}
+	}
+	return "", "", nil
+}
+
 type validatorFunc func(context.Context, *v1.EC2NodeClass, *karpv1.NodeClaim, map[string]string) (string, bool, error)
 
+// validateUserDataSize checks that the userData generated for the NodeClass fits within the EC2 limit. Labels and
+// taints from NodePools add to the final size, so this catches the common case rather than guaranteeing a fit.
+func (v *Validation) validateUserDataSize(
+	ctx context.Context,
+	nodeClass *v1.EC2NodeClass,
+	nodeClaim *karpv1.NodeClaim,
+	tags map[string]string,
+) (reason string, requeue bool, err error) {
+	opts, err := v.mockLaunchTemplateOptions(ctx, nodeClaim, nodeClass, tags)
+	if err != nil {
+		return "", false, fmt.Errorf("generating options, %w", err)
+	}
+	userData, err := opts.UserData.Script()
+	if err != nil {
+		return "", false, fmt.Errorf("generating userData, %w", err)
+	}
+	if err = bootstrap.ValidateUserDataSize(userData); err != nil {
+		return ConditionReasonUserDataTooLarge, false, nil
+	}
+	return "", false, nil
+}
+
 func (v *Validation) validateCreateFleetAuthorization(
 	ctx context.Context,
 	nodeClass *v1.EC2NodeClass,
@@ -280,6 +438,99 @@ func (v *Validation) validateRunInstancesAuthorization(
 	return "", false, nil
 }
 
+// validatePermissions returns the permissions that launches for the EC2NodeClass depend on which the controller is
+// missing. EC2 doesn't validate most of these in the dry runs of the launch requests, so they're simulated against the
+// controller's IAM policies where they would otherwise only surface as launch failures.
+func (v *Validation) validatePermissions(ctx context.Context, nodeClass *v1.EC2NodeClass) ([]string, error) {
+	tags, err := utils.GetTags(nodeClass, mockNodeClaim(nodeClass), options.FromContext(ctx).ClusterName)
+	if err != nil {
+		// Invalid tags are reported by tag validation
+		return nil, nil
+	}
+	key := v.permissionsCacheKey(ctx, nodeClass, tags)
+	if val, ok := v.cache.Get(key); ok {
+		return val.([]string), nil
+	}
+	checks, err := v.permissionChecks(ctx, nodeClass, tags)
+	if err != nil {
+		return nil, err
+	}
+	missingChecks, err := v.permissionProvider.Missing(ctx, checks...)
+	if err != nil {
+		return nil, fmt.Errorf("checking permissions, %w", err)
+	}
+	missing := lo.Map(missingChecks, func(c permission.Check, _ int) string { return c.String() })
+	v.cache.SetDefault(key, missing)
+	return missing, nil
+}
+
+// permissionChecks returns the checks for the actions that are made on behalf of the EC2NodeClass's configuration
+func (v *Validation) permissionChecks(ctx context.Context, nodeClass *v1.EC2NodeClass, tags map[string]string) ([]permission.Check, error) {
+	var checks []permission.Check
+	if nodeClass.Spec.Role != "" {
+		checks = append(checks, permission.Check{
+			Action:   "iam:PassRole",
+			Resource: fmt.Sprintf("role/%s", nodeClass.Spec.Role),
+			Context:  map[string][]string{"iam:PassedToService": {"ec2.amazonaws.com"}},
+		})
+	}
+	tagContext := lo.Assign(permission.RequestTagContext(tags), map[string][]string{"ec2:CreateAction": {"CreateFleet"}})
+	for _, resource := range []string{"volume/*", "network-interface/*"} {
+		checks = append(checks, permission.Check{Action: "ec2:CreateTags", Resource: resource, Context: tagContext})
+	}
+	for _, keyID := range lo.Uniq(lo.FilterMap(nodeClass.Spec.BlockDeviceMappings, func(bdm *v1.BlockDeviceMapping, _ int) (string, bool) {
+		return lo.FromPtr(lo.FromPtr(bdm.EBS).KMSKeyID), bdm.EBS != nil && lo.FromPtr(bdm.EBS.KMSKeyID) != ""
+	})) {
+		keyARN, err := v.kmsKeyARN(ctx, keyID)
+		if err != nil {
+			return nil, err
+		}
+		// Keys which can't be described are reported by the block device mapping reconciler
+		if keyARN.Resource == "" {
+			continue
+		}
+		checks = append(checks, permission.Check{
+			Action:   "kms:CreateGrant",
+			Resource: keyARN.String(),
+			Context: map[string][]string{
+				"kms:GrantIsForAWSResource": {"true"},
+				"kms:ViaService":            {fmt.Sprintf("ec2.%s.amazonaws.com", keyARN.Region)},
+			},
+			ResourceBasedPolicy: true,
+		})
+	}
+	for _, term := range nodeClass.Spec.AMISelectorTerms {
+		if term.SSMParameter == "" {
+			continue
+		}
+		checks = append(checks, permission.Check{
+			Action:   "ssm:GetParameter",
+			Resource: lo.Ternary(arn.IsARN(term.SSMParameter), term.SSMParameter, fmt.Sprintf("parameter/%s", strings.TrimPrefix(term.SSMParameter, "/"))),
+		})
+	}
+	if len(nodeClass.Spec.CapacityReservationSelectorTerms) != 0 && karpoptions.FromContext(ctx).FeatureGates.ReservedCapacity {
+		checks = append(checks, permission.Check{Action: "ec2:DescribeCapacityReservations", Resource: "*"})
+	}
+	return checks, nil
+}
+
+// kmsKeyARN resolves a key ID, alias, or ARN to the ARN of the key, since grants are authorized against the key
+// rather than its alias. An empty ARN is returned if the key can't be described.
+func (v *Validation) kmsKeyARN(ctx context.Context, keyID string) (arn.ARN, error) {
+	if keyARN, err := arn.Parse(keyID); err == nil && strings.HasPrefix(keyARN.Resource, "key/") {
+		return keyARN, nil
+	}
+	out, err := v.kmsapi.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: lo.ToPtr(keyID)})
+	if err != nil {
+		return arn.ARN{}, nil
+	}
+	keyARN, err := arn.Parse(lo.FromPtr(out.KeyMetadata.Arn))
+	if err != nil {
+		return arn.ARN{}, fmt.Errorf("parsing kms key arn, %w", err)
+	}
+	return keyARN, nil
+}
+
 func (*Validation) requiredConditions() []string {
 	return []string{
 		v1.ConditionTypeAMIsReady,
@@ -291,17 +542,38 @@ func (*Validation) requiredConditions() []string {
 
 func (*Validation) cacheKey(nodeClass *v1.EC2NodeClass, tags map[string]string) string {
 	hash := lo.Must(hashstructure.Hash([]interface{}{
-		nodeClass.Status.Subnets,
+		// Available IP counts change frequently and don't affect validation, so they're excluded from the hash
+		lo.Map(nodeClass.Status.Subnets, func(s v1.Subnet, _ int) v1.Subnet {
+			return v1.Subnet{ID: s.ID, Zone: s.Zone, ZoneID: s.ZoneID}
+		}),
 		nodeClass.Status.SecurityGroups,
 		nodeClass.Status.AMIs,
 		nodeClass.Status.InstanceProfile,
 		nodeClass.Spec.MetadataOptions,
 		nodeClass.Spec.BlockDeviceMappings,
+		// userData size depends on these fields as well as on the resolved AMIs
+		nodeClass.Spec.UserData,
+		nodeClass.Spec.Kubelet,
+		nodeClass.Spec.Bottlerocket,
+		nodeClass.Spec.Registries,
 		tags,
 	}, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true}))
 	return fmt.Sprintf("%s:%016x", nodeClass.Name, hash)
 }
 
+func (*Validation) permissionsCacheKey(ctx context.Context, nodeClass *v1.EC2NodeClass, tags map[string]string) string {
+	hash := lo.Must(hashstructure.Hash([]interface{}{
+		nodeClass.Spec.AssumeRole,
+		nodeClass.Spec.Role,
+		nodeClass.Spec.BlockDeviceMappings,
+		nodeClass.Spec.AMISelectorTerms,
+		nodeClass.Spec.CapacityReservationSelectorTerms,
+		karpoptions.FromContext(ctx).FeatureGates.ReservedCapacity,
+		tags,
+	}, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true}))
+	return fmt.Sprintf("%s:permissions-%016x", nodeClass.Name, hash)
+}
+
 // clearCacheEntries removes all cache entries associated with the given nodeclass from the validation cache
 func (v *Validation) clearCacheEntries(nodeClass *v1.EC2NodeClass) {
 	var toDelete []string
@@ -320,6 +592,17 @@ func (v *Validation) clearCacheEntries(nodeClass *v1.EC2NodeClass) {
 	}
 }
 
+// mockNodeClaim returns a NodeClaim for the EC2NodeClass that's used to generate the requests which are validated
+func mockNodeClaim(nodeClass *v1.EC2NodeClass) *karpv1.NodeClaim {
+	return &karpv1.NodeClaim{
+		Spec: karpv1.NodeClaimSpec{
+			NodeClassRef: &karpv1.NodeClassReference{
+				Name: nodeClass.ObjectMeta.Name,
+			},
+		},
+	}
+}
+
 func mockLaunchTemplateConfig() []ec2types.FleetLaunchTemplateConfigRequest {
 	return []ec2types.FleetLaunchTemplateConfigRequest{
 		{
@@ -397,6 +680,7 @@ func (v *Validation) mockLaunchTemplateOptions(
 	if len(selectedInstanceTypes) == 0 || lo.ContainsBy([]string{
 		v1.AMIFamilyWindows2019,
 		v1.AMIFamilyWindows2022,
+		v1.AMIFamilyWindows2025,
 	}, func(family string) bool {
 		return family == nodeClass.AMIFamily()
 	}) {
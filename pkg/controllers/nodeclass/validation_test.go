@@ -15,6 +15,8 @@ limitations under the License.
 package nodeclass_test
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
@@ -25,6 +27,8 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/aws/smithy-go"
 	corev1 "k8s.io/api/core/v1"
 	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -46,7 +50,7 @@ var _ = Describe("NodeClass Validation Status Controller", func() {
 	Context("Preconditions", func() {
 		var reconciler *nodeclass.Validation
 		BeforeEach(func() {
-			reconciler = nodeclass.NewValidationReconciler(env.Client, cloudProvider, awsEnv.EC2API, awsEnv.AMIResolver, awsEnv.InstanceTypesProvider, awsEnv.LaunchTemplateProvider, awsEnv.ValidationCache)
+			reconciler = nodeclass.NewValidationReconciler(env.Client, cloudProvider, recorder, awsEnv.EC2API, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIResolver, awsEnv.InstanceTypesProvider, awsEnv.LaunchTemplateProvider, awsEnv.PermissionProvider, awsEnv.KMSAPI, awsEnv.ValidationCache)
 			for _, cond := range []string{
 				v1.ConditionTypeAMIsReady,
 				v1.ConditionTypeInstanceProfileReady,
@@ -138,6 +142,146 @@ var _ = Describe("NodeClass Validation Status Controller", func() {
 			Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsTrue()).To(BeTrue())
 		})
 	})
+	Context("UserData Template Validation", func() {
+		BeforeEach(func() {
+			nodeClass.Spec.TemplateUserData = lo.ToPtr(true)
+		})
+		It("should update status condition as NotReady when the userData template is invalid", func() {
+			nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho {{ .NodePoolName ")
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsFalse()).To(BeTrue())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal("UserDataTemplateInvalid"))
+			Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
+		})
+		It("should update status condition as NotReady when the userData template references an unknown field", func() {
+			nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho {{ .NodePool }}")
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsFalse()).To(BeTrue())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal("UserDataTemplateInvalid"))
+		})
+		It("should update status condition as Ready when the userData template is valid", func() {
+			nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho {{ .NodePoolName | upper }} {{ .Zone }} {{ join \",\" .InstanceTypes }}")
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsTrue()).To(BeTrue())
+		})
+	})
+	Context("Network Validation", func() {
+		subnet := func(id, vpc string, mapPublicIP bool) ec2types.Subnet {
+			return ec2types.Subnet{
+				SubnetId:                lo.ToPtr(id),
+				VpcId:                   lo.ToPtr(vpc),
+				AvailabilityZone:        lo.ToPtr("test-zone-1a"),
+				AvailabilityZoneId:      lo.ToPtr("tstz1-1a"),
+				AvailableIpAddressCount: lo.ToPtr[int32](100),
+				MapPublicIpOnLaunch:     lo.ToPtr(mapPublicIP),
+			}
+		}
+		securityGroup := func(id, vpc string) ec2types.SecurityGroup {
+			return ec2types.SecurityGroup{GroupId: lo.ToPtr(id), GroupName: lo.ToPtr(id), VpcId: lo.ToPtr(vpc)}
+		}
+		BeforeEach(func() {
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+				subnet("subnet-a", "vpc-1", false),
+				subnet("subnet-b", "vpc-1", false),
+			}})
+			awsEnv.EC2API.DescribeSecurityGroupsBehavior.Output.Set(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []ec2types.SecurityGroup{
+				securityGroup("sg-a", "vpc-1"),
+			}})
+		})
+		It("should update status condition as Ready when subnets and security groups share a VPC", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsTrue()).To(BeTrue())
+		})
+		It("should update status condition as NotReady when subnets span multiple VPCs", func() {
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+				subnet("subnet-a", "vpc-1", false),
+				subnet("subnet-b", "vpc-2", false),
+			}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			cond := nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded)
+			Expect(cond.IsFalse()).To(BeTrue())
+			Expect(cond.Reason).To(Equal(nodeclass.ConditionReasonSubnetsInMultipleVPCs))
+			Expect(cond.Message).To(ContainSubstring("vpc-1 (subnet-a), vpc-2 (subnet-b)"))
+			Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
+		})
+		It("should update status condition as NotReady when security groups are in a different VPC than the subnets", func() {
+			awsEnv.EC2API.DescribeSecurityGroupsBehavior.Output.Set(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []ec2types.SecurityGroup{
+				securityGroup("sg-a", "vpc-1"),
+				securityGroup("sg-b", "vpc-2"),
+			}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			cond := nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded)
+			Expect(cond.IsFalse()).To(BeTrue())
+			Expect(cond.Reason).To(Equal(nodeclass.ConditionReasonSecurityGroupsNotInSubnetVPC))
+			Expect(cond.Message).To(ContainSubstring("sg-b (vpc-2)"))
+			Expect(cond.Message).ToNot(ContainSubstring("sg-a"))
+		})
+		It("should not call the authorization dry runs when the network is invalid", func() {
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+				subnet("subnet-a", "vpc-1", false),
+				subnet("subnet-b", "vpc-2", false),
+			}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.Calls()).To(BeZero())
+			Expect(awsEnv.EC2API.RunInstancesBehavior.Calls()).To(BeZero())
+		})
+		It("should warn when associatePublicIPAddress contradicts the subnets' public IP settings", func() {
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+				subnet("subnet-a", "vpc-1", true),
+				subnet("subnet-b", "vpc-1", false),
+			}})
+			nodeClass.Spec.AssociatePublicIPAddress = lo.ToPtr(false)
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsTrue()).To(BeTrue())
+			Expect(recorder.Calls("SubnetPublicIPMismatch")).To(Equal(1))
+			Expect(recorder.DetectedEvent("associatePublicIPAddress is false, but subnets subnet-a assign public IPs on launch; nodes launched in them will need a NAT gateway to reach the internet")).To(BeTrue())
+		})
+		It("should not warn when associatePublicIPAddress isn't set", func() {
+			awsEnv.EC2API.DescribeSubnetsBehavior.Output.Set(&ec2.DescribeSubnetsOutput{Subnets: []ec2types.Subnet{
+				subnet("subnet-a", "vpc-1", true),
+				subnet("subnet-b", "vpc-1", false),
+			}})
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			Expect(recorder.Calls("SubnetPublicIPMismatch")).To(BeZero())
+		})
+	})
+	Context("UserData Size Validation", func() {
+		It("should update status condition as NotReady when userData exceeds the EC2 limit", func() {
+			content := make([]byte, 24*1024)
+			_, err := rand.Read(content)
+			Expect(err).ToNot(HaveOccurred())
+			nodeClass.Spec.UserData = lo.ToPtr(fmt.Sprintf("#!/bin/bash\necho '%s'\n", base64.StdEncoding.EncodeToString(content)))
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsFalse()).To(BeTrue())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal(nodeclass.ConditionReasonUserDataTooLarge))
+			Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
+		})
+		It("should update status condition as Ready when large userData fits after compression", func() {
+			nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\n" + strings.Repeat("echo 'configuring node'\n", 1000))
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsTrue()).To(BeTrue())
+		})
+	})
 	Context("Authorization Validation", func() {
 		DescribeTable(
 			"NodeClass validation failure conditions",
@@ -148,7 +292,8 @@ var _ = Describe("NodeClass Validation Status Controller", func() {
 				nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 				Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsFalse()).To(BeTrue())
 				Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal(reason))
-				Expect(awsEnv.ValidationCache.Items()).To(HaveLen(1))
+				// Permissions and authorization are cached separately
+				Expect(awsEnv.ValidationCache.Items()).To(HaveLen(2))
 
 				// Even though we would succeed on the subsequent call, we should fail here because we hit the cache
 				ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
@@ -291,6 +436,99 @@ var _ = Describe("NodeClass Validation Status Controller", func() {
 			})
 		})
 	})
+	Context("Permission Validation", func() {
+		var reconciler *nodeclass.Validation
+		BeforeEach(func() {
+			reconciler = nodeclass.NewValidationReconciler(env.Client, cloudProvider, recorder, awsEnv.EC2API, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIResolver, awsEnv.InstanceTypesProvider, awsEnv.LaunchTemplateProvider, awsEnv.PermissionProvider, awsEnv.KMSAPI, awsEnv.ValidationCache)
+		})
+		It("should update status condition as NotReady when the controller can't pass the node role", func() {
+			awsEnv.IAMAPI.DeniedActions.Insert("iam:PassRole")
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsFalse()).To(BeTrue())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal(nodeclass.ConditionReasonPermissionsMissing))
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Message).To(Equal(fmt.Sprintf("Controller isn't authorized to call iam:PassRole on role/%s", nodeClass.Spec.Role)))
+			Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
+			Expect(recorder.Calls("PermissionsMissing")).To(Equal(1))
+			Expect(awsEnv.EC2API.CreateFleetBehavior.Calls()).To(BeZero())
+		})
+		It("should list every missing action", func() {
+			awsEnv.IAMAPI.DeniedActions.Insert("ec2:CreateTags", "ssm:GetParameter")
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{SSMParameter: "/custom/ami"}}
+			_, err := reconciler.Reconcile(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal(nodeclass.ConditionReasonPermissionsMissing))
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Message).To(Equal(
+				"Controller isn't authorized to call ec2:CreateTags on volume/*; ec2:CreateTags on network-interface/*; ssm:GetParameter on parameter/custom/ami",
+			))
+		})
+		It("should simulate tagging with the tags of the launch", func() {
+			nodeClass.Spec.Tags = map[string]string{"team": "compute"}
+			_, err := reconciler.Reconcile(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			var inputs []*iam.SimulatePrincipalPolicyInput
+			for awsEnv.IAMAPI.SimulatePrincipalPolicyBehavior.CalledWithInput.Len() > 0 {
+				inputs = append(inputs, awsEnv.IAMAPI.SimulatePrincipalPolicyBehavior.CalledWithInput.Pop())
+			}
+			input, ok := lo.Find(inputs, func(i *iam.SimulatePrincipalPolicyInput) bool { return i.ActionNames[0] == "ec2:CreateTags" })
+			Expect(ok).To(BeTrue())
+			Expect(input.ContextEntries).To(ContainElements(
+				iamtypes.ContextEntry{ContextKeyName: lo.ToPtr("aws:RequestTag/team"), ContextKeyValues: []string{"compute"}, ContextKeyType: iamtypes.ContextKeyTypeEnumString},
+				iamtypes.ContextEntry{ContextKeyName: lo.ToPtr("ec2:CreateAction"), ContextKeyValues: []string{"CreateFleet"}, ContextKeyType: iamtypes.ContextKeyTypeEnumString},
+			))
+		})
+		It("should simulate creating grants for the ARN of the block device mappings' KMS keys", func() {
+			nodeClass.Spec.BlockDeviceMappings = []*v1.BlockDeviceMapping{{
+				DeviceName: lo.ToPtr("/dev/xvda"),
+				EBS:        &v1.BlockDevice{Encrypted: lo.ToPtr(true), KMSKeyID: lo.ToPtr("test-key")},
+			}}
+			_, err := reconciler.Reconcile(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			var inputs []*iam.SimulatePrincipalPolicyInput
+			for awsEnv.IAMAPI.SimulatePrincipalPolicyBehavior.CalledWithInput.Len() > 0 {
+				inputs = append(inputs, awsEnv.IAMAPI.SimulatePrincipalPolicyBehavior.CalledWithInput.Pop())
+			}
+			input, ok := lo.Find(inputs, func(i *iam.SimulatePrincipalPolicyInput) bool { return i.ActionNames[0] == "kms:CreateGrant" })
+			Expect(ok).To(BeTrue())
+			Expect(input.ResourceArns).To(ConsistOf(fmt.Sprintf("arn:aws:kms:%s:%s:key/test-key", fake.DefaultRegion, fake.DefaultAccount)))
+		})
+		It("should update status condition as NotReady when the controller can't describe capacity reservations", func() {
+			nodeClass.Spec.CapacityReservationSelectorTerms = []v1.CapacityReservationSelectorTerm{{ID: "cr-12345"}}
+			awsEnv.EC2API.NextError.Set(&smithy.GenericAPIError{Code: "UnauthorizedOperation"})
+			_, err := reconciler.Reconcile(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal(nodeclass.ConditionReasonPermissionsMissing))
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Message).To(Equal("Controller isn't authorized to call ec2:DescribeCapacityReservations on *"))
+		})
+		It("should report missing permissions before the status is resolved for the current generation", func() {
+			awsEnv.IAMAPI.DeniedActions.Insert("iam:PassRole")
+			nodeClass.Generation = 2
+			_, err := reconciler.Reconcile(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal(nodeclass.ConditionReasonPermissionsMissing))
+
+			// Once the permission is granted, the previous result no longer applies
+			awsEnv.IAMAPI.DeniedActions.Delete("iam:PassRole")
+			awsEnv.ValidationCache.Flush()
+			_, err = reconciler.Reconcile(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsUnknown()).To(BeTrue())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal(nodeclass.ConditionReasonDependenciesNotReady))
+		})
+		It("should cache the result of the permission checks", func() {
+			awsEnv.IAMAPI.DeniedActions.Insert("iam:PassRole")
+			_, err := reconciler.Reconcile(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			calls := awsEnv.IAMAPI.SimulatePrincipalPolicyBehavior.Calls()
+
+			awsEnv.IAMAPI.DeniedActions.Delete("iam:PassRole")
+			_, err = reconciler.Reconcile(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).Reason).To(Equal(nodeclass.ConditionReasonPermissionsMissing))
+			Expect(awsEnv.IAMAPI.SimulatePrincipalPolicyBehavior.Calls()).To(Equal(calls))
+		})
+	})
 	It("should clear the validation cache when the nodeclass is deleted", func() {
 		controllerutil.AddFinalizer(nodeClass, v1.TerminationFinalizer)
 		nodeClass.Spec.Tags = map[string]string{}
@@ -299,7 +537,7 @@ var _ = Describe("NodeClass Validation Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.StatusConditions().Get(v1.ConditionTypeValidationSucceeded).IsTrue()).To(BeTrue())
 		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsTrue()).To(BeTrue())
-		Expect(awsEnv.ValidationCache.Items()).To(HaveLen(1))
+		Expect(awsEnv.ValidationCache.Items()).To(HaveLen(2))
 
 		Expect(env.Client.Delete(ctx, nodeClass)).To(Succeed())
 		ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
@@ -0,0 +1,130 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/awslabs/operatorpkg/singleton"
+	"github.com/samber/lo"
+	"go.uber.org/multierr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/clock"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
+)
+
+// MinimumAge is the age an instance profile must reach before it is considered for garbage collection. This prevents
+// deleting an instance profile that was just created for an EC2NodeClass whose status hasn't been persisted yet.
+const MinimumAge = 10 * time.Minute
+
+// Controller deletes the instance profiles that are left behind when the role of an EC2NodeClass changes. An instance
+// profile is deleted once it is no longer used by any EC2NodeClass and no instance references it.
+type Controller struct {
+	clk                     clock.Clock
+	kubeClient              client.Client
+	instanceProvider        instance.Provider
+	instanceProfileProvider instanceprofile.Provider
+	region                  string
+}
+
+func NewController(clk clock.Clock, kubeClient client.Client, instanceProvider instance.Provider, instanceProfileProvider instanceprofile.Provider, region string) *Controller {
+	return &Controller{
+		clk:                     clk,
+		kubeClient:              kubeClient,
+		instanceProvider:        instanceProvider,
+		instanceProfileProvider: instanceProfileProvider,
+		region:                  region,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "instanceprofile.garbagecollection")
+	clusterName := options.FromContext(ctx).ClusterName
+
+	// We LIST instance profiles BEFORE we grab EC2NodeClasses and instances so that an instance launched with one of
+	// the listed instance profiles is also listed
+	profiles, err := c.instanceProfileProvider.List(ctx, v1.InstanceProfilePathPrefix(clusterName, c.region))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	candidates := sets.New(lo.FilterMap(profiles, func(p iamtypes.InstanceProfile, _ int) (string, bool) {
+		return lo.FromPtr(p.InstanceProfileName), c.clk.Since(lo.FromPtr(p.CreateDate)) > MinimumAge
+	})...)
+	nodeClassList := &v1.EC2NodeClassList{}
+	if err = c.kubeClient.List(ctx, nodeClassList); err != nil {
+		return reconcile.Result{}, fmt.Errorf("listing nodeclasses, %w", err)
+	}
+	inUse := sets.New[string]()
+	for i := range nodeClassList.Items {
+		nodeClass := &nodeClassList.Items[i]
+		inUse.Insert(nodeClass.Status.InstanceProfile)
+		legacyName := nodeClass.InstanceProfileName(clusterName, c.region)
+		rotationTime := nodeClass.Status.InstanceProfileRotationTime
+		// Launches that were already in flight when the EC2NodeClass rotated its instance profile may still reference
+		// the previous instance profile, so none of its instance profiles are deleted until the rotation reaches the
+		// minimum age
+		if rotationTime != nil && c.clk.Since(rotationTime.Time) <= MinimumAge {
+			path := nodeClass.InstanceProfilePath(clusterName, c.region)
+			inUse.Insert(legacyName)
+			inUse.Insert(lo.FilterMap(profiles, func(p iamtypes.InstanceProfile, _ int) (string, bool) {
+				return lo.FromPtr(p.InstanceProfileName), lo.FromPtr(p.Path) == path
+			})...)
+			continue
+		}
+		// The original instance profile of an EC2NodeClass may have been created without a path, so it's checked by name
+		// once the EC2NodeClass has rotated to a different instance profile
+		if nodeClass.Spec.Role != "" && rotationTime != nil && nodeClass.Status.InstanceProfile != legacyName {
+			candidates.Insert(legacyName)
+		}
+	}
+	instances, err := c.instanceProvider.List(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("listing instances, %w", err)
+	}
+	for _, i := range instances {
+		inUse.Insert(i.InstanceProfile)
+	}
+	errs := lo.Map(sets.List(candidates.Difference(inUse)), func(name string, _ int) error {
+		if err := c.instanceProfileProvider.Delete(ctx, name); err != nil {
+			return err
+		}
+		log.FromContext(ctx).WithValues("instance-profile", name).V(1).Info("garbage collected instance profile")
+		return nil
+	})
+	if err = multierr.Combine(errs...); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("instanceprofile.garbagecollection").
+		WatchesRawSource(singleton.Source()).
+		Complete(singleton.AsReconciler(c))
+}
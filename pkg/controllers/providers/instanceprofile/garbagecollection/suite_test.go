@@ -0,0 +1,195 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollection_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/controllers/providers/instanceprofile/garbagecollection"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+
+	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
+)
+
+var ctx context.Context
+var awsEnv *test.Environment
+var env *coretest.Environment
+var garbageCollectionController *garbagecollection.Controller
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "InstanceProfileGarbageCollection")
+}
+
+var _ = BeforeSuite(func() {
+	ctx = options.ToContext(ctx, test.Options())
+	env = coretest.NewEnvironment(coretest.WithCRDs(apis.CRDs...), coretest.WithCRDs(v1alpha1.CRDs...))
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	awsEnv = test.NewEnvironment(ctx, env)
+	garbageCollectionController = garbagecollection.NewController(awsEnv.Clock, env.Client, awsEnv.InstanceProvider, awsEnv.InstanceProfileProvider, fake.DefaultRegion)
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	awsEnv.Reset()
+	awsEnv.Clock.SetTime(time.Now())
+})
+
+var _ = Describe("InstanceProfileGarbageCollection", func() {
+	var nodeClass *v1.EC2NodeClass
+	var clusterName string
+
+	BeforeEach(func() {
+		clusterName = options.FromContext(ctx).ClusterName
+		nodeClass = test.EC2NodeClass()
+	})
+	AfterEach(func() {
+		ExpectCleanedUp(ctx, env.Client)
+	})
+
+	createProfile := func(name, path string, age time.Duration) {
+		awsEnv.IAMAPI.InstanceProfiles[name] = &iamtypes.InstanceProfile{
+			CreateDate:          aws.Time(awsEnv.Clock.Now().Add(-age)),
+			InstanceProfileId:   aws.String(fake.InstanceProfileID()),
+			InstanceProfileName: aws.String(name),
+			Path:                lo.EmptyableToPtr(path),
+			Roles:               []iamtypes.Role{{RoleName: aws.String("test-role")}},
+		}
+	}
+	createInstance := func(profileName string) {
+		instanceID := fake.InstanceID()
+		awsEnv.EC2API.Instances.Store(instanceID, ec2types.Instance{
+			InstanceId: aws.String(instanceID),
+			State:      &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+			Tags: []ec2types.Tag{
+				{Key: aws.String(karpv1.NodePoolLabelKey), Value: aws.String("default")},
+				{Key: aws.String(v1.LabelNodeClass), Value: aws.String(nodeClass.Name)},
+				{Key: aws.String(v1.EKSClusterNameTagKey), Value: aws.String(clusterName)},
+			},
+			Placement:          &ec2types.Placement{AvailabilityZone: aws.String(fake.DefaultRegion)},
+			InstanceType:       "m5.large",
+			IamInstanceProfile: &ec2types.IamInstanceProfile{Arn: aws.String(fmt.Sprintf("arn:aws:iam::123456789012:instance-profile%s%s", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), profileName))},
+		})
+	}
+
+	It("should delete an instance profile that isn't used by an EC2NodeClass or an instance", func() {
+		nodeClass.Status.InstanceProfile = "current-profile"
+		ExpectApplied(ctx, env.Client, nodeClass)
+		createProfile("current-profile", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), time.Hour)
+		createProfile("previous-profile", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), time.Hour)
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey("current-profile"))
+		Expect(awsEnv.IAMAPI.InstanceProfiles).ToNot(HaveKey("previous-profile"))
+	})
+	It("should not delete an instance profile that is referenced by an instance", func() {
+		nodeClass.Status.InstanceProfile = "current-profile"
+		ExpectApplied(ctx, env.Client, nodeClass)
+		createProfile("current-profile", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), time.Hour)
+		createProfile("previous-profile", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), time.Hour)
+		createInstance("previous-profile")
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey("current-profile"))
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey("previous-profile"))
+	})
+	It("should not delete an instance profile that was recently created", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		createProfile("new-profile", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), time.Minute)
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey("new-profile"))
+	})
+	It("should not delete instance profiles outside of the cluster's path", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		createProfile("other-profile", "/", time.Hour)
+		createProfile("other-cluster-profile", v1.InstanceProfilePathPrefix("other-cluster", fake.DefaultRegion), time.Hour)
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey("other-profile"))
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey("other-cluster-profile"))
+	})
+	It("should delete the original instance profile of an EC2NodeClass once it has been rotated", func() {
+		nodeClass.Status.InstanceProfile = "current-profile"
+		nodeClass.Status.InstanceProfileRotationTime = lo.ToPtr(metav1.NewTime(awsEnv.Clock.Now().Add(-time.Hour)))
+		ExpectApplied(ctx, env.Client, nodeClass)
+		createProfile("current-profile", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), time.Hour)
+		createProfile(nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion), "", time.Hour)
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey("current-profile"))
+		Expect(awsEnv.IAMAPI.InstanceProfiles).ToNot(HaveKey(nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion)))
+	})
+	It("should not delete the original instance profile of an EC2NodeClass that hasn't been rotated", func() {
+		nodeClass.Status.InstanceProfile = "current-profile"
+		ExpectApplied(ctx, env.Client, nodeClass)
+		createProfile("current-profile", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), time.Hour)
+		createProfile(nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion), "", time.Hour)
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey(nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion)))
+	})
+	It("should not delete the previous instance profiles of an EC2NodeClass that was recently rotated", func() {
+		nodeClass.Status.InstanceProfile = "current-profile"
+		nodeClass.Status.InstanceProfileRotationTime = lo.ToPtr(metav1.NewTime(awsEnv.Clock.Now()))
+		ExpectApplied(ctx, env.Client, nodeClass)
+		createProfile("current-profile", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), time.Hour)
+		createProfile("previous-profile", nodeClass.InstanceProfilePath(clusterName, fake.DefaultRegion), time.Hour)
+		createProfile(nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion), "", time.Hour)
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey("previous-profile"))
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey(nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion)))
+
+		awsEnv.Clock.Step(garbagecollection.MinimumAge + time.Minute)
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey("current-profile"))
+		Expect(awsEnv.IAMAPI.InstanceProfiles).ToNot(HaveKey("previous-profile"))
+		Expect(awsEnv.IAMAPI.InstanceProfiles).ToNot(HaveKey(nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion)))
+	})
+	It("should not delete the original instance profile of an EC2NodeClass while it's in use", func() {
+		nodeClass.Status.InstanceProfile = nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion)
+		ExpectApplied(ctx, env.Client, nodeClass)
+		createProfile(nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion), "", time.Hour)
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey(nodeClass.InstanceProfileName(clusterName, fake.DefaultRegion)))
+	})
+})
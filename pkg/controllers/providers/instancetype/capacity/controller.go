@@ -75,16 +75,18 @@ func (c *Controller) Register(_ context.Context, m manager.Manager) error {
 	return controllerruntime.NewControllerManagedBy(m).
 		Named("providers.instancetype.capacity").
 		For(&corev1.Node{}, builder.WithPredicates(predicate.TypedFuncs[client.Object]{
-			// Only trigger reconciliation once a node becomes registered. This is an optimization to omit no-op reconciliations and reduce lock contention on the cache.
+			// Only trigger reconciliation once a node becomes initialized. Waiting for initialization rather than registration ensures extended
+			// resources (e.g. nvidia.com/gpu) have been registered by their device plugins before they're discovered. This is also an
+			// optimization to omit no-op reconciliations and reduce lock contention on the cache.
 			UpdateFunc: func(e event.TypedUpdateEvent[client.Object]) bool {
-				if e.ObjectOld.GetLabels()[karpv1.NodeRegisteredLabelKey] != "" {
+				if e.ObjectOld.GetLabels()[karpv1.NodeInitializedLabelKey] != "" {
 					return false
 				}
-				return e.ObjectNew.GetLabels()[karpv1.NodeRegisteredLabelKey] == "true"
+				return e.ObjectNew.GetLabels()[karpv1.NodeInitializedLabelKey] == "true"
 			},
-			// Reconcile against all Nodes added to the informer cache in a registered state. This allows us to hydrate the discovered capacity cache on controller startup.
+			// Reconcile against all Nodes added to the informer cache in an initialized state. This allows us to hydrate the discovered capacity cache on controller startup.
 			CreateFunc: func(e event.TypedCreateEvent[client.Object]) bool {
-				return e.Object.GetLabels()[karpv1.NodeRegisteredLabelKey] == "true"
+				return e.Object.GetLabels()[karpv1.NodeInitializedLabelKey] == "true"
 			},
 			DeleteFunc:  func(e event.TypedDeleteEvent[client.Object]) bool { return false },
 			GenericFunc: func(e event.TypedGenericEvent[client.Object]) bool { return false },
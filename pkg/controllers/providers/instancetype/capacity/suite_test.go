@@ -151,6 +151,7 @@ var _ = Describe("CapacityCache", func() {
 				Labels: map[string]string{
 					corev1.LabelInstanceTypeStable:   "t3.medium",
 					karpv1.NodeRegisteredLabelKey:    "true",
+					karpv1.NodeInitializedLabelKey:   "true",
 					"karpenter.k8s.aws/ec2nodeclass": nodeClass.Name,
 					corev1.LabelArchStable:           karpv1.ArchitectureAmd64,
 				},
@@ -190,6 +191,132 @@ var _ = Describe("CapacityCache", func() {
 		Expect(ok).To(BeTrue())
 		Expect(i.Capacity.Memory().Value()).To(Equal(node.Status.Capacity.Memory().Value()), "Expected capacity to match discovered node capacity")
 	})
+	It("should lower allocatable to the discovered allocatable without changing computed capacity", func() {
+		computed, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+		Expect(err).To(BeNil())
+		expected, ok := lo.Find(computed, func(i *karpcloudprovider.InstanceType) bool {
+			return i.Name == "t3.medium"
+		})
+		Expect(ok).To(BeTrue())
+		expectedCapacity := expected.Capacity.DeepCopy()
+		expectedKubeReserved := expected.Overhead.KubeReserved.DeepCopy()
+		awsEnv.InstanceTypeCache.Flush()
+
+		node.Status.Capacity = corev1.ResourceList{
+			corev1.ResourceCPU:              resource.MustParse("2"),
+			corev1.ResourceMemory:           resource.MustParse("3840Mi"),
+			corev1.ResourceEphemeralStorage: resource.MustParse("18Gi"),
+			corev1.ResourcePods:             resource.MustParse("10"),
+			"vpc.amazonaws.com/efa":         resource.MustParse("1"),
+		}
+		node.Status.Allocatable = corev1.ResourceList{
+			corev1.ResourceCPU:              resource.MustParse("1930m"),
+			corev1.ResourceMemory:           resource.MustParse("3200Mi"),
+			corev1.ResourceEphemeralStorage: resource.MustParse("16Gi"),
+			corev1.ResourcePods:             resource.MustParse("10"),
+			"vpc.amazonaws.com/efa":         resource.MustParse("1"),
+		}
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, controller, node)
+		instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+		Expect(err).To(BeNil())
+		i, ok := lo.Find(instanceTypes, func(i *karpcloudprovider.InstanceType) bool {
+			return i.Name == "t3.medium"
+		})
+		Expect(ok).To(BeTrue())
+		// Computed capacity is kept, other than memory and resources which aren't computed
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceEphemeralStorage, corev1.ResourcePods} {
+			actual := i.Capacity[name]
+			Expect(actual.Cmp(expectedCapacity[name])).To(Equal(0), "Expected capacity of %s to match computed capacity", name)
+		}
+		Expect(i.Capacity.Memory().String()).To(Equal("3840Mi"))
+		efa := i.Capacity["vpc.amazonaws.com/efa"]
+		Expect(efa.Value()).To(BeNumerically("==", 1))
+		for name, quantity := range expectedKubeReserved {
+			actual := i.Overhead.KubeReserved[name]
+			Expect(actual.Cmp(quantity)).To(Equal(0), "Expected kube reserved %s to match computed kube reserved", name)
+		}
+		allocatable := i.Allocatable()
+		for name, quantity := range node.Status.Allocatable {
+			actual := allocatable[name]
+			Expect(actual.Cmp(quantity)).To(Equal(0), "Expected allocatable of %s to match discovered node allocatable", name)
+		}
+	})
+	It("should learn allocatable resources that are computed as zero", func() {
+		node.Status.Allocatable = corev1.ResourceList{
+			"nvidia.com/gpu": resource.MustParse("2"),
+		}
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, controller, node)
+		instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+		Expect(err).To(BeNil())
+		i, ok := lo.Find(instanceTypes, func(i *karpcloudprovider.InstanceType) bool {
+			return i.Name == "t3.medium"
+		})
+		Expect(ok).To(BeTrue())
+		gpus := i.Allocatable()["nvidia.com/gpu"]
+		Expect(gpus.Value()).To(BeNumerically("==", 2))
+	})
+	It("should not apply capacity discovered for a different EC2NodeClass", func() {
+		node.Status.Allocatable = corev1.ResourceList{
+			corev1.ResourcePods: resource.MustParse("5"),
+		}
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, controller, node)
+
+		otherNodeClass := test.EC2NodeClass()
+		otherNodeClass.Status.AMIs = nodeClass.Status.AMIs
+		ExpectApplied(ctx, env.Client, otherNodeClass)
+		instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, otherNodeClass)
+		Expect(err).To(BeNil())
+		i, ok := lo.Find(instanceTypes, func(i *karpcloudprovider.InstanceType) bool {
+			return i.Name == "t3.medium"
+		})
+		Expect(ok).To(BeTrue())
+		Expect(i.Allocatable().Pods().Value()).To(BeNumerically(">", 5))
+	})
+	It("should not apply discovered capacity after the EC2NodeClass's kubelet configuration changes", func() {
+		node.Status.Allocatable = corev1.ResourceList{
+			corev1.ResourcePods: resource.MustParse("5"),
+		}
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, controller, node)
+
+		updated := nodeClass.DeepCopy()
+		updated.Spec.Kubelet = &v1.KubeletConfiguration{MaxPods: lo.ToPtr[int32](8)}
+		instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, updated)
+		Expect(err).To(BeNil())
+		i, ok := lo.Find(instanceTypes, func(i *karpcloudprovider.InstanceType) bool {
+			return i.Name == "t3.medium"
+		})
+		Expect(ok).To(BeTrue())
+		Expect(i.Allocatable().Pods().Value()).To(BeNumerically("==", 8))
+	})
+	It("should keep the lowest discovered value for each resource and ignore zero values", func() {
+		node.Status.Capacity = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("3840Mi"),
+			"nvidia.com/gpu":      resource.MustParse("1"),
+		}
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, controller, node)
+
+		node.Status.Capacity = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("3900Mi"),
+			"nvidia.com/gpu":      resource.MustParse("0"),
+		}
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, controller, node)
+
+		instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+		Expect(err).To(BeNil())
+		i, ok := lo.Find(instanceTypes, func(i *karpcloudprovider.InstanceType) bool {
+			return i.Name == "t3.medium"
+		})
+		Expect(ok).To(BeTrue())
+		Expect(i.Capacity.Memory().String()).To(Equal("3840Mi"))
+		gpus := i.Capacity["nvidia.com/gpu"]
+		Expect(gpus.Value()).To(BeNumerically("==", 1))
+	})
 	It("should use VM_MEMORY_OVERHEAD_PERCENT calculation after AMI update", func() {
 		ExpectObjectReconciled(ctx, env.Client, controller, node)
 
@@ -30,6 +30,12 @@ const (
 	UnauthorizedOperationErrorCode                 = "UnauthorizedOperation"
 	RateLimitingErrorCode                          = "RequestLimitExceeded"
 	ServiceLinkedRoleCreationNotPermittedErrorCode = "AuthFailure.ServiceLinkedRoleCreationNotPermitted"
+
+	// KMS, IAM, and STS error codes overlap with or differ from those of EC2, so they're only matched by the
+	// service specific helpers below
+	kmsNotFoundErrorCode     = "NotFoundException"
+	kmsAccessDeniedErrorCode = "AccessDeniedException"
+	iamAccessDeniedErrorCode = "AccessDenied"
 )
 
 var (
@@ -132,6 +138,41 @@ func IgnoreUnauthorizedOperationError(err error) error {
 	return err
 }
 
+// IsKMSNotFound returns true if the err is a KMS error (even if it's wrapped) which means the key wasn't found
+func IsKMSNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apiErr, ok := lo.ErrorsAs[smithy.APIError](err); ok {
+		return apiErr.ErrorCode() == kmsNotFoundErrorCode
+	}
+	return false
+}
+
+// IsKMSAccessDenied returns true if the err is a KMS error (even if it's wrapped) which means the caller isn't
+// authorized to perform the request
+func IsKMSAccessDenied(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apiErr, ok := lo.ErrorsAs[smithy.APIError](err); ok {
+		return apiErr.ErrorCode() == kmsAccessDeniedErrorCode
+	}
+	return false
+}
+
+// IsIAMAccessDenied returns true if the err is an IAM or STS error (even if it's wrapped) which means the caller isn't
+// authorized to perform the request
+func IsIAMAccessDenied(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apiErr, ok := lo.ErrorsAs[smithy.APIError](err); ok {
+		return apiErr.ErrorCode() == iamAccessDeniedErrorCode
+	}
+	return false
+}
+
 func IsRateLimitedError(err error) bool {
 	if err == nil {
 		return false
@@ -0,0 +1,271 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/awslabs/operatorpkg/object"
+	"github.com/awslabs/operatorpkg/status"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	corecloudprovider "sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
+	"github.com/aws/karpenter-provider-aws/pkg/cloudprovider"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/utils"
+)
+
+// Reasons that an instance type is rejected before it's filtered by the instance provider
+const (
+	RejectReasonIncompatible         = "IncompatibleRequirements"
+	RejectReasonInsufficientCapacity = "InsufficientCapacity"
+	RejectReasonNoAvailableOfferings = "NoAvailableOfferings"
+)
+
+// Explanation describes how a launch for a NodePool would be resolved with an EC2NodeClass
+type Explanation struct {
+	NodeClass       string           `json:"nodeClass"`
+	NodePool        string           `json:"nodePool"`
+	Ready           bool             `json:"ready"`
+	ReadyMessage    string           `json:"readyMessage,omitempty"`
+	CapacityType    string           `json:"capacityType,omitempty"`
+	InstanceTypes   []*InstanceType  `json:"instanceTypes"`
+	LaunchTemplates []LaunchTemplate `json:"launchTemplates"`
+	// Warnings are caveats about how closely the explanation reflects a launch by the controller
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type InstanceType struct {
+	Name      string     `json:"name"`
+	Offerings []Offering `json:"offerings"`
+	// RejectReason is the reason that the instance type wouldn't be launched, if any
+	RejectReason  string `json:"rejectReason,omitempty"`
+	RejectMessage string `json:"rejectMessage,omitempty"`
+	// ImageID is the AMI that the instance type would be launched with
+	ImageID string `json:"imageID,omitempty"`
+}
+
+type Offering struct {
+	Zone          string  `json:"zone"`
+	CapacityType  string  `json:"capacityType"`
+	ReservationID string  `json:"reservationID,omitempty"`
+	Price         float64 `json:"price"`
+	Available     bool    `json:"available"`
+	// InsufficientCapacity is true when the offering is unavailable since a recent launch returned an insufficient
+	// capacity error for it
+	InsufficientCapacity bool `json:"insufficientCapacity,omitempty"`
+}
+
+type LaunchTemplate struct {
+	Name                  string   `json:"name"`
+	ImageID               string   `json:"imageID"`
+	CapacityReservationID string   `json:"capacityReservationID,omitempty"`
+	InstanceTypes         []string `json:"instanceTypes"`
+	UserData              string   `json:"userData"`
+}
+
+// WarningInsufficientCapacityUnknown is reported when the Explainer doesn't have the controller's insufficient capacity
+// cache, which is only kept in memory
+const WarningInsufficientCapacityUnknown = "insufficient capacity errors are cached in memory by the controller and aren't reflected, so offerings may be unavailable even though they're shown as available"
+
+// Explainer resolves the instance types, AMIs, and launch templates that a launch would use, without creating any
+// resources
+type Explainer struct {
+	kubeClient             client.Client
+	cloudProvider          *cloudprovider.CloudProvider
+	launchTemplateProvider launchtemplate.Provider
+	unavailableOfferings   *awscache.UnavailableOfferings
+}
+
+// NewExplainer returns an Explainer. The unavailable offerings cache may be nil when the Explainer doesn't run in the
+// controller, in which case insufficient capacity errors aren't explained.
+func NewExplainer(kubeClient client.Client, cloudProvider *cloudprovider.CloudProvider, launchTemplateProvider launchtemplate.Provider,
+	unavailableOfferings *awscache.UnavailableOfferings) *Explainer {
+	return &Explainer{
+		kubeClient:             kubeClient,
+		cloudProvider:          cloudProvider,
+		launchTemplateProvider: launchTemplateProvider,
+		unavailableOfferings:   unavailableOfferings,
+	}
+}
+
+func (e *Explainer) Explain(ctx context.Context, nodeClassName, nodePoolName string) (*Explanation, error) {
+	nodeClass := &v1.EC2NodeClass{}
+	if err := e.kubeClient.Get(ctx, types.NamespacedName{Name: nodeClassName}, nodeClass); err != nil {
+		return nil, fmt.Errorf("getting ec2nodeclass, %w", err)
+	}
+	nodePool := &karpv1.NodePool{}
+	if err := e.kubeClient.Get(ctx, types.NamespacedName{Name: nodePoolName}, nodePool); err != nil {
+		return nil, fmt.Errorf("getting nodepool, %w", err)
+	}
+	if ref := nodePool.Spec.Template.Spec.NodeClassRef; ref == nil || ref.Name != nodeClass.Name || ref.Kind != object.GVK(nodeClass).Kind || ref.Group != object.GVK(nodeClass).Group {
+		return nil, fmt.Errorf("nodepool %q doesn't reference ec2nodeclass %q", nodePool.Name, nodeClass.Name)
+	}
+	ctx = account.IntoContext(ctx, nodeClass.Spec.AssumeRole)
+	ready := nodeClass.StatusConditions().Get(status.ConditionReady)
+	explanation := &Explanation{
+		NodeClass:       nodeClass.Name,
+		NodePool:        nodePool.Name,
+		Ready:           ready.IsTrue(),
+		ReadyMessage:    lo.FromPtr(ready).Message,
+		LaunchTemplates: []LaunchTemplate{},
+	}
+	if e.unavailableOfferings == nil {
+		explanation.Warnings = append(explanation.Warnings, WarningInsufficientCapacityUnknown)
+	}
+
+	nodeClaim := nodeClaimFromNodePool(nodePool)
+	instanceTypes, err := e.cloudProvider.GetInstanceTypes(ctx, nodePool)
+	if err != nil {
+		return nil, fmt.Errorf("getting instance types, %w", err)
+	}
+	var candidates []*corecloudprovider.InstanceType
+	explanation.InstanceTypes, candidates = e.explainInstanceTypes(nodeClaim, instanceTypes)
+	explained := lo.KeyBy(explanation.InstanceTypes, func(it *InstanceType) string { return it.Name })
+	filtered, rejected, err := instance.FilterRejectInstanceTypesWithReasons(nodeClaim, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("filtering instance types, %w", err)
+	}
+	for _, r := range rejected {
+		explained[r.Name].RejectReason = r.Reason
+	}
+	if len(filtered) == 0 {
+		return explanation, nil
+	}
+
+	explanation.CapacityType = instance.GetCapacityType(nodeClaim, filtered)
+	tags, err := utils.GetTags(nodeClass, nodeClaim, options.FromContext(ctx).ClusterName)
+	if err != nil {
+		return nil, fmt.Errorf("getting tags, %w", err)
+	}
+	resolvedLaunchTemplates, err := e.launchTemplateProvider.ResolveAll(ctx, nodeClass, nodeClaim, filtered, explanation.CapacityType, tags)
+	if err != nil {
+		return nil, fmt.Errorf("resolving launch templates, %w", err)
+	}
+	for _, resolved := range resolvedLaunchTemplates {
+		input, err := e.launchTemplateProvider.CreateLaunchTemplateInput(ctx, resolved)
+		if err != nil {
+			return nil, fmt.Errorf("rendering launch template, %w", err)
+		}
+		names := lo.Map(resolved.InstanceTypes, func(it *corecloudprovider.InstanceType, _ int) string { return it.Name })
+		sort.Strings(names)
+		for _, name := range names {
+			explained[name].ImageID = resolved.AMIID
+		}
+		explanation.LaunchTemplates = append(explanation.LaunchTemplates, LaunchTemplate{
+			Name:                  aws.ToString(input.LaunchTemplateName),
+			ImageID:               resolved.AMIID,
+			CapacityReservationID: resolved.CapacityReservationID,
+			InstanceTypes:         names,
+			UserData:              decodeUserData(aws.ToString(input.LaunchTemplateData.UserData)),
+		})
+	}
+	sort.Slice(explanation.LaunchTemplates, func(i, j int) bool {
+		return explanation.LaunchTemplates[i].Name < explanation.LaunchTemplates[j].Name
+	})
+	return explanation, nil
+}
+
+// explainInstanceTypes mirrors the instance type resolution of the CloudProvider, returning an explanation of every
+// instance type and the instance types that are compatible with the NodeClaim
+func (e *Explainer) explainInstanceTypes(nodeClaim *karpv1.NodeClaim, instanceTypes []*corecloudprovider.InstanceType) ([]*InstanceType, []*corecloudprovider.InstanceType) {
+	reqs := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...)
+	explained := make([]*InstanceType, 0, len(instanceTypes))
+	var candidates []*corecloudprovider.InstanceType
+	for _, it := range instanceTypes {
+		explanation := &InstanceType{
+			Name: it.Name,
+			Offerings: lo.Map(it.Offerings, func(o *corecloudprovider.Offering, _ int) Offering {
+				return Offering{
+					Zone:                 o.Zone(),
+					CapacityType:         o.CapacityType(),
+					ReservationID:        o.ReservationID(),
+					Price:                o.Price,
+					Available:            o.Available,
+					InsufficientCapacity: e.insufficientCapacity(it.Name, o),
+				}
+			}),
+		}
+		explained = append(explained, explanation)
+		if err := reqs.Compatible(it.Requirements, scheduling.AllowUndefinedWellKnownLabels); err != nil {
+			explanation.RejectReason = RejectReasonIncompatible
+			explanation.RejectMessage = err.Error()
+			continue
+		}
+		if compatible := it.Offerings.Compatible(reqs); len(compatible.Available()) == 0 {
+			explanation.RejectReason = lo.Ternary(lo.ContainsBy(compatible, func(o *corecloudprovider.Offering) bool {
+				return e.insufficientCapacity(it.Name, o)
+			}), RejectReasonInsufficientCapacity, RejectReasonNoAvailableOfferings)
+			continue
+		}
+		candidates = append(candidates, it)
+	}
+	return explained, candidates
+}
+
+// insufficientCapacity returns whether an offering is unavailable since a recent launch returned an insufficient
+// capacity error for it
+func (e *Explainer) insufficientCapacity(instanceType string, o *corecloudprovider.Offering) bool {
+	if e.unavailableOfferings == nil {
+		return false
+	}
+	return e.unavailableOfferings.IsUnavailable(ec2types.InstanceType(instanceType), o.Zone(), o.CapacityType())
+}
+
+// nodeClaimFromNodePool returns a NodeClaim with the requirements that the scheduler would give a NodeClaim for the
+// NodePool, before the requirements of any pods are added
+func nodeClaimFromNodePool(nodePool *karpv1.NodePool) *karpv1.NodeClaim {
+	requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodePool.Spec.Template.Spec.Requirements...)
+	requirements.Add(scheduling.NewLabelRequirements(nodePool.Spec.Template.Labels).Values()...)
+	requirements.Add(scheduling.NewRequirement(karpv1.NodePoolLabelKey, corev1.NodeSelectorOpIn, nodePool.Name))
+	return &karpv1.NodeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-dry-run", nodePool.Name),
+			Labels:      lo.Assign(nodePool.Spec.Template.Labels, map[string]string{karpv1.NodePoolLabelKey: nodePool.Name}),
+			Annotations: nodePool.Spec.Template.Annotations,
+		},
+		Spec: karpv1.NodeClaimSpec{
+			Taints:        nodePool.Spec.Template.Spec.Taints,
+			StartupTaints: nodePool.Spec.Template.Spec.StartupTaints,
+			Requirements:  requirements.NodeSelectorRequirements(),
+			NodeClassRef:  nodePool.Spec.Template.Spec.NodeClassRef,
+		},
+	}
+}
+
+// decodeUserData decodes user data for display, returning it as is if it isn't base64 encoded
+func decodeUserData(userData string) string {
+	decoded, err := base64.StdEncoding.DecodeString(userData)
+	if err != nil {
+		return userData
+	}
+	return string(decoded)
+}
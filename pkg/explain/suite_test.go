@@ -0,0 +1,197 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/awslabs/operatorpkg/object"
+	opstatus "github.com/awslabs/operatorpkg/status"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/events"
+	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/cloudprovider"
+	"github.com/aws/karpenter-provider-aws/pkg/explain"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var stop context.CancelFunc
+var env *coretest.Environment
+var awsEnv *test.Environment
+var explainer *explain.Explainer
+
+func TestExplain(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Explain")
+}
+
+var _ = BeforeSuite(func() {
+	env = coretest.NewEnvironment(
+		coretest.WithCRDs(test.DisableCapacityReservationIDValidation(test.RemoveNodeClassTagValidation(apis.CRDs))...),
+		coretest.WithCRDs(v1alpha1.CRDs...),
+	)
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options())
+	ctx, stop = context.WithCancel(ctx)
+	awsEnv = test.NewEnvironment(ctx, env)
+	cloudProvider := cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, events.NewRecorder(&record.FakeRecorder{}),
+		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.CapacityReservationProvider)
+	explainer = explain.NewExplainer(env.Client, cloudProvider, awsEnv.LaunchTemplateProvider, awsEnv.UnavailableOfferingsCache)
+})
+
+var _ = AfterSuite(func() {
+	stop()
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options())
+	awsEnv.Reset()
+
+	awsEnv.LaunchTemplateProvider.KubeDNSIP = net.ParseIP("10.0.100.10")
+	awsEnv.LaunchTemplateProvider.ClusterEndpoint = "https://test-cluster"
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = Describe("Explain", func() {
+	var nodeClass *v1.EC2NodeClass
+	var nodePool *karpv1.NodePool
+
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass()
+		nodeClass.StatusConditions().SetTrue(opstatus.ConditionReady)
+		nodePool = coretest.NodePool(karpv1.NodePool{
+			Spec: karpv1.NodePoolSpec{
+				Template: karpv1.NodeClaimTemplate{
+					Spec: karpv1.NodeClaimTemplateSpec{
+						NodeClassRef: &karpv1.NodeClassReference{
+							Group: object.GVK(nodeClass).Group,
+							Kind:  object.GVK(nodeClass).Kind,
+							Name:  nodeClass.Name,
+						},
+						Requirements: []karpv1.NodeSelectorRequirementWithMinValues{
+							{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: karpv1.CapacityTypeLabelKey, Operator: corev1.NodeSelectorOpIn, Values: []string{karpv1.CapacityTypeOnDemand}}},
+						},
+					},
+				},
+			},
+		})
+		Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypes(ctx)).To(Succeed())
+		Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypeOfferings(ctx)).To(Succeed())
+	})
+	It("should explain the launch templates without creating any resources", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		explanation, err := explainer.Explain(ctx, nodeClass.Name, nodePool.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(explanation.Ready).To(BeTrue())
+		Expect(explanation.CapacityType).To(Equal(karpv1.CapacityTypeOnDemand))
+		Expect(explanation.LaunchTemplates).ToNot(BeEmpty())
+		for _, lt := range explanation.LaunchTemplates {
+			Expect(lt.ImageID).To(BeElementOf("ami-test1", "ami-test2", "ami-test3", "ami-test4"))
+			Expect(lt.InstanceTypes).ToNot(BeEmpty())
+			Expect(lt.UserData).To(ContainSubstring("/etc/eks/bootstrap.sh"))
+		}
+		m5 := explanationFor(explanation, "m5.large")
+		Expect(m5.RejectReason).To(BeEmpty())
+		Expect(m5.ImageID).To(Equal("ami-test1"))
+
+		Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.Calls()).To(Equal(0))
+		Expect(awsEnv.EC2API.CreateFleetBehavior.Calls()).To(Equal(0))
+	})
+	It("should explain instance types that are incompatible with the nodepool", func() {
+		nodePool.Spec.Template.Spec.Requirements = append(nodePool.Spec.Template.Spec.Requirements, karpv1.NodeSelectorRequirementWithMinValues{
+			NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"m5.large"}},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		explanation, err := explainer.Explain(ctx, nodeClass.Name, nodePool.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(explanationFor(explanation, "m5.large").RejectReason).To(BeEmpty())
+		m5xlarge := explanationFor(explanation, "m5.xlarge")
+		Expect(m5xlarge.RejectReason).To(Equal(explain.RejectReasonIncompatible))
+		Expect(m5xlarge.RejectMessage).ToNot(BeEmpty())
+		Expect(explanation.LaunchTemplates).To(HaveLen(1))
+		Expect(explanation.LaunchTemplates[0].InstanceTypes).To(ConsistOf("m5.large"))
+	})
+	It("should explain instance types with offerings in the insufficient capacity cache", func() {
+		for _, zone := range []string{"test-zone-1a", "test-zone-1b", "test-zone-1c"} {
+			awsEnv.UnavailableOfferingsCache.MarkUnavailable(ctx, "InsufficientInstanceCapacity", "m5.large", zone, karpv1.CapacityTypeOnDemand)
+		}
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		explanation, err := explainer.Explain(ctx, nodeClass.Name, nodePool.Name)
+		Expect(err).ToNot(HaveOccurred())
+		m5 := explanationFor(explanation, "m5.large")
+		Expect(m5.RejectReason).To(Equal(explain.RejectReasonInsufficientCapacity))
+		Expect(lo.Filter(m5.Offerings, func(o explain.Offering, _ int) bool { return o.InsufficientCapacity })).To(HaveLen(3))
+		Expect(explanation.LaunchTemplates).ToNot(ContainElement(HaveField("InstanceTypes", ContainElement("m5.large"))))
+	})
+	It("should warn that insufficient capacity errors aren't explained without the insufficient capacity cache", func() {
+		cloudProvider := cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, events.NewRecorder(&record.FakeRecorder{}),
+			env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.CapacityReservationProvider)
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		explanation, err := explain.NewExplainer(env.Client, cloudProvider, awsEnv.LaunchTemplateProvider, nil).Explain(ctx, nodeClass.Name, nodePool.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(explanation.Warnings).To(ConsistOf(explain.WarningInsufficientCapacityUnknown))
+		Expect(lo.Flatten(lo.Map(explanation.InstanceTypes, func(it *explain.InstanceType, _ int) []explain.Offering { return it.Offerings }))).ToNot(ContainElement(HaveField("InsufficientCapacity", true)))
+	})
+	It("should not warn when explaining with the insufficient capacity cache", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		explanation, err := explainer.Explain(ctx, nodeClass.Name, nodePool.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(explanation.Warnings).To(BeEmpty())
+	})
+	It("should explain instance types that are rejected as exotic", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		explanation, err := explainer.Explain(ctx, nodeClass.Name, nodePool.Name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(explanationFor(explanation, "p3.8xlarge").RejectReason).To(Equal(instance.RejectReasonExotic))
+		Expect(explanationFor(explanation, "m5.metal").RejectReason).To(Equal(instance.RejectReasonExotic))
+	})
+	It("should fail when the nodepool doesn't reference the nodeclass", func() {
+		other := test.EC2NodeClass()
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass, other)
+		_, err := explainer.Explain(ctx, other.Name, nodePool.Name)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+func explanationFor(explanation *explain.Explanation, name string) *explain.InstanceType {
+	GinkgoHelper()
+	it, ok := lo.Find(explanation.InstanceTypes, func(it *explain.InstanceType) bool { return it.Name == name })
+	Expect(ok).To(BeTrue())
+	return it
+}
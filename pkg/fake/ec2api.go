@@ -292,6 +292,10 @@ func (e *EC2API) CreateTags(_ context.Context, input *ec2.CreateTagsInput, _ ...
 	return e.CreateTagsBehavior.Invoke(input, func(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
 		// Update passed in instances with the passed tags
 		for _, id := range input.Resources {
+			// Volumes aren't tracked by the fake, their tags can be validated through CalledWithInput
+			if strings.HasPrefix(id, "vol-") {
+				continue
+			}
 			raw, ok := e.Instances.Load(id)
 			if !ok {
 				return nil, serrors.Wrap(fmt.Errorf("instance does not exist"), "instance-id", id)
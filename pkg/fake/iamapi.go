@@ -17,6 +17,7 @@ package fake
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/aws/smithy-go"
 	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
 )
@@ -35,11 +37,13 @@ const ()
 // pollute each other.
 type IAMAPIBehavior struct {
 	GetInstanceProfileBehavior            MockedFunction[iam.GetInstanceProfileInput, iam.GetInstanceProfileOutput]
+	ListInstanceProfilesBehavior          MockedFunction[iam.ListInstanceProfilesInput, iam.ListInstanceProfilesOutput]
 	CreateInstanceProfileBehavior         MockedFunction[iam.CreateInstanceProfileInput, iam.CreateInstanceProfileOutput]
 	DeleteInstanceProfileBehavior         MockedFunction[iam.DeleteInstanceProfileInput, iam.DeleteInstanceProfileOutput]
 	AddRoleToInstanceProfileBehavior      MockedFunction[iam.AddRoleToInstanceProfileInput, iam.AddRoleToInstanceProfileOutput]
 	TagInstanceProfileBehavior            MockedFunction[iam.TagInstanceProfileInput, iam.TagInstanceProfileOutput]
 	RemoveRoleFromInstanceProfileBehavior MockedFunction[iam.RemoveRoleFromInstanceProfileInput, iam.RemoveRoleFromInstanceProfileOutput]
+	SimulatePrincipalPolicyBehavior       MockedFunction[iam.SimulatePrincipalPolicyInput, iam.SimulatePrincipalPolicyOutput]
 }
 
 type IAMAPI struct {
@@ -49,19 +53,24 @@ type IAMAPI struct {
 	IAMAPIBehavior
 
 	InstanceProfiles map[string]*iamtypes.InstanceProfile
+	// DeniedActions are the actions which are implicitly denied when simulating policies. All other actions are allowed.
+	DeniedActions sets.Set[string]
 }
 
 func NewIAMAPI() *IAMAPI {
-	return &IAMAPI{InstanceProfiles: map[string]*iamtypes.InstanceProfile{}}
+	return &IAMAPI{InstanceProfiles: map[string]*iamtypes.InstanceProfile{}, DeniedActions: sets.New[string]()}
 }
 
 func (s *IAMAPI) Reset() {
 	s.GetInstanceProfileBehavior.Reset()
+	s.ListInstanceProfilesBehavior.Reset()
 	s.CreateInstanceProfileBehavior.Reset()
 	s.DeleteInstanceProfileBehavior.Reset()
 	s.AddRoleToInstanceProfileBehavior.Reset()
 	s.RemoveRoleFromInstanceProfileBehavior.Reset()
+	s.SimulatePrincipalPolicyBehavior.Reset()
 	s.InstanceProfiles = map[string]*iamtypes.InstanceProfile{}
+	s.DeniedActions = sets.New[string]()
 }
 
 func (s *IAMAPI) GetInstanceProfile(_ context.Context, input *iam.GetInstanceProfileInput, _ ...func(*iam.Options)) (*iam.GetInstanceProfileOutput, error) {
@@ -80,6 +89,21 @@ func (s *IAMAPI) GetInstanceProfile(_ context.Context, input *iam.GetInstancePro
 	})
 }
 
+func (s *IAMAPI) ListInstanceProfiles(_ context.Context, input *iam.ListInstanceProfilesInput, _ ...func(*iam.Options)) (*iam.ListInstanceProfilesOutput, error) {
+	return s.ListInstanceProfilesBehavior.Invoke(input, func(*iam.ListInstanceProfilesInput) (*iam.ListInstanceProfilesOutput, error) {
+		s.Lock()
+		defer s.Unlock()
+
+		// IAM treats a missing path prefix as "/", which matches every instance profile
+		prefix := lo.Ternary(input.PathPrefix != nil, aws.ToString(input.PathPrefix), "/")
+		return &iam.ListInstanceProfilesOutput{
+			InstanceProfiles: lo.FilterMap(lo.Values(s.InstanceProfiles), func(i *iamtypes.InstanceProfile, _ int) (iamtypes.InstanceProfile, bool) {
+				return *i, strings.HasPrefix(lo.Ternary(i.Path != nil, aws.ToString(i.Path), "/"), prefix)
+			}),
+		}, nil
+	})
+}
+
 func (s *IAMAPI) CreateInstanceProfile(_ context.Context, input *iam.CreateInstanceProfileInput, _ ...func(*iam.Options)) (*iam.CreateInstanceProfileOutput, error) {
 	return s.CreateInstanceProfileBehavior.Invoke(input, func(output *iam.CreateInstanceProfileInput) (*iam.CreateInstanceProfileOutput, error) {
 		s.Lock()
@@ -197,3 +221,22 @@ func (s *IAMAPI) RemoveRoleFromInstanceProfile(_ context.Context, input *iam.Rem
 		}
 	})
 }
+
+func (s *IAMAPI) SimulatePrincipalPolicy(_ context.Context, input *iam.SimulatePrincipalPolicyInput, _ ...func(*iam.Options)) (*iam.SimulatePrincipalPolicyOutput, error) {
+	return s.SimulatePrincipalPolicyBehavior.Invoke(input, func(*iam.SimulatePrincipalPolicyInput) (*iam.SimulatePrincipalPolicyOutput, error) {
+		s.Lock()
+		defer s.Unlock()
+
+		var results []iamtypes.EvaluationResult
+		for _, action := range input.ActionNames {
+			for _, resource := range lo.Ternary(len(input.ResourceArns) > 0, input.ResourceArns, []string{"*"}) {
+				results = append(results, iamtypes.EvaluationResult{
+					EvalActionName:   aws.String(action),
+					EvalResourceName: aws.String(resource),
+					EvalDecision:     lo.Ternary(s.DeniedActions.Has(action), iamtypes.PolicyEvaluationDecisionTypeImplicitDeny, iamtypes.PolicyEvaluationDecisionTypeAllowed),
+				})
+			}
+		}
+		return &iam.SimulatePrincipalPolicyOutput{EvaluationResults: results}, nil
+	})
+}
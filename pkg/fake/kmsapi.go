@@ -0,0 +1,78 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/samber/lo"
+
+	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
+)
+
+// KMSAPIBehavior must be reset between tests otherwise tests will
+// pollute each other.
+type KMSAPIBehavior struct {
+	DescribeKeyBehavior MockedFunction[kms.DescribeKeyInput, kms.DescribeKeyOutput]
+}
+
+type KMSAPI struct {
+	sync.Mutex
+
+	sdk.KMSAPI
+	KMSAPIBehavior
+
+	// Keys are the keys known to the fake, keyed by ID. Keys which aren't known are treated as enabled symmetric
+	// encryption keys.
+	Keys map[string]*kmstypes.KeyMetadata
+}
+
+func NewKMSAPI() *KMSAPI {
+	return &KMSAPI{Keys: map[string]*kmstypes.KeyMetadata{}}
+}
+
+// Reset must be called between tests otherwise tests will pollute
+// each other.
+func (s *KMSAPI) Reset() {
+	s.DescribeKeyBehavior.Reset()
+	s.Keys = map[string]*kmstypes.KeyMetadata{}
+}
+
+func (s *KMSAPI) DescribeKey(_ context.Context, input *kms.DescribeKeyInput, _ ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	return s.DescribeKeyBehavior.Invoke(input, func(*kms.DescribeKeyInput) (*kms.DescribeKeyOutput, error) {
+		return &kms.DescribeKeyOutput{KeyMetadata: s.keyMetadata(lo.FromPtr(input.KeyId))}, nil
+	})
+}
+
+func (s *KMSAPI) keyMetadata(id string) *kmstypes.KeyMetadata {
+	s.Lock()
+	defer s.Unlock()
+
+	if metadata, ok := s.Keys[id]; ok {
+		return metadata
+	}
+	return &kmstypes.KeyMetadata{
+		KeyId:    lo.ToPtr(id),
+		Arn:      lo.ToPtr(fmt.Sprintf("arn:aws:kms:%s:%s:key/%s", DefaultRegion, DefaultAccount, id)),
+		Enabled:  true,
+		KeyState: kmstypes.KeyStateEnabled,
+		KeyUsage: kmstypes.KeyUsageTypeEncryptDecrypt,
+		KeySpec:  kmstypes.KeySpecSymmetricDefault,
+	}
+}
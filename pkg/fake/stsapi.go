@@ -0,0 +1,73 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/samber/lo"
+
+	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
+)
+
+// STSAPIBehavior must be reset between tests otherwise tests will
+// pollute each other.
+type STSAPIBehavior struct {
+	AssumeRoleBehavior        MockedFunction[sts.AssumeRoleInput, sts.AssumeRoleOutput]
+	GetCallerIdentityBehavior MockedFunction[sts.GetCallerIdentityInput, sts.GetCallerIdentityOutput]
+}
+
+type STSAPI struct {
+	sdk.STSAPI
+	STSAPIBehavior
+}
+
+func NewSTSAPI() *STSAPI {
+	return &STSAPI{}
+}
+
+// Reset must be called between tests otherwise tests will pollute
+// each other.
+func (s *STSAPI) Reset() {
+	s.AssumeRoleBehavior.Reset()
+	s.GetCallerIdentityBehavior.Reset()
+}
+
+func (s *STSAPI) AssumeRole(_ context.Context, input *sts.AssumeRoleInput, _ ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	return s.AssumeRoleBehavior.Invoke(input, func(*sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+		return &sts.AssumeRoleOutput{
+			Credentials: &ststypes.Credentials{
+				AccessKeyId:     lo.ToPtr("access-key-id"),
+				SecretAccessKey: lo.ToPtr("secret-access-key"),
+				SessionToken:    lo.ToPtr(lo.FromPtr(input.RoleArn)),
+				Expiration:      lo.ToPtr(time.Now().Add(time.Hour)),
+			},
+		}, nil
+	})
+}
+
+func (s *STSAPI) GetCallerIdentity(_ context.Context, input *sts.GetCallerIdentityInput, _ ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return s.GetCallerIdentityBehavior.Invoke(input, func(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+		return &sts.GetCallerIdentityOutput{
+			Account: lo.ToPtr(DefaultAccount),
+			Arn:     lo.ToPtr(fmt.Sprintf("arn:aws:sts::%s:assumed-role/KarpenterControllerRole/karpenter", DefaultAccount)),
+			UserId:  lo.ToPtr("AROAEXAMPLE:karpenter"),
+		}, nil
+	})
+}
@@ -31,7 +31,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/awslabs/operatorpkg/aws/middleware"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
@@ -50,20 +52,24 @@ import (
 
 	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/operator"
+	karpoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 
 	prometheusv2 "github.com/jonathan-innis/aws-sdk-go-prometheus/v2"
 
 	"sigs.k8s.io/karpenter/pkg/apis"
 
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
 	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/permission"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 	ssmp "github.com/aws/karpenter-provider-aws/pkg/providers/ssm"
@@ -83,6 +89,7 @@ type Operator struct {
 	UnavailableOfferingsCache   *awscache.UnavailableOfferings
 	SSMCache                    *cache.Cache
 	ValidationCache             *cache.Cache
+	AccountProvider             account.Provider
 	SubnetProvider              subnet.Provider
 	SecurityGroupProvider       securitygroup.Provider
 	InstanceProfileProvider     instanceprofile.Provider
@@ -95,7 +102,9 @@ type Operator struct {
 	InstanceProvider            instance.Provider
 	SSMProvider                 ssmp.Provider
 	CapacityReservationProvider capacityreservation.Provider
-	EC2API                      *ec2.Client
+	PermissionProvider          permission.Provider
+	EC2API                      sdk.EC2API
+	KMSAPI                      *kms.Client
 }
 
 func NewOperator(ctx context.Context, operator *operator.Operator) (context.Context, *Operator) {
@@ -121,13 +130,24 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		region := lo.Must(imds.NewFromConfig(cfg).GetRegion(ctx, nil))
 		cfg.Region = region.Region
 	}
-	ec2api := ec2.NewFromConfig(cfg)
+	stsapi := sts.NewFromConfig(cfg)
+	iamapi := iam.NewFromConfig(cfg)
+	accountProvider := account.NewDefaultProvider(stsapi, cache.New(awscache.AssumeRoleCredentialsTTL, awscache.DefaultCleanupInterval))
+	// EC2 calls are made in the account of the role that the context is scoped to, if any
+	ec2api := account.NewEC2API(ec2.NewFromConfig(cfg), accountProvider)
 	eksapi := eks.NewFromConfig(cfg)
 	if err := CheckEC2Connectivity(ctx, ec2api); err != nil {
 		log.FromContext(ctx).Error(err, "ec2 api connectivity check failed")
 		os.Exit(1)
 	}
 	log.FromContext(ctx).WithValues("region", cfg.Region).V(1).Info("discovered region")
+	permissionProvider := permission.NewDefaultProvider(ec2api, iamapi, stsapi, cfg.Region, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+	// Simulated policies can't account for every condition, so missing permissions don't prevent startup
+	if missing, err := CheckPermissions(ctx, permissionProvider); err != nil {
+		log.FromContext(ctx).Error(err, "failed checking controller permissions")
+	} else if len(missing) > 0 {
+		log.FromContext(ctx).WithValues("missing-actions", missing).Error(fmt.Errorf("controller isn't authorized to call %s", strings.Join(missing, "; ")), "permission check failed, launches are likely to fail")
+	}
 	clusterEndpoint, err := ResolveClusterEndpoint(ctx, eksapi)
 	if err != nil {
 		log.FromContext(ctx).Error(err, "failed detecting cluster endpoint")
@@ -148,8 +168,8 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 	validationCache := cache.New(awscache.ValidationTTL, awscache.DefaultCleanupInterval)
 
 	subnetProvider := subnet.NewDefaultProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval), cache.New(awscache.AvailableIPAddressTTL, awscache.DefaultCleanupInterval), cache.New(awscache.AssociatePublicIPAddressTTL, awscache.DefaultCleanupInterval))
-	securityGroupProvider := securitygroup.NewDefaultProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
-	instanceProfileProvider := instanceprofile.NewDefaultProvider(iam.NewFromConfig(cfg), cache.New(awscache.InstanceProfileTTL, awscache.DefaultCleanupInterval))
+	securityGroupProvider := securitygroup.NewDefaultProvider(ec2api, eksapi, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+	instanceProfileProvider := instanceprofile.NewDefaultProvider(iamapi, cache.New(awscache.InstanceProfileTTL, awscache.DefaultCleanupInterval))
 	pricingProvider := pricing.NewDefaultProvider(
 		pricing.NewAPI(cfg),
 		ec2api,
@@ -215,6 +235,7 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		UnavailableOfferingsCache:   unavailableOfferingsCache,
 		SSMCache:                    ssmCache,
 		ValidationCache:             validationCache,
+		AccountProvider:             accountProvider,
 		SubnetProvider:              subnetProvider,
 		SecurityGroupProvider:       securityGroupProvider,
 		InstanceProfileProvider:     instanceProfileProvider,
@@ -227,7 +248,9 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		InstanceProvider:            instanceProvider,
 		SSMProvider:                 ssmProvider,
 		CapacityReservationProvider: capacityReservationProvider,
+		PermissionProvider:          permissionProvider,
 		EC2API:                      ec2api,
+		KMSAPI:                      kms.NewFromConfig(cfg),
 	}
 }
 
@@ -253,6 +276,43 @@ func CheckEC2Connectivity(ctx context.Context, api sdk.EC2API) error {
 	return err
 }
 
+// CheckPermissions returns the actions that every launch depends on which the controller isn't authorized to perform.
+// Permissions that depend on the configuration of an EC2NodeClass are reported in its status.
+func CheckPermissions(ctx context.Context, permissionProvider permission.Provider) ([]string, error) {
+	clusterName := options.FromContext(ctx).ClusterName
+	tags := permission.RequestTagContext(map[string]string{
+		fmt.Sprintf("kubernetes.io/cluster/%s", clusterName): "owned",
+		v1.NodePoolTagKey:       "default",
+		v1.EKSClusterNameTagKey: clusterName,
+		v1.NodeClassTagKey:      "default",
+	})
+	resourceTags := map[string][]string{
+		fmt.Sprintf("aws:ResourceTag/kubernetes.io/cluster/%s", clusterName): {"owned"},
+		fmt.Sprintf("aws:ResourceTag/%s", v1.NodePoolTagKey):                 {"default"},
+	}
+	checks := []permission.Check{
+		{Action: "ec2:CreateFleet", Resource: "fleet/*", Context: tags},
+		{Action: "ec2:RunInstances", Resource: "instance/*", Context: tags},
+		{Action: "ec2:CreateLaunchTemplate", Resource: "launch-template/*", Context: tags},
+		{Action: "ec2:CreateTags", Resource: "launch-template/*", Context: lo.Assign(tags, map[string][]string{"ec2:CreateAction": {"CreateLaunchTemplate"}})},
+		{Action: "ec2:DeleteLaunchTemplate", Resource: "launch-template/*", Context: resourceTags},
+		{Action: "ec2:TerminateInstances", Resource: "instance/*", Context: resourceTags},
+		// EKS optimized AMIs are resolved from public SSM parameters
+		{Action: "ssm:GetParameter", Resource: "parameter/aws/service/*"},
+	}
+	for _, resource := range []string{"fleet/*", "instance/*", "volume/*", "network-interface/*"} {
+		checks = append(checks, permission.Check{Action: "ec2:CreateTags", Resource: resource, Context: lo.Assign(tags, map[string][]string{"ec2:CreateAction": {"CreateFleet"}})})
+	}
+	if karpoptions.FromContext(ctx).FeatureGates.ReservedCapacity {
+		checks = append(checks, permission.Check{Action: "ec2:DescribeCapacityReservations", Resource: "*"})
+	}
+	missing, err := permissionProvider.Missing(ctx, checks...)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(missing, func(c permission.Check, _ int) string { return c.String() }), nil
+}
+
 func ResolveClusterEndpoint(ctx context.Context, eksAPI sdk.EKSAPI) (string, error) {
 	clusterEndpointFromOptions := options.FromContext(ctx).ClusterEndpoint
 	if clusterEndpointFromOptions != "" {
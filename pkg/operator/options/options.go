@@ -42,6 +42,9 @@ type Options struct {
 	VMMemoryOverheadPercent float64
 	InterruptionQueue       string
 	ReservedENIs            int
+	ReportInstanceTypes     bool
+	SubnetIPThreshold       int
+	ValidateSecurityGroups  bool
 }
 
 func (o *Options) AddFlags(fs *coreoptions.FlagSet) {
@@ -53,6 +56,9 @@ func (o *Options) AddFlags(fs *coreoptions.FlagSet) {
 	fs.Float64Var(&o.VMMemoryOverheadPercent, "vm-memory-overhead-percent", utils.WithDefaultFloat64("VM_MEMORY_OVERHEAD_PERCENT", 0.075), "The VM memory overhead as a percent that will be subtracted from the total memory for all instance types when cached information is unavailable.")
 	fs.StringVar(&o.InterruptionQueue, "interruption-queue", env.WithDefaultString("INTERRUPTION_QUEUE", ""), "Interruption queue is the name of the SQS queue used for processing interruption events from EC2. Interruption handling is disabled if not specified. Enabling interruption handling may require additional permissions on the controller service account. Additional permissions are outlined in the docs.")
 	fs.IntVar(&o.ReservedENIs, "reserved-enis", env.WithDefaultInt("RESERVED_ENIS", 0), "Reserved ENIs are not included in the calculations for max-pods or kube-reserved. This is most often used in the VPC CNI custom networking setup https://docs.aws.amazon.com/eks/latest/userguide/cni-custom-network.html.")
+	fs.BoolVarWithEnv(&o.ReportInstanceTypes, "report-instance-types", "REPORT_INSTANCE_TYPES", false, "If true, then list the instance types that each EC2NodeClass can launch, with their zones and capacity types, in the status of the EC2NodeClass.")
+	fs.IntVar(&o.SubnetIPThreshold, "subnet-ip-threshold", env.WithDefaultInt("SUBNET_IP_THRESHOLD", 0), "The number of available IPs below which the best subnet in a zone is considered to be running out of IPs. EC2NodeClasses with such a zone have their SubnetsHaveCapacity condition set to false. The check is disabled when this is 0.")
+	fs.BoolVarWithEnv(&o.ValidateSecurityGroups, "validate-security-groups", "VALIDATE_SECURITY_GROUPS", false, "If true, then check that the rules of the security groups selected by each EC2NodeClass allow the EKS control plane and the nodes to connect to each other. Requires eks:DescribeCluster.")
 }
 
 func (o *Options) Parse(fs *coreoptions.FlagSet, args ...string) error {
@@ -27,6 +27,7 @@ func (o *Options) Validate() error {
 		o.validateEndpoint(),
 		o.validateVMMemoryOverheadPercent(),
 		o.validateReservedENIs(),
+		o.validateSubnetIPThreshold(),
 		o.validateRequiredFields(),
 	)
 }
@@ -58,6 +59,13 @@ func (o *Options) validateReservedENIs() error {
 	return nil
 }
 
+func (o *Options) validateSubnetIPThreshold() error {
+	if o.SubnetIPThreshold < 0 {
+		return fmt.Errorf("subnet-ip-threshold cannot be negative")
+	}
+	return nil
+}
+
 func (o *Options) validateRequiredFields() error {
 	if o.ClusterName == "" {
 		return fmt.Errorf("missing field, cluster-name")
@@ -62,7 +62,10 @@ var _ = Describe("Options", func() {
 			"--isolated-vpc",
 			"--vm-memory-overhead-percent", "0.1",
 			"--interruption-queue", "env-cluster",
-			"--reserved-enis", "10")
+			"--reserved-enis", "10",
+			"--report-instance-types",
+			"--subnet-ip-threshold", "20",
+			"--validate-security-groups")
 		Expect(err).ToNot(HaveOccurred())
 		expectOptionsEqual(opts, test.Options(test.OptionsFields{
 			ClusterCABundle:         lo.ToPtr("env-bundle"),
@@ -72,6 +75,9 @@ var _ = Describe("Options", func() {
 			VMMemoryOverheadPercent: lo.ToPtr[float64](0.1),
 			InterruptionQueue:       lo.ToPtr("env-cluster"),
 			ReservedENIs:            lo.ToPtr(10),
+			ReportInstanceTypes:     lo.ToPtr(true),
+			SubnetIPThreshold:       lo.ToPtr(20),
+			ValidateSecurityGroups:  lo.ToPtr(true),
 		}))
 	})
 	It("should correctly fallback to env vars when CLI flags aren't set", func() {
@@ -82,6 +88,9 @@ var _ = Describe("Options", func() {
 		os.Setenv("VM_MEMORY_OVERHEAD_PERCENT", "0.1")
 		os.Setenv("INTERRUPTION_QUEUE", "env-cluster")
 		os.Setenv("RESERVED_ENIS", "10")
+		os.Setenv("REPORT_INSTANCE_TYPES", "true")
+		os.Setenv("SUBNET_IP_THRESHOLD", "20")
+		os.Setenv("VALIDATE_SECURITY_GROUPS", "true")
 
 		// Add flags after we set the environment variables so that the parsing logic correctly refers
 		// to the new environment variable values
@@ -96,6 +105,9 @@ var _ = Describe("Options", func() {
 			VMMemoryOverheadPercent: lo.ToPtr[float64](0.1),
 			InterruptionQueue:       lo.ToPtr("env-cluster"),
 			ReservedENIs:            lo.ToPtr(10),
+			ReportInstanceTypes:     lo.ToPtr(true),
+			SubnetIPThreshold:       lo.ToPtr(20),
+			ValidateSecurityGroups:  lo.ToPtr(true),
 		}))
 	})
 
@@ -119,6 +131,10 @@ var _ = Describe("Options", func() {
 			err := opts.Parse(fs, "--cluster-name", "test-cluster", "--reserved-enis", "-1")
 			Expect(err).To(HaveOccurred())
 		})
+		It("should fail when subnetIPThreshold is negative", func() {
+			err := opts.Parse(fs, "--cluster-name", "test-cluster", "--subnet-ip-threshold", "-1")
+			Expect(err).To(HaveOccurred())
+		})
 	})
 })
 
@@ -131,4 +147,7 @@ func expectOptionsEqual(optsA *options.Options, optsB *options.Options) {
 	Expect(optsA.VMMemoryOverheadPercent).To(Equal(optsB.VMMemoryOverheadPercent))
 	Expect(optsA.InterruptionQueue).To(Equal(optsB.InterruptionQueue))
 	Expect(optsA.ReservedENIs).To(Equal(optsB.ReservedENIs))
+	Expect(optsA.ReportInstanceTypes).To(Equal(optsB.ReportInstanceTypes))
+	Expect(optsA.SubnetIPThreshold).To(Equal(optsB.SubnetIPThreshold))
+	Expect(optsA.ValidateSecurityGroups).To(Equal(optsB.ValidateSecurityGroups))
 }
@@ -23,14 +23,19 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/smithy-go"
+	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
 
+	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 	coretest "sigs.k8s.io/karpenter/pkg/test"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
 	"github.com/aws/karpenter-provider-aws/pkg/fake"
 	awscontext "github.com/aws/karpenter-provider-aws/pkg/operator"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/permission"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -104,4 +109,41 @@ var _ = Describe("Operator", func() {
 		_, err := awscontext.ResolveClusterEndpoint(ctx, fakeEKSAPI)
 		Expect(err).To(HaveOccurred())
 	})
+	Context("Permissions", func() {
+		var ec2api *fake.EC2API
+		var iamapi *fake.IAMAPI
+		var permissionProvider *permission.DefaultProvider
+
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+			ctx = coreoptions.ToContext(ctx, coretest.Options(coretest.OptionsFields{FeatureGates: coretest.FeatureGates{ReservedCapacity: lo.ToPtr(true)}}))
+			ec2api = fake.NewEC2API()
+			iamapi = fake.NewIAMAPI()
+			permissionProvider = permission.NewDefaultProvider(ec2api, iamapi, fake.NewSTSAPI(), fake.DefaultRegion, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+		})
+		It("should not report any actions when the controller is authorized to launch nodes", func() {
+			missing, err := awscontext.CheckPermissions(ctx, permissionProvider)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(missing).To(BeEmpty())
+		})
+		It("should report the actions that the controller isn't authorized to perform", func() {
+			iamapi.DeniedActions.Insert("ec2:CreateTags", "ssm:GetParameter")
+			missing, err := awscontext.CheckPermissions(ctx, permissionProvider)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(missing).To(ConsistOf(
+				"ec2:CreateTags on launch-template/*",
+				"ec2:CreateTags on fleet/*",
+				"ec2:CreateTags on instance/*",
+				"ec2:CreateTags on volume/*",
+				"ec2:CreateTags on network-interface/*",
+				"ssm:GetParameter on parameter/aws/service/*",
+			))
+		})
+		It("should report capacity reservation permissions when dry runs aren't authorized", func() {
+			ec2api.NextError.Set(&smithy.GenericAPIError{Code: "UnauthorizedOperation"})
+			missing, err := awscontext.CheckPermissions(ctx, permissionProvider)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(missing).To(ConsistOf("ec2:DescribeCapacityReservations on *"))
+		})
+	})
 })
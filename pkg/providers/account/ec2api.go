@@ -0,0 +1,108 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package account
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
+)
+
+// EC2API makes EC2 calls in the account of the role in the context of each call, using the credentials of the
+// controller when the context isn't scoped to a role
+type EC2API struct {
+	sdk.EC2API
+	provider Provider
+}
+
+func NewEC2API(ec2api sdk.EC2API, provider Provider) *EC2API {
+	return &EC2API{
+		EC2API:   ec2api,
+		provider: provider,
+	}
+}
+
+func (e *EC2API) withCredentials(ctx context.Context, optFns []func(*ec2.Options)) []func(*ec2.Options) {
+	role := FromContext(ctx)
+	if role == nil {
+		return optFns
+	}
+	credentials := e.provider.Credentials(role)
+	return append(optFns, func(o *ec2.Options) {
+		o.Credentials = credentials
+	})
+}
+
+func (e *EC2API) DescribeCapacityReservations(ctx context.Context, input *ec2.DescribeCapacityReservationsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeCapacityReservationsOutput, error) {
+	return e.EC2API.DescribeCapacityReservations(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	return e.EC2API.DescribeImages(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) DescribeLaunchTemplates(ctx context.Context, input *ec2.DescribeLaunchTemplatesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplatesOutput, error) {
+	return e.EC2API.DescribeLaunchTemplates(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) DescribeSubnets(ctx context.Context, input *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return e.EC2API.DescribeSubnets(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) DescribeSecurityGroups(ctx context.Context, input *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return e.EC2API.DescribeSecurityGroups(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) DescribeInstanceTypes(ctx context.Context, input *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+	return e.EC2API.DescribeInstanceTypes(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) DescribeInstanceTypeOfferings(ctx context.Context, input *ec2.DescribeInstanceTypeOfferingsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypeOfferingsOutput, error) {
+	return e.EC2API.DescribeInstanceTypeOfferings(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) DescribeSpotPriceHistory(ctx context.Context, input *ec2.DescribeSpotPriceHistoryInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSpotPriceHistoryOutput, error) {
+	return e.EC2API.DescribeSpotPriceHistory(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) CreateFleet(ctx context.Context, input *ec2.CreateFleetInput, optFns ...func(*ec2.Options)) (*ec2.CreateFleetOutput, error) {
+	return e.EC2API.CreateFleet(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) TerminateInstances(ctx context.Context, input *ec2.TerminateInstancesInput, optFns ...func(*ec2.Options)) (*ec2.TerminateInstancesOutput, error) {
+	return e.EC2API.TerminateInstances(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) DescribeInstances(ctx context.Context, input *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	return e.EC2API.DescribeInstances(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) RunInstances(ctx context.Context, input *ec2.RunInstancesInput, optFns ...func(*ec2.Options)) (*ec2.RunInstancesOutput, error) {
+	return e.EC2API.RunInstances(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) CreateTags(ctx context.Context, input *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	return e.EC2API.CreateTags(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) CreateLaunchTemplate(ctx context.Context, input *ec2.CreateLaunchTemplateInput, optFns ...func(*ec2.Options)) (*ec2.CreateLaunchTemplateOutput, error) {
+	return e.EC2API.CreateLaunchTemplate(ctx, input, e.withCredentials(ctx, optFns)...)
+}
+
+func (e *EC2API) DeleteLaunchTemplate(ctx context.Context, input *ec2.DeleteLaunchTemplateInput, optFns ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateOutput, error) {
+	return e.EC2API.DeleteLaunchTemplate(ctx, input, e.withCredentials(ctx, optFns)...)
+}
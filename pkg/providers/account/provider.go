@@ -0,0 +1,124 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package account
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
+)
+
+// RoleSessionName identifies the sessions of the Karpenter controller in the CloudTrail logs of other accounts
+const RoleSessionName = "karpenter"
+
+type Provider interface {
+	Credentials(*v1.AssumeRole) aws.CredentialsProvider
+}
+
+type DefaultProvider struct {
+	sync.Mutex
+	stsapi sdk.STSAPI
+	cache  *cache.Cache
+}
+
+func NewDefaultProvider(stsapi sdk.STSAPI, cache *cache.Cache) *DefaultProvider {
+	return &DefaultProvider{
+		stsapi: stsapi,
+		cache:  cache,
+	}
+}
+
+// Credentials returns the credentials for the role. The role is only assumed when the credentials are first retrieved,
+// and the credentials are shared by every caller until they need to be refreshed.
+func (p *DefaultProvider) Credentials(role *v1.AssumeRole) aws.CredentialsProvider {
+	p.Lock()
+	defer p.Unlock()
+	key := cacheKey(role)
+	if credentials, ok := p.cache.Get(key); ok {
+		p.cache.SetDefault(key, credentials)
+		return credentials.(*aws.CredentialsCache)
+	}
+	credentials := aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(p.stsapi, role.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = RoleSessionName
+		o.ExternalID = role.ExternalID
+	}))
+	p.cache.SetDefault(key, credentials)
+	return credentials
+}
+
+func cacheKey(role *v1.AssumeRole) string {
+	return fmt.Sprintf("%s/%s", role.RoleARN, lo.FromPtr(role.ExternalID))
+}
+
+type contextKey struct{}
+
+// IntoContext scopes the AWS calls made with the returned context to the account of the role. Calls made with a nil
+// role are made in the account of the controller.
+func IntoContext(ctx context.Context, role *v1.AssumeRole) context.Context {
+	return context.WithValue(ctx, contextKey{}, role)
+}
+
+// FromContext returns the role that AWS calls made with the context are scoped to, if any
+func FromContext(ctx context.Context) *v1.AssumeRole {
+	role, _ := ctx.Value(contextKey{}).(*v1.AssumeRole)
+	return role
+}
+
+// CacheKey scopes a cache key to the account of the context, since the same query can resolve to different resources
+// in each account
+func CacheKey(ctx context.Context, key string) string {
+	if role := FromContext(ctx); role != nil {
+		return fmt.Sprintf("%s/%s", role.RoleARN, key)
+	}
+	return key
+}
+
+// Roles returns the distinct roles that are assumed across all EC2NodeClasses
+func Roles(ctx context.Context, kubeClient client.Client) ([]*v1.AssumeRole, error) {
+	nodeClassList := &v1.EC2NodeClassList{}
+	if err := kubeClient.List(ctx, nodeClassList); err != nil {
+		return nil, fmt.Errorf("listing ec2nodeclasses, %w", err)
+	}
+	roles := lo.FilterMap(nodeClassList.Items, func(nc v1.EC2NodeClass, _ int) (*v1.AssumeRole, bool) {
+		return nc.Spec.AssumeRole, nc.Spec.AssumeRole != nil
+	})
+	return lo.UniqBy(roles, cacheKey), nil
+}
+
+// Role resolves the role with the given ARN from the EC2NodeClasses which assume it, so that it's assumed with the
+// external ID that the role requires. An empty ARN resolves to no role.
+func Role(ctx context.Context, kubeClient client.Client, roleARN string) (*v1.AssumeRole, error) {
+	if roleARN == "" {
+		return nil, nil
+	}
+	roles, err := Roles(ctx, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	if role, ok := lo.Find(roles, func(r *v1.AssumeRole) bool { return r.RoleARN == roleARN }); ok {
+		return role, nil
+	}
+	// The role is no longer assumed by any EC2NodeClass, but its instances still need to be managed
+	return &v1.AssumeRole{RoleARN: roleARN}, nil
+}
@@ -0,0 +1,136 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package account_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
+	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var stsapi *fake.STSAPI
+var accountProvider *account.DefaultProvider
+
+func TestAccount(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Account")
+}
+
+var _ = BeforeEach(func() {
+	stsapi = fake.NewSTSAPI()
+	accountProvider = account.NewDefaultProvider(stsapi, cache.New(awscache.AssumeRoleCredentialsTTL, awscache.DefaultCleanupInterval))
+})
+
+// optionsEC2API records the options that each call is made with
+type optionsEC2API struct {
+	sdk.EC2API
+	options []ec2.Options
+}
+
+func (e *optionsEC2API) DescribeSubnets(_ context.Context, _ *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	options := ec2.Options{}
+	for _, fn := range optFns {
+		fn(&options)
+	}
+	e.options = append(e.options, options)
+	return &ec2.DescribeSubnetsOutput{}, nil
+}
+
+var _ = Describe("Account", func() {
+	var role *v1.AssumeRole
+
+	BeforeEach(func() {
+		role = &v1.AssumeRole{
+			RoleARN:    "arn:aws:iam::123456789012:role/KarpenterNodeLauncher",
+			ExternalID: lo.ToPtr("external-id"),
+		}
+	})
+	Context("Credentials", func() {
+		It("should assume the role with the external ID and session name", func() {
+			credentials, err := accountProvider.Credentials(role).Retrieve(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(credentials.SessionToken).To(Equal(role.RoleARN))
+
+			Expect(stsapi.AssumeRoleBehavior.CalledWithInput.Len()).To(Equal(1))
+			input := stsapi.AssumeRoleBehavior.CalledWithInput.Pop()
+			Expect(lo.FromPtr(input.RoleArn)).To(Equal(role.RoleARN))
+			Expect(lo.FromPtr(input.ExternalId)).To(Equal("external-id"))
+			Expect(lo.FromPtr(input.RoleSessionName)).To(Equal(account.RoleSessionName))
+		})
+		It("should only assume the role once for repeated calls", func() {
+			for range 3 {
+				_, err := accountProvider.Credentials(role).Retrieve(ctx)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			Expect(stsapi.AssumeRoleBehavior.Calls()).To(Equal(1))
+		})
+		It("should assume the role again for a different external ID", func() {
+			_, err := accountProvider.Credentials(role).Retrieve(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = accountProvider.Credentials(&v1.AssumeRole{RoleARN: role.RoleARN}).Retrieve(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stsapi.AssumeRoleBehavior.Calls()).To(Equal(2))
+		})
+	})
+	Context("CacheKey", func() {
+		It("should not change the key without a role", func() {
+			Expect(account.CacheKey(ctx, "key")).To(Equal("key"))
+		})
+		It("should scope the key to the role", func() {
+			Expect(account.CacheKey(account.IntoContext(ctx, role), "key")).To(Equal(role.RoleARN + "/key"))
+		})
+	})
+	Context("EC2API", func() {
+		var ec2api *optionsEC2API
+
+		BeforeEach(func() {
+			ec2api = &optionsEC2API{}
+		})
+		It("should use the credentials of the controller without a role", func() {
+			_, err := account.NewEC2API(ec2api, accountProvider).DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ec2api.options).To(HaveLen(1))
+			Expect(ec2api.options[0].Credentials).To(BeNil())
+			Expect(stsapi.AssumeRoleBehavior.Calls()).To(Equal(0))
+		})
+		It("should use the credentials of the role in the context", func() {
+			_, err := account.NewEC2API(ec2api, accountProvider).DescribeSubnets(account.IntoContext(ctx, role), &ec2.DescribeSubnetsInput{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ec2api.options).To(HaveLen(1))
+			Expect(ec2api.options[0].Credentials).ToNot(BeNil())
+
+			credentials, err := ec2api.options[0].Credentials.Retrieve(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(credentials).To(Equal(lo.Must(accountProvider.Credentials(role).Retrieve(ctx))))
+			Expect(ec2api.options[0].Credentials).To(BeAssignableToTypeOf(&aws.CredentialsCache{}))
+		})
+	})
+})
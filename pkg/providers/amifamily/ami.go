@@ -32,6 +32,7 @@ import (
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/version"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -96,10 +97,20 @@ func (p *DefaultProvider) DescribeImageQueries(ctx context.Context, nodeClass *v
 
 	idFilter := ec2types.Filter{Name: aws.String("image-id")}
 	queries := []DescribeImageQuery{}
+	// Image IDs from terms without selection constraints are batched into a single query, otherwise each ID needs a query
+	// carrying its term's constraints.
+	addImageID := func(term v1.AMISelectorTerm, id string) {
+		if query := withSelectionConstraints(DescribeImageQuery{}, term); query.MinAge != 0 || query.MaxAge != 0 || query.ExcludeDeprecated {
+			query.Filters = []ec2types.Filter{{Name: aws.String("image-id"), Values: []string{id}}}
+			queries = append(queries, query)
+			return
+		}
+		idFilter.Values = append(idFilter.Values, id)
+	}
 	for _, term := range nodeClass.Spec.AMISelectorTerms {
 		switch {
 		case term.ID != "":
-			idFilter.Values = append(idFilter.Values, term.ID)
+			addImageID(term, term.ID)
 		case term.SSMParameter != "":
 			imageID, err := p.ssmProvider.Get(ctx, ssm.Parameter{
 				Name: term.SSMParameter,
@@ -116,7 +127,7 @@ func (p *DefaultProvider) DescribeImageQueries(ctx context.Context, nodeClass *v
 				log.FromContext(ctx).WithValues("ssmParameter", term.SSMParameter, "id", imageID).V(1).Error(nil, "parameter value is an invalid AMI ID")
 				continue
 			}
-			idFilter.Values = append(idFilter.Values, imageID)
+			addImageID(term, imageID)
 		default:
 			query := DescribeImageQuery{
 				Owners: lo.Ternary(term.Owner != "", []string{term.Owner}, []string{}),
@@ -146,7 +157,7 @@ func (p *DefaultProvider) DescribeImageQueries(ctx context.Context, nodeClass *v
 					})
 				}
 			}
-			queries = append(queries, query)
+			queries = append(queries, withSelectionConstraints(query, term))
 		}
 	}
 	if len(idFilter.Values) > 0 {
@@ -155,13 +166,21 @@ func (p *DefaultProvider) DescribeImageQueries(ctx context.Context, nodeClass *v
 	return queries, nil
 }
 
+// withSelectionConstraints applies the term's age and deprecation constraints to the query
+func withSelectionConstraints(query DescribeImageQuery, term v1.AMISelectorTerm) DescribeImageQuery {
+	query.MinAge = lo.FromPtr(term.MinAge).Duration
+	query.MaxAge = lo.FromPtr(term.MaxAge).Duration
+	query.ExcludeDeprecated = lo.FromPtr(term.ExcludeDeprecated)
+	return query
+}
+
 //nolint:gocyclo
 func (p *DefaultProvider) amis(ctx context.Context, queries []DescribeImageQuery) (AMIs, error) {
 	hash, err := hashstructure.Hash(queries, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	if err != nil {
 		return nil, err
 	}
-	if images, ok := p.cache.Get(fmt.Sprintf("%d", hash)); ok {
+	if images, ok := p.cache.Get(account.CacheKey(ctx, fmt.Sprintf("%d", hash))); ok {
 		// Ensure what's returned from this function is a deep-copy of AMIs so alterations
 		// to the data don't affect the original
 		return append(AMIs{}, images.(AMIs)...), nil
@@ -195,6 +214,9 @@ func (p *DefaultProvider) amis(ctx context.Context, queries []DescribeImageQuery
 						Deprecated:   candidateDeprecated,
 						Requirements: reqs,
 					}
+					if !query.Selectable(ami, p.clk.Now()) {
+						continue
+					}
 					if v, ok := images[reqsHash]; ok {
 						if cmpResult := compareAMI(v, ami); cmpResult <= 0 {
 							continue
@@ -205,7 +227,7 @@ func (p *DefaultProvider) amis(ctx context.Context, queries []DescribeImageQuery
 			}
 		}
 	}
-	p.cache.SetDefault(fmt.Sprintf("%d", hash), AMIs(lo.Values(images)))
+	p.cache.SetDefault(account.CacheKey(ctx, fmt.Sprintf("%d", hash)), AMIs(lo.Values(images)))
 	return lo.Values(images), nil
 }
 
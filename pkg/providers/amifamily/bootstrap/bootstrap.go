@@ -38,6 +38,7 @@ type Options struct {
 	ContainerRuntime    *string
 	CustomUserData      *string
 	InstanceStorePolicy *v1.InstanceStorePolicy
+	Registries          *v1.RegistryConfiguration
 }
 
 func (o Options) kubeletExtraArgs() (args []string) {
@@ -71,6 +72,38 @@ func (o Options) kubeletExtraArgs() (args []string) {
 	if o.KubeletConfig.CPUCFSQuota != nil {
 		args = append(args, fmt.Sprintf("--cpu-cfs-quota=%t", lo.FromPtr(o.KubeletConfig.CPUCFSQuota)))
 	}
+	if o.KubeletConfig.CPUManagerPolicy != nil {
+		args = append(args, fmt.Sprintf("--cpu-manager-policy=%s", lo.FromPtr(o.KubeletConfig.CPUManagerPolicy)))
+	}
+	if o.KubeletConfig.CPUManagerReconcilePeriod != nil {
+		args = append(args, fmt.Sprintf("--cpu-manager-reconcile-period=%s", o.KubeletConfig.CPUManagerReconcilePeriod.Duration.String()))
+	}
+	if o.KubeletConfig.TopologyManagerPolicy != nil {
+		args = append(args, fmt.Sprintf("--topology-manager-policy=%s", lo.FromPtr(o.KubeletConfig.TopologyManagerPolicy)))
+	}
+	if o.KubeletConfig.TopologyManagerScope != nil {
+		args = append(args, fmt.Sprintf("--topology-manager-scope=%s", lo.FromPtr(o.KubeletConfig.TopologyManagerScope)))
+	}
+	if o.KubeletConfig.ContainerLogMaxSize != nil {
+		args = append(args, fmt.Sprintf("--container-log-max-size=%s", lo.FromPtr(o.KubeletConfig.ContainerLogMaxSize)))
+	}
+	if o.KubeletConfig.ContainerLogMaxFiles != nil {
+		args = append(args, fmt.Sprintf("--container-log-max-files=%d", lo.FromPtr(o.KubeletConfig.ContainerLogMaxFiles)))
+	}
+	if o.KubeletConfig.RegistryPullQPS != nil {
+		args = append(args, fmt.Sprintf("--registry-qps=%d", lo.FromPtr(o.KubeletConfig.RegistryPullQPS)))
+	}
+	if o.KubeletConfig.RegistryBurst != nil {
+		args = append(args, fmt.Sprintf("--registry-burst=%d", lo.FromPtr(o.KubeletConfig.RegistryBurst)))
+	}
+	if o.KubeletConfig.SerializeImagePulls != nil {
+		args = append(args, fmt.Sprintf("--serialize-image-pulls=%t", lo.FromPtr(o.KubeletConfig.SerializeImagePulls)))
+	}
+	if len(o.KubeletConfig.AllowedUnsafeSysctls) > 0 {
+		args = append(args, fmt.Sprintf("--allowed-unsafe-sysctls=%s", strings.Join(o.KubeletConfig.AllowedUnsafeSysctls, ",")))
+	}
+	// ShutdownGracePeriod, ShutdownGracePeriodCriticalPods, and MaxParallelImagePulls are only configurable through
+	// the kubelet config file and are rejected by validation for the AMI families that configure kubelet through flags
 	return lo.Compact(args)
 }
 
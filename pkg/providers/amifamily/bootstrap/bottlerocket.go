@@ -29,6 +29,7 @@ import (
 
 type Bottlerocket struct {
 	Options
+	Settings *v1.BottlerocketSettings
 }
 
 // nolint:gocyclo
@@ -37,6 +38,12 @@ func (b Bottlerocket) Script() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("invalid UserData %w", err)
 	}
+	if err := s.MergeNodeClassSettings(b.Settings); err != nil {
+		return "", fmt.Errorf("merging bottlerocket settings %w", err)
+	}
+	if err := s.MergeNodeClassSettings(b.registrySettings()); err != nil {
+		return "", fmt.Errorf("merging registry settings %w", err)
+	}
 	// Karpenter will overwrite settings present inside custom UserData
 	// based on other fields specified in the NodePool
 	s.Settings.Kubernetes.ClusterName = &b.ClusterName
@@ -84,6 +91,53 @@ func (b Bottlerocket) Script() (string, error) {
 		if b.KubeletConfig.CPUCFSQuota != nil {
 			s.Settings.Kubernetes.CPUCFSQuota = b.KubeletConfig.CPUCFSQuota
 		}
+		if b.KubeletConfig.CPUManagerPolicy != nil {
+			s.Settings.Kubernetes.CPUManagerPolicy = b.KubeletConfig.CPUManagerPolicy
+		}
+		if b.KubeletConfig.CPUManagerReconcilePeriod != nil {
+			s.Settings.Kubernetes.CPUManagerReconcilePeriod = lo.ToPtr(b.KubeletConfig.CPUManagerReconcilePeriod.Duration.String())
+		}
+		if b.KubeletConfig.TopologyManagerPolicy != nil {
+			s.Settings.Kubernetes.TopologyManagerPolicy = b.KubeletConfig.TopologyManagerPolicy
+		}
+		if b.KubeletConfig.TopologyManagerScope != nil {
+			s.Settings.Kubernetes.TopologyManagerScope = b.KubeletConfig.TopologyManagerScope
+		}
+		if b.KubeletConfig.ShutdownGracePeriod != nil {
+			s.Settings.Kubernetes.ShutdownGracePeriod = lo.ToPtr(b.KubeletConfig.ShutdownGracePeriod.Duration.String())
+		}
+		if b.KubeletConfig.ShutdownGracePeriodCriticalPods != nil {
+			s.Settings.Kubernetes.ShutdownGracePeriodForCriticalPods = lo.ToPtr(b.KubeletConfig.ShutdownGracePeriodCriticalPods.Duration.String())
+		}
+		if b.KubeletConfig.ContainerLogMaxSize != nil {
+			s.Settings.Kubernetes.ContainerLogMaxSize = b.KubeletConfig.ContainerLogMaxSize
+		}
+		if b.KubeletConfig.ContainerLogMaxFiles != nil {
+			s.Settings.Kubernetes.ContainerLogMaxFiles = aws.Int(int(lo.FromPtr(b.KubeletConfig.ContainerLogMaxFiles)))
+		}
+		if b.KubeletConfig.RegistryPullQPS != nil {
+			s.Settings.Kubernetes.RegistryQPS = aws.Int(int(lo.FromPtr(b.KubeletConfig.RegistryPullQPS)))
+		}
+		if b.KubeletConfig.RegistryBurst != nil {
+			s.Settings.Kubernetes.RegistryBurst = aws.Int(int(lo.FromPtr(b.KubeletConfig.RegistryBurst)))
+		}
+		if len(b.KubeletConfig.AllowedUnsafeSysctls) > 0 {
+			s.Settings.Kubernetes.AllowedUnsafeSysctls = b.KubeletConfig.AllowedUnsafeSysctls
+		}
+	}
+
+	if providers := b.credentialProviders(); len(providers) > 0 {
+		if s.Settings.Kubernetes.CredentialProviders == nil {
+			s.Settings.Kubernetes.CredentialProviders = map[string]BottlerocketCredentialProvider{}
+		}
+		for _, p := range providers {
+			s.Settings.Kubernetes.CredentialProviders[p.Name] = BottlerocketCredentialProvider{
+				Enabled:       lo.ToPtr(true),
+				CacheDuration: lo.ToPtr(credentialProviderCacheDuration(p).String()),
+				ImagePatterns: p.MatchImages,
+				Environment:   p.Env,
+			}
+		}
 	}
 
 	s.Settings.Kubernetes.NodeTaints = map[string][]string{}
@@ -107,3 +161,18 @@ func (b Bottlerocket) Script() (string, error) {
 	}
 	return base64.StdEncoding.EncodeToString(script), nil
 }
+
+// registrySettings returns the registry mirrors of the EC2NodeClass as Bottlerocket container registry settings
+func (b Bottlerocket) registrySettings() *v1.BottlerocketSettings {
+	mirrors := b.registryMirrors()
+	if len(mirrors) == 0 {
+		return nil
+	}
+	return &v1.BottlerocketSettings{
+		ContainerRegistry: &v1.BottlerocketContainerRegistry{
+			Mirrors: lo.Map(mirrors, func(m v1.RegistryMirror, _ int) v1.BottlerocketRegistryMirror {
+				return v1.BottlerocketRegistryMirror{Registry: m.Registry, Endpoints: m.Endpoints}
+			}),
+		},
+	}
+}
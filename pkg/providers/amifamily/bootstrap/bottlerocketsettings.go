@@ -16,6 +16,9 @@ package bootstrap
 
 import (
 	"github.com/pelletier/go-toml/v2"
+	"github.com/samber/lo"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 )
 
 func NewBottlerocketConfig(userdata *string) (*BottlerocketConfig, error) {
@@ -115,6 +118,119 @@ type BootstrapCommand struct {
 	Essential bool                 `toml:"essential"`
 }
 
+// BottlerocketKernel is kernel configuration for bottlerocket api
+type BottlerocketKernel struct {
+	Sysctl map[string]string `toml:"sysctl,omitempty"`
+}
+
+// BottlerocketContainerRegistry is container image pull configuration for bottlerocket api
+type BottlerocketContainerRegistry struct {
+	Mirrors []BottlerocketRegistryMirror `toml:"mirrors,omitempty"`
+}
+
+type BottlerocketRegistryMirror struct {
+	Registry string   `toml:"registry"`
+	Endpoint []string `toml:"endpoint"`
+}
+
+type BottlerocketBootstrapContainer struct {
+	Source    string  `toml:"source"`
+	Mode      string  `toml:"mode"`
+	UserData  *string `toml:"user-data,omitempty"`
+	Essential *bool   `toml:"essential,omitempty"`
+}
+
+type BottlerocketHostContainer struct {
+	Source       *string `toml:"source,omitempty"`
+	Enabled      *bool   `toml:"enabled,omitempty"`
+	Superpowered *bool   `toml:"superpowered,omitempty"`
+	UserData     *string `toml:"user-data,omitempty"`
+}
+
+type BottlerocketNTP struct {
+	TimeServers []string `toml:"time-servers"`
+}
+
+// bottlerocketNodeClassSettings are the settings configured by the typed bottlerocket block of an EC2NodeClass
+type bottlerocketNodeClassSettings struct {
+	Kernel              *BottlerocketKernel                       `toml:"kernel,omitempty"`
+	ContainerRegistry   *BottlerocketContainerRegistry            `toml:"container-registry,omitempty"`
+	BootstrapContainers map[string]BottlerocketBootstrapContainer `toml:"bootstrap-containers,omitempty"`
+	HostContainers      map[string]BottlerocketHostContainer      `toml:"host-containers,omitempty"`
+	NTP                 *BottlerocketNTP                          `toml:"ntp,omitempty"`
+}
+
+func newBottlerocketNodeClassSettings(settings *v1.BottlerocketSettings) bottlerocketNodeClassSettings {
+	s := bottlerocketNodeClassSettings{}
+	if settings.Kernel != nil && len(settings.Kernel.Sysctl) != 0 {
+		s.Kernel = &BottlerocketKernel{Sysctl: settings.Kernel.Sysctl}
+	}
+	if settings.ContainerRegistry != nil && len(settings.ContainerRegistry.Mirrors) != 0 {
+		s.ContainerRegistry = &BottlerocketContainerRegistry{
+			Mirrors: lo.Map(settings.ContainerRegistry.Mirrors, func(m v1.BottlerocketRegistryMirror, _ int) BottlerocketRegistryMirror {
+				return BottlerocketRegistryMirror{Registry: m.Registry, Endpoint: m.Endpoints}
+			}),
+		}
+	}
+	if len(settings.BootstrapContainers) != 0 {
+		s.BootstrapContainers = lo.MapValues(settings.BootstrapContainers, func(c v1.BottlerocketBootstrapContainer, _ string) BottlerocketBootstrapContainer {
+			return BottlerocketBootstrapContainer{
+				Source:    c.Source,
+				Mode:      lo.FromPtrOr(c.Mode, string(BootstrapCommandModeAlways)),
+				UserData:  c.UserData,
+				Essential: c.Essential,
+			}
+		})
+	}
+	if len(settings.HostContainers) != 0 {
+		s.HostContainers = lo.MapValues(settings.HostContainers, func(c v1.BottlerocketHostContainer, _ string) BottlerocketHostContainer {
+			return BottlerocketHostContainer{
+				Source:       c.Source,
+				Enabled:      c.Enabled,
+				Superpowered: c.Superpowered,
+				UserData:     c.UserData,
+			}
+		})
+	}
+	if settings.NTP != nil {
+		s.NTP = &BottlerocketNTP{TimeServers: settings.NTP.TimeServers}
+	}
+	return s
+}
+
+// MergeNodeClassSettings merges the typed settings of an EC2NodeClass into the settings parsed from UserData.
+// Tables are merged key by key, while all other values (including arrays) are replaced by the EC2NodeClass settings.
+func (c *BottlerocketConfig) MergeNodeClassSettings(settings *v1.BottlerocketSettings) error {
+	if settings == nil {
+		return nil
+	}
+	data, err := toml.Marshal(newBottlerocketNodeClassSettings(settings))
+	if err != nil {
+		return err
+	}
+	raw := map[string]interface{}{}
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if c.SettingsRaw == nil {
+		c.SettingsRaw = map[string]interface{}{}
+	}
+	mergeTables(c.SettingsRaw, raw)
+	return nil
+}
+
+func mergeTables(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcTable, srcIsTable := v.(map[string]interface{})
+		dstTable, dstIsTable := dst[k].(map[string]interface{})
+		if srcIsTable && dstIsTable {
+			mergeTables(dstTable, srcTable)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
 func (c *BottlerocketConfig) UnmarshalTOML(data []byte) error {
 	// unmarshal known settings
 	s := struct {
@@ -37,6 +37,13 @@ type EKS struct {
 	ContainerRuntime string
 }
 
+// localDisksStrategies maps instance store policies to the strategies supported by bootstrap.sh's --local-disks flag
+var localDisksStrategies = map[v1.InstanceStorePolicy]string{
+	v1.InstanceStorePolicyRAID0:  "raid0",
+	v1.InstanceStorePolicyRAID10: "raid10",
+	v1.InstanceStorePolicyMount:  "mount",
+}
+
 const (
 	Boundary                      = "//"
 	MIMEVersionHeader             = "MIME-Version: 1.0"
@@ -50,7 +57,11 @@ func (e EKS) Script() (string, error) {
 	}
 	// The mime/multipart package adds carriage returns, while the rest of our logic does not. Remove all
 	// carriage returns for consistency.
-	return base64.StdEncoding.EncodeToString([]byte(strings.ReplaceAll(userData, "\r", ""))), nil
+	userData = strings.ReplaceAll(userData, "\r", "")
+	if len(userData) > compressionThreshold {
+		return serializeMIME(userData)
+	}
+	return base64.StdEncoding.EncodeToString([]byte(userData)), nil
 }
 
 //nolint:gocyclo
@@ -62,6 +73,7 @@ func (e EKS) eksBootstrapScript() string {
 	var userData bytes.Buffer
 	userData.WriteString("#!/bin/bash -xe\n")
 	userData.WriteString("exec > >(tee /var/log/user-data.log|logger -t user-data -s 2>/dev/console) 2>&1\n")
+	userData.WriteString(e.registryScript())
 	// Due to the way bootstrap.sh is written, parameters should not be passed to it with an equal sign
 	userData.WriteString(fmt.Sprintf("/etc/eks/bootstrap.sh '%s' --apiserver-endpoint '%s' %s", e.ClusterName, e.ClusterEndpoint, caBundleArg))
 
@@ -74,11 +86,11 @@ func (e EKS) eksBootstrapScript() string {
 	if e.KubeletConfig != nil && e.KubeletConfig.MaxPods != nil {
 		userData.WriteString(" \\\n--use-max-pods false")
 	}
-	if args := e.kubeletExtraArgs(); len(args) > 0 {
+	if args := lo.Compact(append(e.kubeletExtraArgs(), e.credentialProviderConfigArg())); len(args) > 0 {
 		userData.WriteString(fmt.Sprintf(" \\\n--kubelet-extra-args '%s'", strings.Join(args, " ")))
 	}
-	if lo.FromPtr(e.InstanceStorePolicy) == v1.InstanceStorePolicyRAID0 {
-		userData.WriteString(" \\\n--local-disks raid0")
+	if localDisks, ok := localDisksStrategies[lo.FromPtr(e.InstanceStorePolicy)]; ok {
+		userData.WriteString(fmt.Sprintf(" \\\n--local-disks %s", localDisks))
 	}
 	return userData.String()
 }
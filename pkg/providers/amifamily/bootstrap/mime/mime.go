@@ -16,6 +16,7 @@ package mime
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -38,11 +39,20 @@ const (
 	ContentTypeShellScript ContentType = `text/x-shellscript; charset="us-ascii"`
 	ContentTypeNodeConfig  ContentType = "application/" + admapi.GroupName
 	ContentTypeMultipart   ContentType = `multipart/mixed; boundary="` + boundary + `"`
+	// ContentTypeGzip marks a gzip compressed part, which cloud-init decompresses before detecting the part's type
+	// from its content
+	ContentTypeGzip ContentType = "application/x-gzip"
+
+	transferEncodingBase64 = "base64"
+	// base64LineLength is the maximum encoded line length for base64 content transfer encoding (RFC 2045)
+	base64LineLength = 76
 )
 
 type Entry struct {
 	ContentType ContentType
-	Content     string
+	// ContentTransferEncoding is set when Content has already been encoded, e.g. for compressed entries
+	ContentTransferEncoding string
+	Content                 string
 }
 
 type Archive []Entry
@@ -69,8 +79,9 @@ func NewArchive(content string) (Archive, error) {
 			return nil, fmt.Errorf("parsing content, %s, %w", string(slurp), err)
 		}
 		archive = append(archive, Entry{
-			ContentType: ContentType(p.Header.Get("Content-Type")),
-			Content:     string(slurp),
+			ContentType:             ContentType(p.Header.Get("Content-Type")),
+			ContentTransferEncoding: p.Header.Get("Content-Transfer-Encoding"),
+			Content:                 string(slurp),
 		})
 	}
 	return archive, nil
@@ -86,9 +97,13 @@ func (ma Archive) Serialize() (string, error) {
 	buffer.WriteString(versionHeader + "\n")
 	buffer.WriteString(fmt.Sprintf("Content-Type: %s\n\n", ContentTypeMultipart))
 	for _, entry := range ma {
-		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+		header := textproto.MIMEHeader{
 			"Content-Type": []string{string(entry.ContentType)},
-		})
+		}
+		if entry.ContentTransferEncoding != "" {
+			header.Set("Content-Transfer-Encoding", entry.ContentTransferEncoding)
+		}
+		partWriter, err := writer.CreatePart(header)
 		if err != nil {
 			return "", fmt.Errorf("creating multi-part section for entry, %w", err)
 		}
@@ -105,6 +120,47 @@ func (ma Archive) Serialize() (string, error) {
 	return base64.StdEncoding.EncodeToString([]byte(strings.ReplaceAll(buffer.String(), "\r", ""))), nil
 }
 
+// Compress returns a copy of the archive with each entry gzip compressed. NodeConfig entries are left uncompressed
+// so that they're always readable by nodeadm, and they're typically small relative to the other entries. Entries
+// which are already encoded are also left as-is.
+func (ma Archive) Compress() (Archive, error) {
+	compressed := make(Archive, 0, len(ma))
+	for _, entry := range ma {
+		if entry.ContentType == ContentTypeNodeConfig || entry.ContentTransferEncoding != "" {
+			compressed = append(compressed, entry)
+			continue
+		}
+		var buffer bytes.Buffer
+		gzipWriter, err := gzip.NewWriterLevel(&buffer, gzip.BestCompression)
+		if err != nil {
+			return nil, fmt.Errorf("creating gzip writer, %w", err)
+		}
+		if _, err = gzipWriter.Write([]byte(entry.Content)); err != nil {
+			return nil, fmt.Errorf("compressing entry, %w", err)
+		}
+		if err = gzipWriter.Close(); err != nil {
+			return nil, fmt.Errorf("compressing entry, %w", err)
+		}
+		compressed = append(compressed, Entry{
+			ContentType:             ContentTypeGzip,
+			ContentTransferEncoding: transferEncodingBase64,
+			Content:                 wrapLines(base64.StdEncoding.EncodeToString(buffer.Bytes()), base64LineLength),
+		})
+	}
+	return compressed, nil
+}
+
+// wrapLines splits s into newline terminated lines of at most n characters
+func wrapLines(s string, n int) string {
+	var lines strings.Builder
+	for len(s) > n {
+		lines.WriteString(s[:n] + "\n")
+		s = s[n:]
+	}
+	lines.WriteString(s + "\n")
+	return lines.String()
+}
+
 func (Archive) getReader(content string) (*multipart.Reader, error) {
 	mailMsg, err := mail.ReadMessage(strings.NewReader(content))
 	if err != nil {
@@ -15,10 +15,14 @@ limitations under the License.
 package mime_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -95,4 +99,40 @@ var _ = Describe("MIME Parser", func() {
 		Expect(err).To(BeNil())
 		Expect(string(serialized)).To(Equal(string(expected)))
 	})
+	It("should compress entries other than NodeConfigs", func() {
+		nodeConfig, err := os.ReadFile("test_data/nodeconfig.txt")
+		Expect(err).To(BeNil())
+		shell, err := os.ReadFile("test_data/shell.txt")
+		Expect(err).To(BeNil())
+		archive, err := mime.Archive{
+			{ContentType: mime.ContentTypeNodeConfig, Content: string(nodeConfig)},
+			{ContentType: mime.ContentTypeShellScript, Content: string(shell)},
+		}.Compress()
+		Expect(err).To(BeNil())
+		Expect(archive).To(HaveLen(2))
+		Expect(archive[0]).To(Equal(mime.Entry{ContentType: mime.ContentTypeNodeConfig, Content: string(nodeConfig)}))
+		Expect(archive[1].ContentType).To(Equal(mime.ContentTypeGzip))
+		Expect(archive[1].ContentTransferEncoding).To(Equal("base64"))
+
+		compressed, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(archive[1].Content, "\n", ""))
+		Expect(err).To(BeNil())
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		Expect(err).To(BeNil())
+		decompressed, err := io.ReadAll(reader)
+		Expect(err).To(BeNil())
+		Expect(string(decompressed)).To(Equal(string(shell)))
+	})
+	It("should preserve the content transfer encoding of entries when serializing and parsing", func() {
+		encoded, err := mime.Archive{
+			{ContentType: mime.ContentTypeGzip, ContentTransferEncoding: "base64", Content: "H4sIAAAAAAAA/wAEAPv/Zm9vCgMAqGUyfgQAAAA=\n"},
+		}.Serialize()
+		Expect(err).To(BeNil())
+		serialized, err := base64.StdEncoding.DecodeString(encoded)
+		Expect(err).To(BeNil())
+		Expect(string(serialized)).To(ContainSubstring("Content-Transfer-Encoding: base64"))
+		archive, err := mime.NewArchive(string(serialized))
+		Expect(err).To(BeNil())
+		Expect(archive).To(HaveLen(1))
+		Expect(archive[0].ContentTransferEncoding).To(Equal("base64"))
+	})
 })
@@ -36,6 +36,14 @@ type Nodeadm struct {
 	Options
 }
 
+// localStorageStrategies maps instance store policies to nodeadm local storage strategies. RAID10 is supported by
+// nodeadm, but isn't included in the version of the NodeConfig API that we depend on.
+var localStorageStrategies = map[v1.InstanceStorePolicy]admv1alpha1.LocalStorageStrategy{
+	v1.InstanceStorePolicyRAID0:  admv1alpha1.LocalStorageRAID0,
+	v1.InstanceStorePolicyRAID10: admv1alpha1.LocalStorageStrategy("RAID10"),
+	v1.InstanceStorePolicyMount:  admv1alpha1.LocalStorageMount,
+}
+
 func (n Nodeadm) Script() (string, error) {
 	nodeConfigYAML, err := n.getNodeConfigYAML()
 	if err != nil {
@@ -45,11 +53,17 @@ func (n Nodeadm) Script() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("parsing custom UserData, %w", err)
 	}
+	if script := n.registryScript(); script != "" {
+		customEntries = append(customEntries, mime.Entry{
+			ContentType: mime.ContentTypeShellScript,
+			Content:     "#!/bin/bash -xe\n" + script,
+		})
+	}
 	mimeArchive := mime.Archive(append(customEntries, mime.Entry{
 		ContentType: mime.ContentTypeNodeConfig,
 		Content:     nodeConfigYAML,
 	}))
-	userData, err := mimeArchive.Serialize()
+	userData, err := serializeArchive(mimeArchive)
 	if err != nil {
 		return "", err
 	}
@@ -83,16 +97,16 @@ func (n Nodeadm) getNodeConfigYAML() (string, error) {
 	} else {
 		return "", cloudprovider.NewNodeClassNotReadyError(fmt.Errorf("resolving cluster CIDR"))
 	}
-	if lo.FromPtr(n.InstanceStorePolicy) == v1.InstanceStorePolicyRAID0 {
-		config.Spec.Instance.LocalStorage.Strategy = admv1alpha1.LocalStorageRAID0
+	if strategy, ok := localStorageStrategies[lo.FromPtr(n.InstanceStorePolicy)]; ok {
+		config.Spec.Instance.LocalStorage.Strategy = strategy
 	}
 	inlineConfig, err := n.generateInlineKubeletConfiguration()
 	if err != nil {
 		return "", err
 	}
 	config.Spec.Kubelet.Config = inlineConfig
-	if arg := n.nodeLabelArg(); arg != "" {
-		config.Spec.Kubelet.Flags = []string{arg}
+	if flags := lo.Compact([]string{n.nodeLabelArg(), n.credentialProviderConfigArg()}); len(flags) > 0 {
+		config.Spec.Kubelet.Flags = flags
 	}
 
 	// Convert to YAML at the end for improved legibility.
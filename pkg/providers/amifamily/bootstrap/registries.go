@@ -0,0 +1,181 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+)
+
+const (
+	// containerdCertsDir is the containerd registry host configuration directory. The EKS optimized AMIs set
+	// containerd's config_path to this directory, which rules out configuring mirrors in the containerd config itself.
+	containerdCertsDir        = "/etc/containerd/certs.d"
+	windowsContainerdCertsDir = `$env:ProgramFiles\containerd\certs.d`
+	// CredentialProviderConfigPath is where Karpenter writes the kubelet image credential provider config. Plugin
+	// binaries are still resolved from the AMI's default credential provider bin dir.
+	CredentialProviderConfigPath = "/etc/karpenter/image-credential-provider/config.json"
+	ecrCredentialProviderName    = "ecr-credential-provider"
+)
+
+// ecrCredentialProvider matches the ECR credential provider configured by the EKS optimized AMIs, so that ECR
+// pulls continue to work when the credential provider config is replaced.
+var ecrCredentialProvider = v1.CredentialProvider{
+	Name: ecrCredentialProviderName,
+	MatchImages: []string{
+		"*.dkr.ecr.*.amazonaws.com",
+		"*.dkr.ecr.*.amazonaws.com.cn",
+		"*.dkr.ecr-fips.*.amazonaws.com",
+		"*.dkr.ecr.*.c2s.ic.gov",
+		"*.dkr.ecr.*.sc2s.sgov.gov",
+	},
+	DefaultCacheDuration: &metav1.Duration{Duration: 12 * time.Hour},
+}
+
+func (o Options) registryMirrors() []v1.RegistryMirror {
+	if o.Registries == nil {
+		return nil
+	}
+	return o.Registries.Mirrors
+}
+
+func (o Options) credentialProviders() []v1.CredentialProvider {
+	if o.Registries == nil {
+		return nil
+	}
+	return o.Registries.CredentialProviders
+}
+
+// credentialProviderConfigArg returns the kubelet flag pointing at the Karpenter generated credential provider config.
+// Flags passed by Karpenter are appended after the AMI's defaults, so this takes precedence.
+func (o Options) credentialProviderConfigArg() string {
+	if len(o.credentialProviders()) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("--image-credential-provider-config=%s", CredentialProviderConfigPath)
+}
+
+// registryScript returns the shell commands which write containerd registry host configuration for each mirror and the
+// kubelet credential provider config. These must run before the kubelet starts.
+func (o Options) registryScript() string {
+	var script bytes.Buffer
+	for _, mirror := range o.registryMirrors() {
+		dir := fmt.Sprintf("%s/%s", containerdCertsDir, mirrorHostDir(mirror.Registry))
+		script.WriteString(fmt.Sprintf("mkdir -p '%s'\n", dir))
+		script.WriteString(fmt.Sprintf("cat > '%s/hosts.toml' <<'EOF'\n%sEOF\n", dir, hostsTOML(mirror)))
+	}
+	if len(o.credentialProviders()) > 0 {
+		script.WriteString(fmt.Sprintf("mkdir -p '%s'\n", path.Dir(CredentialProviderConfigPath)))
+		script.WriteString(fmt.Sprintf("cat > '%s' <<'EOF'\n%s\nEOF\n", CredentialProviderConfigPath, o.credentialProviderConfig()))
+	}
+	return script.String()
+}
+
+// windowsRegistryScript returns the PowerShell commands which write containerd registry host configuration for each mirror
+func (o Options) windowsRegistryScript() string {
+	var script bytes.Buffer
+	for _, mirror := range o.registryMirrors() {
+		dir := fmt.Sprintf(`%s\%s`, windowsContainerdCertsDir, mirrorHostDir(mirror.Registry))
+		script.WriteString(fmt.Sprintf("New-Item -ItemType Directory -Force -Path \"%s\" | Out-Null\n", dir))
+		script.WriteString(fmt.Sprintf("Set-Content -Path \"%s\\hosts.toml\" -Value @'\n%s'@\n", dir, hostsTOML(mirror)))
+	}
+	return script.String()
+}
+
+// mirrorHostDir returns the containerd host directory name for a registry, where "_default" applies to all registries
+func mirrorHostDir(registry string) string {
+	if registry == "*" {
+		return "_default"
+	}
+	return registry
+}
+
+// hostsTOML renders a containerd hosts.toml for a mirror. Hosts are tried in order, falling back to the registry itself.
+// https://github.com/containerd/containerd/blob/main/docs/hosts.md
+func hostsTOML(mirror v1.RegistryMirror) string {
+	var hosts bytes.Buffer
+	for _, endpoint := range mirror.Endpoints {
+		hosts.WriteString(fmt.Sprintf("[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n", endpoint))
+	}
+	return hosts.String()
+}
+
+type credentialProviderConfig struct {
+	APIVersion string                     `json:"apiVersion"`
+	Kind       string                     `json:"kind"`
+	Providers  []credentialProviderPlugin `json:"providers"`
+}
+
+type credentialProviderPlugin struct {
+	Name                 string                          `json:"name"`
+	MatchImages          []string                        `json:"matchImages"`
+	DefaultCacheDuration string                          `json:"defaultCacheDuration"`
+	APIVersion           string                          `json:"apiVersion"`
+	Env                  []credentialProviderEnvVariable `json:"env,omitempty"`
+}
+
+type credentialProviderEnvVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// credentialProviderConfig returns the kubelet CredentialProviderConfig for the configured providers, including the
+// ECR credential provider unless it has been overridden.
+// https://kubernetes.io/docs/reference/config-api/kubelet-credentialprovider.v1/
+func (o Options) credentialProviderConfig() string {
+	providers := o.credentialProviders()
+	if _, ok := lo.Find(providers, func(p v1.CredentialProvider) bool { return p.Name == ecrCredentialProviderName }); !ok {
+		providers = append([]v1.CredentialProvider{ecrCredentialProvider}, providers...)
+	}
+	return string(lo.Must(json.MarshalIndent(credentialProviderConfig{
+		APIVersion: "kubelet.config.k8s.io/v1",
+		Kind:       "CredentialProviderConfig",
+		Providers: lo.Map(providers, func(p v1.CredentialProvider, _ int) credentialProviderPlugin {
+			return credentialProviderPlugin{
+				Name:                 p.Name,
+				MatchImages:          p.MatchImages,
+				DefaultCacheDuration: credentialProviderCacheDuration(p).String(),
+				APIVersion:           "credentialprovider.kubelet.k8s.io/v1",
+				Env:                  credentialProviderEnv(p.Env),
+			}
+		}),
+	}, "", "  ")))
+}
+
+// credentialProviderEnv returns the environment variables of a provider, sorted by name so that UserData is deterministic
+func credentialProviderEnv(env map[string]string) []credentialProviderEnvVariable {
+	keys := lo.Keys(env)
+	sort.Strings(keys)
+	return lo.Map(keys, func(k string, _ int) credentialProviderEnvVariable {
+		return credentialProviderEnvVariable{Name: k, Value: env[k]}
+	})
+}
+
+// credentialProviderCacheDuration defaults the cache duration of a provider to that of the ECR credential provider
+func credentialProviderCacheDuration(p v1.CredentialProvider) time.Duration {
+	if p.DefaultCacheDuration != nil {
+		return p.DefaultCacheDuration.Duration
+	}
+	return ecrCredentialProvider.DefaultCacheDuration.Duration
+}
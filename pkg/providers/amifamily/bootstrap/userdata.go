@@ -0,0 +1,92 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily/bootstrap/mime"
+)
+
+const (
+	// MaxUserDataSize is the maximum size of EC2 user data, before it's base64 encoded
+	// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/user-data.html
+	MaxUserDataSize = 16 * 1024
+	// compressionThreshold is the size above which MIME user data parts are gzip compressed. Uncompressed user data is
+	// preferred below this size since it's readable when inspecting a launch template or instance.
+	compressionThreshold = 12 * 1024
+)
+
+// UserDataTooLargeError is returned when user data exceeds MaxUserDataSize, even after compression
+type UserDataTooLargeError struct {
+	Size int
+}
+
+func (e *UserDataTooLargeError) Error() string {
+	return fmt.Sprintf("user data is %d bytes, exceeding the EC2 limit of %d bytes", e.Size, MaxUserDataSize)
+}
+
+func IsUserDataTooLarge(err error) bool {
+	if err == nil {
+		return false
+	}
+	var tooLargeErr *UserDataTooLargeError
+	return errors.As(err, &tooLargeErr)
+}
+
+// ValidateUserDataSize returns a UserDataTooLargeError if the base64 encoded user data returned by a Bootstrapper
+// exceeds MaxUserDataSize once decoded
+func ValidateUserDataSize(userData string) error {
+	if size := decodedSize(userData); size > MaxUserDataSize {
+		return &UserDataTooLargeError{Size: size}
+	}
+	return nil
+}
+
+// serializeArchive serializes a MIME archive, compressing its entries if the archive is close to MaxUserDataSize
+func serializeArchive(archive mime.Archive) (string, error) {
+	userData, err := archive.Serialize()
+	if err != nil {
+		return "", err
+	}
+	if decodedSize(userData) <= compressionThreshold {
+		return userData, nil
+	}
+	compressed, err := archive.Compress()
+	if err != nil {
+		return "", fmt.Errorf("compressing user data, %w", err)
+	}
+	return compressed.Serialize()
+}
+
+// serializeMIME parses and re-serializes MIME user data so that it's compressed if necessary. Headers other than each
+// part's Content-Type are not preserved.
+func serializeMIME(userData string) (string, error) {
+	archive, err := mime.NewArchive(userData)
+	if err != nil {
+		return "", fmt.Errorf("parsing user data, %w", err)
+	}
+	return serializeArchive(archive)
+}
+
+func decodedSize(userData string) int {
+	decoded, err := base64.StdEncoding.DecodeString(userData)
+	if err != nil {
+		return len(userData)
+	}
+	return len(decoded)
+}
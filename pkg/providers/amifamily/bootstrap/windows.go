@@ -36,6 +36,7 @@ func (w Windows) Script() (string, error) {
 	if customUserData != "" {
 		userData.WriteString(customUserData + "\n")
 	}
+	userData.WriteString(w.windowsRegistryScript())
 
 	userData.WriteString("[string]$EKSBootstrapScriptFile = \"$env:ProgramFiles\\Amazon\\EKS\\Start-EKSBootstrap.ps1\"\n")
 	userData.WriteString(fmt.Sprintf(`& $EKSBootstrapScriptFile -EKSClusterName '%s' -APIServerEndpoint '%s'`, w.ClusterName, w.ClusterEndpoint))
@@ -87,7 +87,9 @@ func (b Bottlerocket) UserData(kubeletConfig *v1.KubeletConfiguration, taints []
 			CABundle:            caBundle,
 			CustomUserData:      customUserData,
 			InstanceStorePolicy: instanceStorePolicy,
+			Registries:          b.Options.Registries,
 		},
+		Settings: b.Options.Bottlerocket,
 	}
 }
 
@@ -17,7 +17,10 @@ package amifamily
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"net"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -57,6 +60,8 @@ type Options struct {
 	InstanceProfile     string
 	CABundle            *string `hash:"ignore"`
 	InstanceStorePolicy *v1.InstanceStorePolicy
+	Bottlerocket        *v1.BottlerocketSettings
+	Registries          *v1.RegistryConfiguration
 	// Level-triggered fields that may change out of sync.
 	SecurityGroups           []v1.SecurityGroup
 	Tags                     map[string]string
@@ -75,6 +80,7 @@ type LaunchTemplate struct {
 	AMIID                 string
 	InstanceTypes         []*cloudprovider.InstanceType `hash:"ignore"`
 	DetailedMonitoring    bool
+	CPUOptions            *v1.CPUOptions
 	EFACount              int
 	CapacityType          string
 	CapacityReservationID string
@@ -124,12 +130,13 @@ func NewDefaultResolver() *DefaultResolver {
 // Multiple ResolvedTemplates are returned based on the instanceTypes passed in to support special AMIs for certain instance types like GPUs.
 func (r DefaultResolver) Resolve(nodeClass *v1.EC2NodeClass, nodeClaim *karpv1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, capacityType string, options *Options) ([]*LaunchTemplate, error) {
 	amiFamily := GetAMIFamily(nodeClass.AMIFamily(), options)
-	if len(nodeClass.Status.AMIs) == 0 {
+	amis := launchAMIs(nodeClass, nodeClaim)
+	if len(amis) == 0 {
 		return nil, fmt.Errorf("no amis exist given constraints")
 	}
-	mappedAMIs := MapToInstanceTypes(instanceTypes, nodeClass.Status.AMIs)
+	mappedAMIs := MapToInstanceTypes(instanceTypes, amis)
 	if len(mappedAMIs) == 0 {
-		return nil, fmt.Errorf("no instance types satisfy requirements of amis %v", lo.Uniq(lo.Map(nodeClass.Status.AMIs, func(a v1.AMI, _ int) string { return a.ID })))
+		return nil, fmt.Errorf("no instance types satisfy requirements of amis %v", lo.Uniq(lo.Map(amis, func(a v1.AMI, _ int) string { return a.ID })))
 	}
 	var resolvedTemplates []*LaunchTemplate
 	for amiID, instanceTypes := range mappedAMIs {
@@ -141,9 +148,17 @@ func (r DefaultResolver) Resolve(nodeClass *v1.EC2NodeClass, nodeClaim *karpv1.N
 		// Reservations IDs are also included since we need to create a separate LaunchTemplate per reservation ID when
 		// launching reserved capacity. If it's a reserved capacity launch, we've already filtered the instance types
 		// further up the call stack.
+		// When CPU options are configured, the core count and threads per core of each instance type are required
+		// since EC2 expects both to be set on the launch template. Similarly, when the root volume's size is scaled with
+		// the instance type, a unique launch template is required per root volume size, and instance types which match
+		// different kubelet instance type overrides require unique launch templates for their kubelet configuration.
 		type launchTemplateParams struct {
-			efaCount int
-			maxPods  int
+			efaCount        int
+			maxPods         int
+			coreCount       int32
+			threadsPerCore  int32
+			rootVolumeSize  string
+			kubeletOverride int
 			// reservationIDs is encoded as a string rather than a slice to ensure this type is comparable for use by `lo.GroupBy`.
 			reservationIDs string
 		}
@@ -154,7 +169,11 @@ func (r DefaultResolver) Resolve(nodeClass *v1.EC2NodeClass, nodeClaim *karpv1.N
 					int(lo.ToPtr(it.Capacity[v1.ResourceEFA]).Value()),
 					0,
 				),
-				maxPods: int(it.Capacity.Pods().Value()),
+				maxPods:         int(it.Capacity.Pods().Value()),
+				coreCount:       lo.Ternary(configuresCores(nodeClass.Spec.CPUOptions), coreCount(it), 0),
+				threadsPerCore:  lo.Ternary(configuresCores(nodeClass.Spec.CPUOptions), threadsPerCore(it), 0),
+				rootVolumeSize:  rootVolumeSize(nodeClass.Spec.BlockDeviceMappings, it),
+				kubeletOverride: kubeletOverride(nodeClass.Spec.Kubelet, it),
 				// If we're dealing with reserved instances, there's only going to be a single instance per group. This invariant
 				// is due to reservation IDs not being shared across instance types. Because of this, we don't need to worry about
 				// ordering in this string.
@@ -170,12 +189,146 @@ func (r DefaultResolver) Resolve(nodeClass *v1.EC2NodeClass, nodeClaim *karpv1.N
 
 		for params, instanceTypes := range paramsToInstanceTypes {
 			reservationIDs := strings.Split(params.reservationIDs, ",")
-			resolvedTemplates = append(resolvedTemplates, r.resolveLaunchTemplates(nodeClass, nodeClaim, instanceTypes, capacityType, amiFamily, amiID, params.maxPods, params.efaCount, reservationIDs, options)...)
+			launchTemplates, err := r.resolveLaunchTemplates(nodeClass, nodeClaim, instanceTypes, capacityType, amiFamily, amiID, params.maxPods, params.efaCount, reservationIDs, cpuOptions(nodeClass.Spec.CPUOptions, params.coreCount, params.threadsPerCore), params.rootVolumeSize, options)
+			if err != nil {
+				return nil, err
+			}
+			resolvedTemplates = append(resolvedTemplates, launchTemplates...)
 		}
 	}
 	return resolvedTemplates, nil
 }
 
+func configuresCores(cpuOptions *v1.CPUOptions) bool {
+	return cpuOptions != nil && (cpuOptions.CoreCount != nil || cpuOptions.ThreadsPerCore != nil)
+}
+
+func threadsPerCore(it *cloudprovider.InstanceType) int32 {
+	threads, err := strconv.ParseInt(it.Requirements.Get(v1.LabelInstanceThreadsPerCore).Any(), 10, 32)
+	if err != nil || threads == 0 {
+		return 1
+	}
+	return int32(threads)
+}
+
+func coreCount(it *cloudprovider.InstanceType) int32 {
+	// nolint:gosec
+	// The vCPU count of an instance type is always within the bounds of an int32
+	return int32(it.Capacity.Cpu().Value()) / threadsPerCore(it)
+}
+
+// cpuOptions resolves the CPU options for a launch template, filling in the per instance type core count and threads
+// per core when the EC2NodeClass configures either of them.
+func cpuOptions(cpuOptions *v1.CPUOptions, coreCount, threadsPerCore int32) *v1.CPUOptions {
+	if cpuOptions == nil {
+		return nil
+	}
+	resolved := cpuOptions.DeepCopy()
+	if configuresCores(cpuOptions) {
+		resolved.CoreCount = lo.ToPtr(coreCount)
+		resolved.ThreadsPerCore = lo.ToPtr(threadsPerCore)
+	}
+	return resolved
+}
+
+// ResolveVolumeSize returns the size of an EBS volume attached to an instance type with the given vCPUs and memory.
+// Volumes without a size policy use their configured size.
+func ResolveVolumeSize(ebs *v1.BlockDevice, vcpus int64, memoryMiB int64) *resource.Quantity {
+	if ebs == nil {
+		return nil
+	}
+	if ebs.VolumeSizePolicy == nil {
+		return ebs.VolumeSize
+	}
+	policy := ebs.VolumeSizePolicy
+	size := lo.FromPtr(ebs.VolumeSize).Value()
+	if policy.PerVCPU != nil {
+		size += policy.PerVCPU.Value() * vcpus
+	}
+	if policy.PerGiBMemory != nil {
+		size += policy.PerGiBMemory.Value() * memoryMiB / 1024
+	}
+	if policy.Min != nil {
+		size = max(size, policy.Min.Value())
+	}
+	if policy.Max != nil {
+		size = min(size, policy.Max.Value())
+	}
+	// EBS volumes are sized in whole Gi, and must be at least 1Gi
+	gi := max((size+(1<<30)-1)>>30, 1)
+	return resource.NewQuantity(gi<<30, resource.BinarySI)
+}
+
+// rootVolumeSize returns the size of the root volume for an instance type, or an empty string if the root volume's
+// size doesn't depend on the instance type
+func rootVolumeSize(blockDeviceMappings []*v1.BlockDeviceMapping, it *cloudprovider.InstanceType) string {
+	bdm, ok := lo.Find(blockDeviceMappings, func(bdm *v1.BlockDeviceMapping) bool {
+		return bdm.RootVolume && bdm.EBS != nil && bdm.EBS.VolumeSizePolicy != nil
+	})
+	if !ok {
+		return ""
+	}
+	// The vCPUs and memory are read from the instance type's labels, since these are what its advertised ephemeral
+	// storage is computed from.
+	vcpus, _ := strconv.ParseInt(it.Requirements.Get(v1.LabelInstanceCPU).Any(), 10, 64)
+	memoryMiB, _ := strconv.ParseInt(it.Requirements.Get(v1.LabelInstanceMemory).Any(), 10, 64)
+	return ResolveVolumeSize(bdm.EBS, vcpus, memoryMiB).String()
+}
+
+// blockDeviceMappings returns the block device mappings for a launch template, setting the root volume's size when
+// it's resolved for the launch template's instance types
+func blockDeviceMappings(blockDeviceMappings []*v1.BlockDeviceMapping, rootVolumeSize string) []*v1.BlockDeviceMapping {
+	if rootVolumeSize == "" {
+		return blockDeviceMappings
+	}
+	return lo.Map(blockDeviceMappings, func(bdm *v1.BlockDeviceMapping, _ int) *v1.BlockDeviceMapping {
+		if !bdm.RootVolume || bdm.EBS == nil {
+			return bdm
+		}
+		resolved := bdm.DeepCopy()
+		resolved.EBS.VolumeSize = lo.ToPtr(resource.MustParse(rootVolumeSize))
+		resolved.EBS.VolumeSizePolicy = nil
+		return resolved
+	})
+}
+
+// instanceTypeShape returns the family, size, vCPUs, and memory (in MiB) that kubelet instance type overrides are
+// matched against
+func instanceTypeShape(it *cloudprovider.InstanceType) (string, string, int64, int64) {
+	vcpus, _ := strconv.ParseInt(it.Requirements.Get(v1.LabelInstanceCPU).Any(), 10, 64)
+	memoryMiB, _ := strconv.ParseInt(it.Requirements.Get(v1.LabelInstanceMemory).Any(), 10, 64)
+	return it.Requirements.Get(v1.LabelInstanceFamily).Any(), it.Requirements.Get(v1.LabelInstanceSize).Any(), vcpus, memoryMiB
+}
+
+// kubeletOverride returns the index of the kubelet instance type override which matches an instance type, or -1 if
+// none match
+func kubeletOverride(kubelet *v1.KubeletConfiguration, it *cloudprovider.InstanceType) int {
+	if kubelet == nil {
+		return -1
+	}
+	family, size, vcpus, memoryMiB := instanceTypeShape(it)
+	_, i, _ := lo.FindIndexOf(kubelet.InstanceTypeOverrides, func(o v1.KubeletInstanceTypeOverride) bool {
+		return o.Matches(family, size, vcpus, memoryMiB)
+	})
+	return i
+}
+
+// launchAMIs returns the AMIs to launch with. While candidate AMIs are soaking, they're used for the configured
+// percentage of launches and the stable AMIs are used for the remainder. NodeClaims are bucketed by name so that
+// retried launches for a NodeClaim always select the same AMIs.
+func launchAMIs(nodeClass *v1.EC2NodeClass, nodeClaim *karpv1.NodeClaim) []v1.AMI {
+	rollout := nodeClass.Status.AMIRollout
+	if nodeClass.Spec.AMIRolloutPolicy == nil || rollout == nil || len(rollout.CandidateAMIs) == 0 {
+		return nodeClass.Status.AMIs
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(nodeClaim.Name))
+	if int32(h.Sum32()%100) < lo.FromPtrOr(nodeClass.Spec.AMIRolloutPolicy.CanaryPercentage, 100) {
+		return rollout.CandidateAMIs
+	}
+	return nodeClass.Status.AMIs
+}
+
 func GetAMIFamily(amiFamily string, options *Options) AMIFamily {
 	switch amiFamily {
 	case v1.AMIFamilyBottlerocket:
@@ -184,10 +337,14 @@ func GetAMIFamily(amiFamily string, options *Options) AMIFamily {
 		return &Windows{Options: options, Version: v1.Windows2019, Build: v1.Windows2019Build}
 	case v1.AMIFamilyWindows2022:
 		return &Windows{Options: options, Version: v1.Windows2022, Build: v1.Windows2022Build}
+	case v1.AMIFamilyWindows2025:
+		return &Windows{Options: options, Version: v1.Windows2025, Build: v1.Windows2025Build}
 	case v1.AMIFamilyCustom:
 		return &Custom{Options: options}
 	case v1.AMIFamilyAL2023:
 		return &AL2023{Options: options}
+	case v1.AMIFamilyUbuntu:
+		return &Ubuntu{Options: options}
 	default:
 		return &AL2{Options: options}
 	}
@@ -229,11 +386,15 @@ func (r DefaultResolver) resolveLaunchTemplates(
 	maxPods int,
 	efaCount int,
 	capacityReservationIDs []string,
+	cpuOptions *v1.CPUOptions,
+	rootVolumeSize string,
 	options *Options,
-) []*LaunchTemplate {
+) ([]*LaunchTemplate, error) {
 	kubeletConfig := &v1.KubeletConfiguration{}
 	if nodeClass.Spec.Kubelet != nil {
-		kubeletConfig = nodeClass.Spec.Kubelet.DeepCopy()
+		// Instance types are grouped by the kubelet instance type override they match, so resolving the configuration
+		// for any of them yields the same result
+		kubeletConfig = nodeClass.Spec.Kubelet.ForInstanceType(instanceTypeShape(instanceTypes[0]))
 	}
 	if kubeletConfig.MaxPods == nil {
 		// nolint:gosec
@@ -258,6 +419,10 @@ func (r DefaultResolver) resolveLaunchTemplates(
 	if len(capacityReservationIDs) == 0 {
 		capacityReservationIDs = append(capacityReservationIDs, "")
 	}
+	userData, err := r.resolveUserData(nodeClass, instanceTypes, capacityType, amiID, options)
+	if err != nil {
+		return nil, err
+	}
 	return lo.Map(capacityReservationIDs, func(id string, _ int) *LaunchTemplate {
 		resolved := &LaunchTemplate{
 			Options: options,
@@ -267,12 +432,13 @@ func (r DefaultResolver) resolveLaunchTemplates(
 				options.Labels,
 				options.CABundle,
 				instanceTypes,
-				nodeClass.Spec.UserData,
+				userData,
 				options.InstanceStorePolicy,
 			),
-			BlockDeviceMappings:   nodeClass.Spec.BlockDeviceMappings,
+			BlockDeviceMappings:   blockDeviceMappings(nodeClass.Spec.BlockDeviceMappings, rootVolumeSize),
 			MetadataOptions:       nodeClass.Spec.MetadataOptions,
 			DetailedMonitoring:    aws.ToBool(nodeClass.Spec.DetailedMonitoring),
+			CPUOptions:            cpuOptions,
 			AMIID:                 amiID,
 			InstanceTypes:         instanceTypes,
 			EFACount:              efaCount,
@@ -286,5 +452,31 @@ func (r DefaultResolver) resolveLaunchTemplates(
 			resolved.MetadataOptions = amiFamily.DefaultMetadataOptions()
 		}
 		return resolved
+	}), nil
+}
+
+// resolveUserData returns the EC2NodeClass's userData, rendered as a template if templateUserData is enabled. The
+// template data is taken from the launch template's options and the instance types that it has already been resolved
+// for, so that rendering doesn't split launch templates any further than their options already do.
+func (r DefaultResolver) resolveUserData(nodeClass *v1.EC2NodeClass, instanceTypes []*cloudprovider.InstanceType, capacityType string, amiID string, options *Options) (*string, error) {
+	if nodeClass.Spec.UserData == nil || !lo.FromPtr(nodeClass.Spec.TemplateUserData) {
+		return nodeClass.Spec.UserData, nil
+	}
+	instanceTypeNames := lo.Map(instanceTypes, func(it *cloudprovider.InstanceType, _ int) string { return it.Name })
+	sort.Strings(instanceTypeNames)
+	userData, err := RenderUserData(*nodeClass.Spec.UserData, UserDataTemplateData{
+		ClusterName:   options.ClusterName,
+		NodeClassName: options.NodeClassName,
+		NodePoolName:  options.Labels[karpv1.NodePoolLabelKey],
+		CapacityType:  capacityType,
+		// Labels include single-value requirements, so the zone is only set when the NodeClaim is constrained to one
+		Zone:          options.Labels[corev1.LabelTopologyZone],
+		InstanceTypes: instanceTypeNames,
+		AMIID:         amiID,
+		Labels:        options.Labels,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &userData, nil
 }
@@ -34,6 +34,8 @@ import (
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	karpv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
@@ -171,6 +173,30 @@ var _ = Describe("AMIProvider", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(amis).To(HaveLen(5))
 	})
+	DescribeTable("should succeed to resolve AMIs (Ubuntu)", func(alias, release, serial string) {
+		nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: alias}}
+		awsEnv.SSMAPI.Parameters = map[string]string{
+			fmt.Sprintf("/aws/service/canonical/ubuntu/eks/%s/%s/stable/%s/amd64/hvm/ebs-gp2/ami-id", release, version, serial): amd64AMI,
+			fmt.Sprintf("/aws/service/canonical/ubuntu/eks/%s/%s/stable/%s/arm64/hvm/ebs-gp2/ami-id", release, version, serial): arm64AMI,
+		}
+		amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(amis).To(HaveLen(2))
+	},
+		Entry("latest", "ubuntu@latest", amifamily.DefaultUbuntuRelease, "current"),
+		Entry("pinned serial", "ubuntu@20240701", amifamily.DefaultUbuntuRelease, "20240701"),
+		Entry("release", "ubuntu@24.04", "24.04", "current"),
+		Entry("release and pinned serial", "ubuntu@24.04-20240701", "24.04", "20240701"),
+	)
+	It("should fail to resolve AMIs for an Ubuntu release that isn't published (Ubuntu)", func() {
+		nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@24.04"}}
+		awsEnv.SSMAPI.Parameters = map[string]string{
+			fmt.Sprintf("/aws/service/canonical/ubuntu/eks/22.04/%s/stable/current/amd64/hvm/ebs-gp2/ami-id", version): amd64AMI,
+			fmt.Sprintf("/aws/service/canonical/ubuntu/eks/22.04/%s/stable/current/arm64/hvm/ebs-gp2/ami-id", version): arm64AMI,
+		}
+		_, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+		Expect(err).To(HaveOccurred())
+	})
 	It("should succeed to resolve AMIs (Windows2019)", func() {
 		nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "windows2019@latest"}}
 		awsEnv.SSMAPI.Parameters = map[string]string{
@@ -189,6 +215,43 @@ var _ = Describe("AMIProvider", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(amis).To(HaveLen(1))
 	})
+	It("should succeed to resolve AMIs (Windows2025)", func() {
+		nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "windows2025@latest"}}
+		awsEnv.SSMAPI.Parameters = map[string]string{
+			fmt.Sprintf("/aws/service/ami-windows-latest/Windows_Server-2025-English-Core-EKS_Optimized-%s/image_id", version): amd64AMI,
+		}
+		amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(amis).To(HaveLen(1))
+	})
+	It("should succeed to resolve pinned AMIs by name (Windows2022)", func() {
+		pinnedAMI := fmt.Sprintf("Windows_Server-2022-English-Core-EKS_Optimized-%s-2024.09.10", version)
+		awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+			Images: []ec2types.Image{
+				{
+					Name:         aws.String(pinnedAMI),
+					ImageId:      aws.String("ami-id-windows-pinned"),
+					CreationDate: aws.String(time.Time{}.Format(time.RFC3339)),
+					Architecture: "x86_64",
+					State:        ec2types.ImageStateAvailable,
+				},
+				{
+					Name:         aws.String(fmt.Sprintf("Windows_Server-2022-English-Core-EKS_Optimized-%s-2024.10.08", version)),
+					ImageId:      aws.String("ami-id-windows-newer"),
+					CreationDate: aws.String(time.Time{}.Add(time.Minute).Format(time.RFC3339)),
+					Architecture: "x86_64",
+					State:        ec2types.ImageStateAvailable,
+				},
+			},
+		})
+		nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "windows2022@2024.09.10"}}
+		amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(amis).To(HaveLen(1))
+		Expect(amis[0].AmiID).To(Equal("ami-id-windows-pinned"))
+		Expect(amis[0].Requirements.Get(corev1.LabelOSStable).Has(string(corev1.Windows))).To(BeTrue())
+		Expect(amis[0].Requirements.Get(corev1.LabelWindowsBuild).Has(v1.Windows2022Build)).To(BeTrue())
+	})
 
 	It("should not cause data races when calling Get() simultaneously", func() {
 		nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{
@@ -307,6 +370,16 @@ var _ = Describe("AMIProvider", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(amis).To(HaveLen(4))
 		})
+		It("should succeed to partially resolve AMIs if all SSM aliases don't exist (Ubuntu)", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@latest"}}
+			// No ARM64 AMI exists here
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				fmt.Sprintf("/aws/service/canonical/ubuntu/eks/22.04/%s/stable/current/amd64/hvm/ebs-gp2/ami-id", version): amd64AMI,
+			}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+		})
 	})
 	Context("AMI Tag Requirements", func() {
 		var img ec2types.Image
@@ -533,6 +606,131 @@ var _ = Describe("AMIProvider", func() {
 			}))
 		})
 	})
+	Context("AMI Selection Constraints", func() {
+		var now time.Time
+		BeforeEach(func() {
+			now = time.Date(2024, 9, 10, 0, 0, 0, 0, time.UTC)
+			awsEnv.Clock.SetTime(now)
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{
+				{
+					Tags: map[string]string{"*": "*"},
+				},
+			}
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+				Images: []ec2types.Image{
+					{
+						Name:         aws.String(amd64AMI),
+						ImageId:      aws.String("ami-new"),
+						CreationDate: aws.String(now.Add(-24 * time.Hour).Format(time.RFC3339)),
+						Architecture: "x86_64",
+						State:        ec2types.ImageStateAvailable,
+					},
+					{
+						Name:         aws.String(amd64AMI),
+						ImageId:      aws.String("ami-baked"),
+						CreationDate: aws.String(now.Add(-10 * 24 * time.Hour).Format(time.RFC3339)),
+						Architecture: "x86_64",
+						State:        ec2types.ImageStateAvailable,
+					},
+					{
+						Name:            aws.String(amd64AMI),
+						ImageId:         aws.String("ami-old"),
+						CreationDate:    aws.String(now.Add(-100 * 24 * time.Hour).Format(time.RFC3339)),
+						DeprecationTime: aws.String(now.Add(-1 * time.Hour).Format(time.RFC3339)),
+						Architecture:    "x86_64",
+						State:           ec2types.ImageStateAvailable,
+					},
+				},
+			})
+		})
+		It("should select the newest AMI when no constraints are set", func() {
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-new"))
+		})
+		It("should ignore AMIs younger than minAge", func() {
+			nodeClass.Spec.AMISelectorTerms[0].MinAge = &metav1.Duration{Duration: 7 * 24 * time.Hour}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-baked"))
+		})
+		It("should select an AMI once it has aged past minAge", func() {
+			nodeClass.Spec.AMISelectorTerms[0].MinAge = &metav1.Duration{Duration: 7 * 24 * time.Hour}
+			awsEnv.Clock.SetTime(now.Add(7 * 24 * time.Hour))
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-new"))
+		})
+		It("should ignore AMIs older than maxAge", func() {
+			nodeClass.Spec.AMISelectorTerms[0].MinAge = &metav1.Duration{Duration: 2 * 24 * time.Hour}
+			nodeClass.Spec.AMISelectorTerms[0].MaxAge = &metav1.Duration{Duration: 5 * 24 * time.Hour}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(BeEmpty())
+
+			nodeClass.Spec.AMISelectorTerms[0].MaxAge = &metav1.Duration{Duration: 12 * 24 * time.Hour}
+			amis, err = awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-baked"))
+		})
+		It("should fall back to deprecated AMIs unless they're excluded", func() {
+			nodeClass.Spec.AMISelectorTerms[0].MinAge = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("ami-old"))
+			Expect(amis[0].Deprecated).To(BeTrue())
+
+			nodeClass.Spec.AMISelectorTerms[0].ExcludeDeprecated = lo.ToPtr(true)
+			amis, err = awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(BeEmpty())
+		})
+		It("should query constrained image IDs separately from unconstrained image IDs", func() {
+			queries, err := awsEnv.AMIProvider.DescribeImageQueries(ctx, &v1.EC2NodeClass{
+				Spec: v1.EC2NodeClassSpec{
+					AMISelectorTerms: []v1.AMISelectorTerm{
+						{
+							ID: "ami-abcd1234",
+						},
+						{
+							ID: "ami-cafeaced",
+						},
+						{
+							ID:                "ami-deadbeef",
+							MinAge:            &metav1.Duration{Duration: time.Hour},
+							ExcludeDeprecated: lo.ToPtr(true),
+						},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			ExpectConsistsOfAMIQueries([]amifamily.DescribeImageQuery{
+				{
+					Filters: []ec2types.Filter{
+						{
+							Name:   lo.ToPtr("image-id"),
+							Values: []string{"ami-abcd1234", "ami-cafeaced"},
+						},
+					},
+				},
+				{
+					Filters: []ec2types.Filter{
+						{
+							Name:   lo.ToPtr("image-id"),
+							Values: []string{"ami-deadbeef"},
+						},
+					},
+					MinAge:            time.Hour,
+					ExcludeDeprecated: true,
+				},
+			}, queries)
+		})
+	})
 	Context("AMI Selectors", func() {
 		// When you tag public or shared resources, the tags you assign are available only to your AWS account; no other AWS account will have access to those tags
 		// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/Using_Tags.html#tag-restrictions
@@ -898,6 +1096,36 @@ var _ = Describe("AMIProvider", func() {
 	})
 })
 
+var _ = Describe("VolumeSizePolicy", func() {
+	DescribeTable(
+		"should resolve the volume size for an instance type",
+		func(ebs *v1.BlockDevice, vcpus int64, memoryMiB int64, expected string) {
+			Expect(amifamily.ResolveVolumeSize(ebs, vcpus, memoryMiB).String()).To(Equal(expected))
+		},
+		Entry("without a size policy", &v1.BlockDevice{VolumeSize: lo.ToPtr(resource.MustParse("20Gi"))}, int64(4), int64(16384), "20Gi"),
+		Entry("per vCPU", &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{PerVCPU: lo.ToPtr(resource.MustParse("5Gi"))}}, int64(4), int64(16384), "20Gi"),
+		Entry("per GiB of memory", &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{PerGiBMemory: lo.ToPtr(resource.MustParse("2Gi"))}}, int64(4), int64(16384), "32Gi"),
+		Entry("per vCPU and GiB of memory", &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{
+			PerVCPU:      lo.ToPtr(resource.MustParse("5Gi")),
+			PerGiBMemory: lo.ToPtr(resource.MustParse("1Gi")),
+		}}, int64(4), int64(16384), "36Gi"),
+		Entry("with a base volume size", &v1.BlockDevice{
+			VolumeSize:       lo.ToPtr(resource.MustParse("20Gi")),
+			VolumeSizePolicy: &v1.VolumeSizePolicy{PerVCPU: lo.ToPtr(resource.MustParse("5Gi"))},
+		}, int64(4), int64(16384), "40Gi"),
+		Entry("below the minimum", &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{
+			PerVCPU: lo.ToPtr(resource.MustParse("5Gi")),
+			Min:     lo.ToPtr(resource.MustParse("50Gi")),
+		}}, int64(4), int64(16384), "50Gi"),
+		Entry("above the maximum", &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{
+			PerVCPU: lo.ToPtr(resource.MustParse("5Gi")),
+			Max:     lo.ToPtr(resource.MustParse("100Gi")),
+		}}, int64(192), int64(786432), "100Gi"),
+		Entry("rounded up to the nearest Gi", &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{PerVCPU: lo.ToPtr(resource.MustParse("500Mi"))}}, int64(3), int64(8192), "2Gi"),
+		Entry("in decimal units", &v1.BlockDevice{VolumeSizePolicy: &v1.VolumeSizePolicy{PerVCPU: lo.ToPtr(resource.MustParse("10G"))}}, int64(2), int64(8192), "19Gi"),
+	)
+})
+
 func ExpectConsistsOfAMIQueries(expected, actual []amifamily.DescribeImageQuery) {
 	GinkgoHelper()
 	Expect(actual).To(HaveLen(len(expected)))
@@ -105,6 +105,14 @@ type DescribeImageQuery struct {
 	// Sometimes, an image may have multiple sets of known requirements. For example, the AL2 GPU AMI is compatible with both Neuron and Nvidia GPU
 	// instances, which means we need a set of requirements for either instance type.
 	KnownRequirements map[string][]scheduling.Requirements
+	// Requirements are the requirements known for any image returned by the query which isn't in KnownRequirements.
+	// This is used when images are discovered by name rather than by ID (e.g. pinned Windows AMIs).
+	Requirements []scheduling.Requirements
+	// MinAge and MaxAge bound the time since an image's creation date for it to be selected. Zero values are unbounded.
+	MinAge time.Duration
+	MaxAge time.Duration
+	// ExcludeDeprecated prevents images past their deprecation time from being selected, even when no other image matches.
+	ExcludeDeprecated bool
 }
 
 func (q DescribeImageQuery) DescribeImagesInput() *ec2.DescribeImagesInput {
@@ -119,6 +127,25 @@ func (q DescribeImageQuery) DescribeImagesInput() *ec2.DescribeImagesInput {
 	}
 }
 
+// Selectable returns true if the AMI satisfies the query's age and deprecation constraints at the given time.
+// AMIs without a creation date are treated as infinitely old.
+func (q DescribeImageQuery) Selectable(ami AMI, now time.Time) bool {
+	if q.ExcludeDeprecated && ami.Deprecated {
+		return false
+	}
+	if q.MinAge == 0 && q.MaxAge == 0 {
+		return true
+	}
+	age := now.Sub(parseTimeWithDefault(ami.CreationDate, minTime))
+	if q.MinAge != 0 && age < q.MinAge {
+		return false
+	}
+	if q.MaxAge != 0 && age > q.MaxAge {
+		return false
+	}
+	return true
+}
+
 func (q DescribeImageQuery) RequirementsForImageWithArchitecture(image string, arch string) []scheduling.Requirements {
 	if knownRequirements, ok := q.KnownRequirements[image]; ok {
 		return lo.Map(knownRequirements, func(r scheduling.Requirements, _ int) scheduling.Requirements {
@@ -126,5 +153,12 @@ func (q DescribeImageQuery) RequirementsForImageWithArchitecture(image string, a
 			return r
 		})
 	}
+	if len(q.Requirements) != 0 {
+		return lo.Map(q.Requirements, func(r scheduling.Requirements, _ int) scheduling.Requirements {
+			reqs := scheduling.NewRequirements(r.Values()...)
+			reqs.Add(scheduling.NewRequirement(corev1.LabelArchStable, corev1.NodeSelectorOpIn, arch))
+			return reqs
+		})
+	}
 	return []scheduling.Requirements{scheduling.NewRequirements(scheduling.NewRequirement(corev1.LabelArchStable, corev1.NodeSelectorOpIn, arch))}
 }
@@ -0,0 +1,125 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package amifamily
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/awslabs/operatorpkg/serrors"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily/bootstrap"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/ssm"
+)
+
+// DefaultUbuntuRelease is the Ubuntu release of the Canonical EKS AMIs that are discovered when an Ubuntu alias doesn't
+// specify a release
+const DefaultUbuntuRelease = "22.04"
+
+var ubuntuReleasePattern = regexp.MustCompile(`^[0-9]{2}[.][0-9]{2}$`)
+
+type Ubuntu struct {
+	DefaultFamily
+	*Options
+}
+
+// DescribeImageQuery discovers Canonical's EKS AMIs through their public SSM parameters
+func (u Ubuntu) DescribeImageQuery(ctx context.Context, ssmProvider ssm.Provider, k8sVersion string, amiVersion string) (DescribeImageQuery, error) {
+	release, serial := ubuntuReleaseAndSerial(amiVersion)
+	ids := map[string][]Variant{}
+	for path, variants := range map[string][]Variant{
+		fmt.Sprintf("/aws/service/canonical/ubuntu/eks/%s/%s/stable/%s/amd64/hvm/ebs-gp2/ami-id", release, k8sVersion, serial): {VariantStandard},
+		fmt.Sprintf("/aws/service/canonical/ubuntu/eks/%s/%s/stable/%s/arm64/hvm/ebs-gp2/ami-id", release, k8sVersion, serial): {VariantStandard},
+	} {
+		imageID, err := ssmProvider.Get(ctx, ssm.Parameter{
+			Name:      path,
+			IsMutable: serial == "current",
+		})
+		if err != nil {
+			continue
+		}
+		ids[imageID] = variants
+	}
+	// Failed to discover any AMIs, we should short circuit AMI discovery
+	if len(ids) == 0 {
+		return DescribeImageQuery{}, serrors.Wrap(fmt.Errorf("failed to discover any AMIs for alias"), "alias", fmt.Sprintf("ubuntu@%s", amiVersion))
+	}
+
+	return DescribeImageQuery{
+		Filters: []ec2types.Filter{{
+			Name:   lo.ToPtr("image-id"),
+			Values: lo.Keys(ids),
+		}},
+		KnownRequirements: lo.MapValues(ids, func(variants []Variant, _ string) []scheduling.Requirements {
+			return lo.Map(variants, func(v Variant, _ int) scheduling.Requirements { return v.Requirements() })
+		}),
+	}, nil
+}
+
+// ubuntuReleaseAndSerial returns the Ubuntu release and Canonical image serial of an Ubuntu alias version. The version
+// may be "latest", a release (ex: "24.04"), a release and serial (ex: "24.04-20240701"), or a serial of the default
+// release (ex: "20240701"). Canonical publishes the latest image of each release under the "current" serial.
+func ubuntuReleaseAndSerial(amiVersion string) (string, string) {
+	if amiVersion == v1.AliasVersionLatest {
+		return DefaultUbuntuRelease, "current"
+	}
+	if ubuntuReleasePattern.MatchString(amiVersion) {
+		return amiVersion, "current"
+	}
+	if release, serial, ok := strings.Cut(amiVersion, "-"); ok {
+		return release, serial
+	}
+	return DefaultUbuntuRelease, amiVersion
+}
+
+// UserData returns the default userdata script for the AMI Family. Canonical's EKS AMIs ship the same
+// bootstrap.sh entrypoint as the EKS optimized AL2 AMIs.
+func (u Ubuntu) UserData(kubeletConfig *v1.KubeletConfiguration, taints []corev1.Taint, labels map[string]string, caBundle *string, _ []*cloudprovider.InstanceType, customUserData *string, instanceStorePolicy *v1.InstanceStorePolicy) bootstrap.Bootstrapper {
+	return bootstrap.EKS{
+		Options: bootstrap.Options{
+			ClusterName:         u.Options.ClusterName,
+			ClusterEndpoint:     u.Options.ClusterEndpoint,
+			KubeletConfig:       kubeletConfig,
+			Taints:              taints,
+			Labels:              labels,
+			CABundle:            caBundle,
+			CustomUserData:      customUserData,
+			InstanceStorePolicy: instanceStorePolicy,
+			Registries:          u.Options.Registries,
+		},
+	}
+}
+
+// DefaultBlockDeviceMappings returns the default block device mappings for the AMI Family
+func (u Ubuntu) DefaultBlockDeviceMappings() []*v1.BlockDeviceMapping {
+	return []*v1.BlockDeviceMapping{{
+		DeviceName: u.EphemeralBlockDevice(),
+		EBS:        &DefaultEBS,
+	}}
+}
+
+func (u Ubuntu) EphemeralBlockDevice() *string {
+	return aws.String("/dev/sda1")
+}
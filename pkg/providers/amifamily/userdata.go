@@ -0,0 +1,80 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package amifamily
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// UserDataTemplateData is the context available to an EC2NodeClass's userData when templateUserData is enabled. It's
+// limited to values which are shared by every instance type in a launch template, since rendering a value which
+// differs between instance types would split launches across more launch templates.
+type UserDataTemplateData struct {
+	ClusterName   string
+	NodeClassName string
+	NodePoolName  string
+	CapacityType  string
+	// Zone is only set when the NodeClaim is constrained to a single zone
+	Zone string
+	// InstanceTypes is the sorted list of instance types that the launch template may launch
+	InstanceTypes []string
+	AMIID         string
+	Labels        map[string]string
+}
+
+// userDataTemplateFuncs is the set of functions available to user data templates. It's intentionally limited to pure
+// string functions, so that rendering can't reach outside of the data that it's passed.
+var userDataTemplateFuncs = template.FuncMap{
+	"join":       func(sep string, elems []string) string { return strings.Join(elems, sep) },
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, replacement, s string) string { return strings.ReplaceAll(s, old, replacement) },
+	"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"default": func(def, s string) string {
+		if s == "" {
+			return def
+		}
+		return s
+	},
+	"quote":  strconv.Quote,
+	"b64enc": func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+	"toJSON": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// RenderUserData renders userData as a Go template with the given data
+func RenderUserData(userData string, data UserDataTemplateData) (string, error) {
+	tmpl, err := template.New("userData").Funcs(userDataTemplateFuncs).Option("missingkey=zero").Parse(userData)
+	if err != nil {
+		return "", fmt.Errorf("parsing userData template, %w", err)
+	}
+	var rendered bytes.Buffer
+	if err = tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("rendering userData template, %w", err)
+	}
+	return rendered.String(), nil
+}
@@ -40,7 +40,7 @@ import (
 type Windows struct {
 	DefaultFamily
 	*Options
-	// Version is the major version of Windows Server (2019 or 2022).
+	// Version is the major version of Windows Server (2019, 2022, or 2025).
 	// Only the core version of each version is supported by Karpenter, so this field only indicates the year.
 	Version string
 	// Build is a specific build code associated with the Version
@@ -48,8 +48,21 @@ type Windows struct {
 }
 
 func (w Windows) DescribeImageQuery(ctx context.Context, ssmProvider ssm.Provider, k8sVersion string, amiVersion string) (DescribeImageQuery, error) {
+	name := fmt.Sprintf("Windows_Server-%s-English-%s-EKS_Optimized-%s", w.Version, v1.WindowsCore, k8sVersion)
+	// Only the latest Windows AMIs are published to SSM, so pinned versions are discovered by the date suffix of the AMI
+	// name instead (ex: "windows2022@2024.09.10" selects "Windows_Server-2022-English-Core-EKS_Optimized-1.30-2024.09.10")
+	if amiVersion != v1.AliasVersionLatest {
+		return DescribeImageQuery{
+			Filters: []ec2types.Filter{{
+				Name:   lo.ToPtr("name"),
+				Values: []string{fmt.Sprintf("%s-%s", name, amiVersion)},
+			}},
+			Owners:       []string{"amazon"},
+			Requirements: []scheduling.Requirements{w.requirements()},
+		}, nil
+	}
 	imageID, err := ssmProvider.Get(ctx, ssm.Parameter{
-		Name:      fmt.Sprintf("/aws/service/ami-windows-latest/Windows_Server-%s-English-%s-EKS_Optimized-%s/image_id", w.Version, v1.WindowsCore, k8sVersion),
+		Name:      fmt.Sprintf("/aws/service/ami-windows-latest/%s/image_id", name),
 		IsMutable: true,
 	})
 	if err != nil {
@@ -61,14 +74,18 @@ func (w Windows) DescribeImageQuery(ctx context.Context, ssmProvider ssm.Provide
 			Values: []string{imageID},
 		}},
 		KnownRequirements: map[string][]scheduling.Requirements{
-			imageID: {scheduling.NewRequirements(
-				scheduling.NewRequirement(corev1.LabelOSStable, corev1.NodeSelectorOpIn, string(corev1.Windows)),
-				scheduling.NewRequirement(corev1.LabelWindowsBuild, corev1.NodeSelectorOpIn, w.Build),
-			)},
+			imageID: {w.requirements()},
 		},
 	}, nil
 }
 
+func (w Windows) requirements() scheduling.Requirements {
+	return scheduling.NewRequirements(
+		scheduling.NewRequirement(corev1.LabelOSStable, corev1.NodeSelectorOpIn, string(corev1.Windows)),
+		scheduling.NewRequirement(corev1.LabelWindowsBuild, corev1.NodeSelectorOpIn, w.Build),
+	)
+}
+
 // UserData returns the default userdata script for the AMI Family
 func (w Windows) UserData(kubeletConfig *v1.KubeletConfiguration, taints []corev1.Taint, labels map[string]string, caBundle *string, _ []*cloudprovider.InstanceType, customUserData *string, _ *v1.InstanceStorePolicy) bootstrap.Bootstrapper {
 	return bootstrap.Windows{
@@ -80,6 +97,7 @@ func (w Windows) UserData(kubeletConfig *v1.KubeletConfiguration, taints []corev
 			Labels:          labels,
 			CABundle:        caBundle,
 			CustomUserData:  customUserData,
+			Registries:      w.Options.Registries,
 		},
 	}
 }
@@ -29,6 +29,7 @@ import (
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
 	awserrors "github.com/aws/karpenter-provider-aws/pkg/errors"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
 )
 
 type Provider interface {
@@ -73,7 +74,7 @@ func (p *DefaultProvider) List(ctx context.Context, selectorTerms ...v1.Capacity
 
 	var reservations []*ec2types.CapacityReservation
 	queries := QueriesFromSelectorTerms(selectorTerms...)
-	reservations, queries = p.resolveCachedQueries(queries...)
+	reservations, queries = p.resolveCachedQueries(ctx, queries...)
 	if len(queries) == 0 {
 		return p.filterReservations(reservations), nil
 	}
@@ -95,15 +96,15 @@ func (p *DefaultProvider) List(ctx context.Context, selectorTerms ...v1.Capacity
 		p.syncAvailability(lo.SliceToMap(queryReservations, func(r *ec2types.CapacityReservation) (string, int) {
 			return *r.CapacityReservationId, int(*r.AvailableInstanceCount)
 		}))
-		p.reservationCache.SetDefault(q.CacheKey(), queryReservations)
+		p.reservationCache.SetDefault(account.CacheKey(ctx, q.CacheKey()), queryReservations)
 		reservations = append(reservations, queryReservations...)
 	}
 	return p.filterReservations(reservations), nil
 }
 
-func (p *DefaultProvider) resolveCachedQueries(queries ...*Query) (reservations []*ec2types.CapacityReservation, remainingQueries []*Query) {
+func (p *DefaultProvider) resolveCachedQueries(ctx context.Context, queries ...*Query) (reservations []*ec2types.CapacityReservation, remainingQueries []*Query) {
 	for _, q := range queries {
-		if value, ok := p.reservationCache.Get(q.CacheKey()); ok {
+		if value, ok := p.reservationCache.Get(account.CacheKey(ctx, q.CacheKey())); ok {
 			reservations = append(reservations, value.([]*ec2types.CapacityReservation)...)
 		} else {
 			remainingQueries = append(remainingQueries, q)
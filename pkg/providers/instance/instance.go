@@ -116,7 +116,7 @@ func NewDefaultProvider(
 
 func (p *DefaultProvider) Create(ctx context.Context, nodeClass *v1.EC2NodeClass, nodeClaim *karpv1.NodeClaim, tags map[string]string, instanceTypes []*cloudprovider.InstanceType) (*Instance, error) {
 	// We filter out instance type that don't have an available offering that supports the capacity type
-	capacityType := getCapacityType(nodeClaim, instanceTypes)
+	capacityType := GetCapacityType(nodeClaim, instanceTypes)
 	fleetInstance, err := p.launchInstance(ctx, nodeClass, nodeClaim, capacityType, instanceTypes, tags)
 	if awserrors.IsLaunchTemplateNotFound(err) {
 		// retry once if launch template is not found. This allows karpenter to generate a new LT if the
@@ -313,7 +313,7 @@ func GetCreateFleetInput(nodeClass *v1.EC2NodeClass, capacityType string, tags m
 
 func (p *DefaultProvider) checkODFallback(nodeClaim *karpv1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, launchTemplateConfigs []ec2types.FleetLaunchTemplateConfigRequest) error {
 	// only evaluate for on-demand fallback if the capacity type for the request is OD and both OD and spot are allowed in requirements
-	if getCapacityType(nodeClaim, instanceTypes) != karpv1.CapacityTypeOnDemand || !scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Get(karpv1.CapacityTypeLabelKey).Has(karpv1.CapacityTypeSpot) {
+	if GetCapacityType(nodeClaim, instanceTypes) != karpv1.CapacityTypeOnDemand || !scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Get(karpv1.CapacityTypeLabelKey).Has(karpv1.CapacityTypeSpot) {
 		return nil
 	}
 
@@ -470,9 +470,9 @@ func (p *DefaultProvider) getCapacityReservationIDForInstance(instance, zone str
 	panic("reservation ID doesn't exist for reserved launch")
 }
 
-// getCapacityType selects the capacity type based on the flexibility of the NodeClaim and the available offerings.
+// GetCapacityType selects the capacity type based on the flexibility of the NodeClaim and the available offerings.
 // Prioritization is as follows: reserved, spot, on-demand.
-func getCapacityType(nodeClaim *karpv1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) string {
+func GetCapacityType(nodeClaim *karpv1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) string {
 	for _, capacityType := range []string{karpv1.CapacityTypeReserved, karpv1.CapacityTypeSpot} {
 		requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...)
 		if !requirements.Get(karpv1.CapacityTypeLabelKey).Has(capacityType) {
@@ -488,45 +488,74 @@ func getCapacityType(nodeClaim *karpv1.NodeClaim, instanceTypes []*cloudprovider
 	return karpv1.CapacityTypeOnDemand
 }
 
+// Reasons that an instance type is rejected from a launch by FilterRejectInstanceTypesWithReasons
+const (
+	RejectReasonNoReservedOfferings = "NoReservedOfferings"
+	RejectReasonExotic              = "Exotic"
+	RejectReasonUnwantedSpot        = "UnwantedSpot"
+	RejectReasonTruncated           = "Truncated"
+)
+
+// RejectedInstanceType is an instance type that was filtered out of a launch, with the reason it was filtered out
+type RejectedInstanceType struct {
+	*cloudprovider.InstanceType
+	Reason string
+}
+
 func FilterRejectInstanceTypes(nodeClaim *karpv1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) ([]*cloudprovider.InstanceType, []*cloudprovider.InstanceType, error) {
-	var err error
+	filtered, rejected, err := FilterRejectInstanceTypesWithReasons(nodeClaim, instanceTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return filtered, lo.Map(rejected, func(r RejectedInstanceType, _ int) *cloudprovider.InstanceType { return r.InstanceType }), nil
+}
+
+// FilterRejectInstanceTypesWithReasons filters the instance types the same way as FilterRejectInstanceTypes, but
+// returns the reason that each instance type was rejected for
+func FilterRejectInstanceTypesWithReasons(nodeClaim *karpv1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) ([]*cloudprovider.InstanceType, []RejectedInstanceType, error) {
 	schedulingRequirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...)
 	// We filter out non-reserved instances regardless of the min-values settings, since if the launch is eligible for
 	// reserved instances that's all we'll include in our fleet request.
 	if reqs := schedulingRequirements; reqs.Get(karpv1.CapacityTypeLabelKey).Has(karpv1.CapacityTypeReserved) {
-		filtered, rejected := filterRejectReservedInstanceTypes(reqs, instanceTypes)
-		if _, err = cloudprovider.InstanceTypes(filtered).SatisfiesMinValues(schedulingRequirements); err != nil {
+		filtered, r := filterRejectReservedInstanceTypes(reqs, instanceTypes)
+		if _, err := cloudprovider.InstanceTypes(filtered).SatisfiesMinValues(schedulingRequirements); err != nil {
 			return nil, nil, cloudprovider.NewCreateError(fmt.Errorf("failed to construct CreateFleet request while respecting minValues requirements, %w", err), "InstanceTypeFilteringFailed", "Failed to filter instance types while respecting minValues")
 		}
 		if len(filtered) > 0 {
-			// TODO: Support FilterReject on Truncating instance types
-			filtered, err = cloudprovider.InstanceTypes(filtered).Truncate(schedulingRequirements, maxInstanceTypes)
-			if err != nil {
-				return nil, nil, cloudprovider.NewCreateError(fmt.Errorf("truncating instance types, %w", err), "InstanceTypeFilteringFailed", "Error truncating instance types based on the passed-in requirements")
-			}
-			return filtered, rejected, nil
+			return truncateInstanceTypes(schedulingRequirements, filtered, withReason(r, RejectReasonNoReservedOfferings))
 		}
 	}
 	// Only filter the instances if there are no minValues in the requirement.
-	var rejected []*cloudprovider.InstanceType
+	var rejected []RejectedInstanceType
 	filtered := instanceTypes
 	if !schedulingRequirements.HasMinValues() {
 		var r []*cloudprovider.InstanceType
 		filtered, r = filterRejectExoticInstanceTypes(filtered)
-		rejected = append(rejected, r...)
+		rejected = append(rejected, withReason(r, RejectReasonExotic)...)
 		// If we could potentially launch either a spot or on-demand node, we want to filter out the spot instance types that
 		// are more expensive than the cheapest on-demand type.
 		if isMixedCapacityLaunch(nodeClaim, filtered) {
 			filtered, r = filterRejectUnwantedSpot(filtered)
-			rejected = append(rejected, r...)
+			rejected = append(rejected, withReason(r, RejectReasonUnwantedSpot)...)
 		}
 	}
-	// TODO: Support FilterReject on Truncating instance types
-	filtered, err = cloudprovider.InstanceTypes(filtered).Truncate(schedulingRequirements, maxInstanceTypes)
+	return truncateInstanceTypes(schedulingRequirements, filtered, rejected)
+}
+
+// truncateInstanceTypes truncates the instance types to the maximum number of instance types that are included in a
+// launch, rejecting the instance types that were truncated
+func truncateInstanceTypes(requirements scheduling.Requirements, instanceTypes []*cloudprovider.InstanceType, rejected []RejectedInstanceType) ([]*cloudprovider.InstanceType, []RejectedInstanceType, error) {
+	truncated, err := cloudprovider.InstanceTypes(instanceTypes).Truncate(requirements, maxInstanceTypes)
 	if err != nil {
 		return nil, nil, cloudprovider.NewCreateError(fmt.Errorf("truncating instance types, %w", err), "InstanceTypeFilteringFailed", "Error truncating instance types based on the passed-in requirements")
 	}
-	return filtered, rejected, nil
+	return truncated, append(rejected, withReason(lo.Without(instanceTypes, truncated...), RejectReasonTruncated)...), nil
+}
+
+func withReason(instanceTypes []*cloudprovider.InstanceType, reason string) []RejectedInstanceType {
+	return lo.Map(instanceTypes, func(it *cloudprovider.InstanceType, _ int) RejectedInstanceType {
+		return RejectedInstanceType{InstanceType: it, Reason: reason}
+	})
 }
 
 // filterReservedInstanceTypes is used to filter the provided set of instance types to only include those with
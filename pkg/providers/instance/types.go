@@ -16,6 +16,7 @@ package instance
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -40,6 +41,10 @@ type Instance struct {
 	SubnetID              string
 	Tags                  map[string]string
 	EFAEnabled            bool
+	// Volumes maps the device name of each attached EBS volume to its volume ID
+	Volumes map[string]string
+	// InstanceProfile is the name of the instance profile associated with the instance
+	InstanceProfile string
 }
 
 func NewInstance(ctx context.Context, out ec2types.Instance) *Instance {
@@ -69,6 +74,12 @@ func NewInstance(ctx context.Context, out ec2types.Instance) *Instance {
 		EFAEnabled: lo.ContainsBy(out.NetworkInterfaces, func(item ec2types.InstanceNetworkInterface) bool {
 			return item.InterfaceType != nil && *item.InterfaceType == string(ec2types.NetworkInterfaceTypeEfa)
 		}),
+		Volumes: lo.SliceToMap(lo.Filter(out.BlockDeviceMappings, func(bdm ec2types.InstanceBlockDeviceMapping, _ int) bool {
+			return bdm.Ebs != nil && bdm.Ebs.VolumeId != nil
+		}), func(bdm ec2types.InstanceBlockDeviceMapping) (string, string) {
+			return lo.FromPtr(bdm.DeviceName), lo.FromPtr(bdm.Ebs.VolumeId)
+		}),
+		InstanceProfile: instanceProfileName(out.IamInstanceProfile),
 	}
 
 }
@@ -94,3 +105,12 @@ func NewInstanceFromFleet(
 		EFAEnabled:            efaEnabled,
 	}
 }
+
+// instanceProfileName returns the name of the instance profile from its ARN, which includes the instance profile's path
+// e.g. arn:aws:iam::123456789012:instance-profile/karpenter/us-west-2/cluster/default/cluster_123
+func instanceProfileName(profile *ec2types.IamInstanceProfile) string {
+	if profile == nil || profile.Arn == nil {
+		return ""
+	}
+	return lo.LastOr(strings.Split(lo.FromPtr(profile.Arn), "/"), "")
+}
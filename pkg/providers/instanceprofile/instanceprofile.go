@@ -32,7 +32,8 @@ import (
 
 type Provider interface {
 	Get(context.Context, string) (*iamtypes.InstanceProfile, error)
-	Create(context.Context, string, string, map[string]string) error
+	List(context.Context, string) ([]iamtypes.InstanceProfile, error)
+	Create(context.Context, string, string, string, map[string]string) error
 	Delete(context.Context, string) error
 }
 
@@ -62,7 +63,26 @@ func (p *DefaultProvider) Get(ctx context.Context, instanceProfileName string) (
 	return out.InstanceProfile, nil
 }
 
-func (p *DefaultProvider) Create(ctx context.Context, instanceProfileName string, roleName string, tags map[string]string) error {
+// List returns the instance profiles under the given IAM path prefix
+func (p *DefaultProvider) List(ctx context.Context, pathPrefix string) ([]iamtypes.InstanceProfile, error) {
+	var instanceProfiles []iamtypes.InstanceProfile
+	paginator := iam.NewListInstanceProfilesPaginator(p.iamapi, &iam.ListInstanceProfilesInput{
+		PathPrefix: lo.ToPtr(pathPrefix),
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, serrors.Wrap(fmt.Errorf("listing instance profiles, %w", err), "path-prefix", pathPrefix)
+		}
+		instanceProfiles = append(instanceProfiles, out.InstanceProfiles...)
+	}
+	return instanceProfiles, nil
+}
+
+func (p *DefaultProvider) Create(ctx context.Context, instanceProfileName string, path string, roleName string, tags map[string]string) error {
+	// If the role has a path, ignore the path and take the role name only since AddRoleToInstanceProfile
+	// does not support paths in the role name.
+	roleName = RoleName(roleName)
 	instanceProfile, err := p.Get(ctx, instanceProfileName)
 	if err != nil {
 		if !awserrors.IsNotFound(err) {
@@ -70,6 +90,7 @@ func (p *DefaultProvider) Create(ctx context.Context, instanceProfileName string
 		}
 		o, err := p.iamapi.CreateInstanceProfile(ctx, &iam.CreateInstanceProfileInput{
 			InstanceProfileName: lo.ToPtr(instanceProfileName),
+			Path:                lo.EmptyableToPtr(path),
 			Tags:                utils.IAMMergeTags(tags),
 		})
 		if err != nil {
@@ -90,9 +111,6 @@ func (p *DefaultProvider) Create(ctx context.Context, instanceProfileName string
 			return serrors.Wrap(fmt.Errorf("removing role for instance profile, %w", err), "role", lo.FromPtr(instanceProfile.Roles[0].RoleName), "instance-profile", instanceProfileName)
 		}
 	}
-	// If the role has a path, ignore the path and take the role name only since AddRoleToInstanceProfile
-	// does not support paths in the role name.
-	roleName = lo.LastOr(strings.Split(roleName, "/"), roleName)
 	if _, err = p.iamapi.AddRoleToInstanceProfile(ctx, &iam.AddRoleToInstanceProfileInput{
 		InstanceProfileName: lo.ToPtr(instanceProfileName),
 		RoleName:            lo.ToPtr(roleName),
@@ -131,3 +149,8 @@ func (p *DefaultProvider) Delete(ctx context.Context, instanceProfileName string
 	p.cache.Delete(instanceProfileName)
 	return nil
 }
+
+// RoleName returns the name of a role, stripping the path if one is specified
+func RoleName(role string) string {
+	return lo.LastOr(strings.Split(role, "/"), role)
+}
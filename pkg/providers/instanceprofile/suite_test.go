@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/samber/lo"
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
@@ -105,7 +106,7 @@ var _ = Describe("InstanceProfileProvider", func() {
 		func(roleWithPath, role string) {
 			const profileName = "test-profile"
 			nodeClass.Spec.Role = roleWithPath
-			Expect(awsEnv.InstanceProfileProvider.Create(ctx, profileName, role, nil)).To(Succeed())
+			Expect(awsEnv.InstanceProfileProvider.Create(ctx, profileName, "", roleWithPath, nil)).To(Succeed())
 			Expect(profileName).ToNot(BeNil())
 			Expect(awsEnv.IAMAPI.InstanceProfiles[profileName].Roles).To(HaveLen(1))
 			Expect(aws.ToString(awsEnv.IAMAPI.InstanceProfiles[profileName].Roles[0].RoleName)).To(Equal(role))
@@ -113,4 +114,24 @@ var _ = Describe("InstanceProfileProvider", func() {
 		Entry("with custom paths", fmt.Sprintf("CustomPath/%s", nodeRole), nodeRole),
 		Entry("without custom paths", nodeRole, nodeRole),
 	)
+	It("should not swap the role when the existing role is specified with a path", func() {
+		const profileName = "test-profile"
+		Expect(awsEnv.InstanceProfileProvider.Create(ctx, profileName, "", fmt.Sprintf("CustomPath/%s", nodeRole), nil)).To(Succeed())
+		awsEnv.InstanceProfileCache.Flush()
+		Expect(awsEnv.InstanceProfileProvider.Create(ctx, profileName, "", fmt.Sprintf("CustomPath/%s", nodeRole), nil)).To(Succeed())
+		Expect(awsEnv.IAMAPI.RemoveRoleFromInstanceProfileBehavior.Calls()).To(BeZero())
+	})
+	It("should create the instance profile with the given path", func() {
+		const profileName = "test-profile"
+		Expect(awsEnv.InstanceProfileProvider.Create(ctx, profileName, "/karpenter/test/", nodeRole, nil)).To(Succeed())
+		Expect(aws.ToString(awsEnv.IAMAPI.InstanceProfiles[profileName].Path)).To(Equal("/karpenter/test/"))
+	})
+	It("should list the instance profiles under a path prefix", func() {
+		Expect(awsEnv.InstanceProfileProvider.Create(ctx, "profile-a", "/karpenter/test/a/", nodeRole, nil)).To(Succeed())
+		Expect(awsEnv.InstanceProfileProvider.Create(ctx, "profile-b", "/karpenter/test/b/", nodeRole, nil)).To(Succeed())
+		Expect(awsEnv.InstanceProfileProvider.Create(ctx, "profile-c", "", nodeRole, nil)).To(Succeed())
+		profiles, err := awsEnv.InstanceProfileProvider.List(ctx, "/karpenter/test/")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lo.Map(profiles, func(p iamtypes.InstanceProfile, _ int) string { return aws.ToString(p.InstanceProfileName) })).To(ConsistOf("profile-a", "profile-b"))
+	})
 })
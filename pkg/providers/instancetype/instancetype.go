@@ -17,10 +17,10 @@ package instancetype
 import (
 	"context"
 	"fmt"
+	"path"
 	"sync"
 	"sync/atomic"
 
-	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
 
 	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
@@ -48,6 +48,7 @@ import (
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/utils/pretty"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
 )
 
 type Provider interface {
@@ -174,12 +175,15 @@ func (p *DefaultProvider) resolveInstanceTypes(
 		return s.Zone, s.ZoneID
 	})
 	return lo.FilterMap(p.instanceTypesInfo, func(info ec2types.InstanceTypeInfo, _ int) (*cloudprovider.InstanceType, bool) {
+		if !selected(info, nodeClass.Spec.InstanceTypeSelector) {
+			return nil, false
+		}
 		it := p.instanceTypesResolver.Resolve(ctx, info, p.instanceTypesOfferings[string(info.InstanceType)].UnsortedList(), zonesToZoneIDs, nodeClass)
 		if it == nil {
 			return nil, false
 		}
-		if cached, ok := p.discoveredCapacityCache.Get(fmt.Sprintf("%s-%016x", it.Name, amiHash)); ok {
-			it.Capacity[corev1.ResourceMemory] = cached.(resource.Quantity)
+		if cached, ok := p.discoveredCapacityCache.Get(p.discoveredCapacityKey(it.Name, nodeClass, amiHash)); ok {
+			applyDiscoveredCapacity(it, cached.(discoveredCapacity))
 		}
 		InstanceTypeVCPU.Set(float64(lo.FromPtr(info.VCpuInfo.DefaultVCpus)), map[string]string{
 			instanceTypeLabel: string(info.InstanceType),
@@ -191,6 +195,31 @@ func (p *DefaultProvider) resolveInstanceTypes(
 	})
 }
 
+// selected returns true if the instance type is allowed by the instance type selector of an EC2NodeClass
+func selected(info ec2types.InstanceTypeInfo, selector *v1.InstanceTypeSelector) bool {
+	if selector == nil {
+		return true
+	}
+	if lo.FromPtr(selector.ExcludePreviousGeneration) && !lo.FromPtr(info.CurrentGeneration) {
+		return false
+	}
+	if lo.FromPtr(selector.ExcludeBareMetal) && lo.FromPtr(info.BareMetal) {
+		return false
+	}
+	if len(selector.Allow) > 0 && !matchesAny(string(info.InstanceType), selector.Allow) {
+		return false
+	}
+	return !matchesAny(string(info.InstanceType), selector.Deny)
+}
+
+func matchesAny(name string, patterns []string) bool {
+	return lo.ContainsBy(patterns, func(pattern string) bool {
+		// Patterns are validated to only contain characters which can't make them malformed
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
 func (p *DefaultProvider) UpdateInstanceTypes(ctx context.Context) error {
 	// DO NOT REMOVE THIS LOCK ----------------------------------------------------------------------------
 	// We lock here so that multiple callers to getInstanceTypeOfferings do not result in cache misses and multiple
@@ -295,17 +324,111 @@ func (p *DefaultProvider) UpdateInstanceTypeCapacityFromNode(ctx context.Context
 	}
 
 	amiHash, _ := hashstructure.Hash(nodeClass.Status.AMIs, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
-	key := fmt.Sprintf("%s-%016x", instanceTypeName, amiHash)
+	key := p.discoveredCapacityKey(instanceTypeName, nodeClass, amiHash)
 
-	// Update cache if non-existent or actual capacity is less than or equal to cached value
-	actualCapacity := node.Status.Capacity.Memory()
-	if cachedCapacity, ok := p.discoveredCapacityCache.Get(key); !ok || actualCapacity.Cmp(cachedCapacity.(resource.Quantity)) < 1 {
-		log.FromContext(ctx).WithValues("memory-capacity", actualCapacity, "instance-type", instanceTypeName).V(1).Info("updating discovered capacity cache")
-		p.discoveredCapacityCache.SetDefault(key, *actualCapacity)
+	// Each resource tracks the lowest value reported by any node, so the cached values only ever decrease until they expire
+	var cached discoveredCapacity
+	if item, ok := p.discoveredCapacityCache.Get(key); ok {
+		cached = item.(discoveredCapacity)
 	}
+	capacity, capacityChanged := lowerResources(cached.Capacity, node.Status.Capacity)
+	allocatable, allocatableChanged := lowerResources(cached.Allocatable, node.Status.Allocatable)
+	if capacityChanged || allocatableChanged {
+		log.FromContext(ctx).WithValues("capacity", capacity, "allocatable", allocatable, "instance-type", instanceTypeName).V(1).Info("updating discovered capacity cache")
+	}
+	p.discoveredCapacityCache.SetDefault(key, discoveredCapacity{Capacity: capacity, Allocatable: allocatable})
 	return nil
 }
 
+// discoveredCapacityKey scopes discovered capacity to the EC2NodeClass that launched the node, and is invalidated by the
+// same changes to the EC2NodeClass that invalidate the instance types cache (e.g. changes to the kubelet configuration,
+// block device mappings, or CPU options), since these change the resources of the node.
+func (p *DefaultProvider) discoveredCapacityKey(instanceTypeName string, nodeClass *v1.EC2NodeClass, amiHash uint64) string {
+	return fmt.Sprintf("%s-%s-%s-%016x", nodeClass.UID, instanceTypeName, p.instanceTypesResolver.CacheKey(nodeClass), amiHash)
+}
+
+// discoveredCapacity is the capacity and allocatable reported by registered nodes for an instance type launched with a set of AMIs
+type discoveredCapacity struct {
+	Capacity    corev1.ResourceList
+	Allocatable corev1.ResourceList
+}
+
+// lowerResources returns a copy of the cached resources, lowered to any smaller values which were observed on a node.
+// Zero values aren't learned since extended resources can be reported as zero before their device plugin is ready.
+func lowerResources(cached, observed corev1.ResourceList) (corev1.ResourceList, bool) {
+	lowered := corev1.ResourceList{}
+	for name, quantity := range cached {
+		lowered[name] = quantity.DeepCopy()
+	}
+	changed := false
+	for name, quantity := range observed {
+		if quantity.IsZero() {
+			continue
+		}
+		if current, ok := lowered[name]; !ok || quantity.Cmp(current) < 0 {
+			lowered[name] = quantity.DeepCopy()
+			changed = true
+		}
+	}
+	return lowered, changed
+}
+
+// applyDiscoveredCapacity lowers the allocatable of an instance type to the allocatable discovered from registered nodes.
+// The computed capacity is kept, since it determines the kubelet configuration and launch template parameters (e.g. max
+// pods, core count, and root volume size) of the instance type. The exceptions are memory, whose computed capacity is only
+// an estimate of the memory that's available to the OS, and resources that are computed as zero. A computed zero means the
+// resource isn't known from the EC2 instance type info, so resources registered by device plugins (e.g. GPUs or EFA on
+// Custom AMIs) are learned from the nodes.
+func applyDiscoveredCapacity(it *cloudprovider.InstanceType, discovered discoveredCapacity) {
+	for name, quantity := range discovered.Capacity {
+		predicted, ok := it.Capacity[name]
+		InstanceTypeCapacityPredictionDelta.Set(quantity.AsApproximateFloat64()-predicted.AsApproximateFloat64(), map[string]string{
+			instanceTypeLabel: it.Name,
+			resourceTypeLabel: string(name),
+		})
+		if !ok || predicted.IsZero() || name == corev1.ResourceMemory {
+			it.Capacity[name] = quantity.DeepCopy()
+		}
+	}
+	if len(discovered.Allocatable) == 0 {
+		return
+	}
+	// Allocatable is computed rather than read from the instance type, since reading it memoizes the result
+	allocatable := resources.Subtract(it.Capacity, it.Overhead.Total())
+	// The overhead is copied rather than modified, since its resource lists are computed from the EC2NodeClass
+	overhead := &cloudprovider.InstanceTypeOverhead{
+		KubeReserved:      it.Overhead.KubeReserved.DeepCopy(),
+		SystemReserved:    it.Overhead.SystemReserved.DeepCopy(),
+		EvictionThreshold: it.Overhead.EvictionThreshold.DeepCopy(),
+	}
+	if overhead.EvictionThreshold == nil {
+		overhead.EvictionThreshold = corev1.ResourceList{}
+	}
+	for name, quantity := range discovered.Allocatable {
+		predicted := allocatable[name]
+		InstanceTypeAllocatablePredictionDelta.Set(quantity.AsApproximateFloat64()-predicted.AsApproximateFloat64(), map[string]string{
+			instanceTypeLabel: it.Name,
+			resourceTypeLabel: string(name),
+		})
+		switch cmp := quantity.Cmp(predicted); {
+		case cmp > 0 && predicted.IsZero():
+			// The resource isn't known from the instance type info, so its capacity is raised to make it allocatable
+			capacity := it.Capacity[name]
+			capacity.Add(quantity)
+			it.Capacity[name] = capacity
+		case cmp < 0:
+			// The core InstanceTypeOverhead has no field for overhead that's only observed on nodes. The kube and system
+			// reserved lists mirror the kubelet configuration that's passed to the nodes, so the observed overhead is
+			// accounted for in the eviction threshold instead. Reported eviction thresholds include this overhead.
+			threshold := overhead.EvictionThreshold[name]
+			threshold.Add(predicted)
+			threshold.Sub(quantity)
+			overhead.EvictionThreshold[name] = threshold
+		}
+	}
+	it.Overhead = overhead
+}
+
 func (p *DefaultProvider) Reset() {
 	p.instanceTypesInfo = []ec2types.InstanceTypeInfo{}
 	p.instanceTypesOfferings = map[string]sets.Set[string]{}
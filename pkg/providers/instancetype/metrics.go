@@ -25,6 +25,7 @@ import (
 const (
 	cloudProviderSubsystem = "cloudprovider"
 	instanceTypeLabel      = "instance_type"
+	resourceTypeLabel      = "resource_type"
 )
 
 var (
@@ -52,4 +53,30 @@ var (
 			instanceTypeLabel,
 		},
 	)
+	InstanceTypeCapacityPredictionDelta = opmetrics.NewPrometheusGauge(
+		crmetrics.Registry,
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "instance_type_capacity_prediction_delta",
+			Help:      "Difference between the capacity discovered from registered nodes and the computed capacity for a given instance type and resource. Negative values indicate the computed capacity overestimated the node.",
+		},
+		[]string{
+			instanceTypeLabel,
+			resourceTypeLabel,
+		},
+	)
+	InstanceTypeAllocatablePredictionDelta = opmetrics.NewPrometheusGauge(
+		crmetrics.Registry,
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "instance_type_allocatable_prediction_delta",
+			Help:      "Difference between the allocatable discovered from registered nodes and the computed allocatable for a given instance type and resource. Negative values indicate the computed allocatable overestimated the node.",
+		},
+		[]string{
+			instanceTypeLabel,
+			resourceTypeLabel,
+		},
+	)
 )
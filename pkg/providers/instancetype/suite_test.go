@@ -240,6 +240,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1.LabelInstanceFamily:                       "g4dn",
 			v1.LabelInstanceSize:                         "8xlarge",
 			v1.LabelInstanceCPU:                          "32",
+			v1.LabelInstanceThreadsPerCore:               "2",
 			v1.LabelInstanceCPUManufacturer:              "intel",
 			v1.LabelInstanceCPUSustainedClockSpeedMhz:    "2500",
 			v1.LabelInstanceMemory:                       "131072",
@@ -300,6 +301,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1.LabelInstanceFamily:                       "g4dn",
 			v1.LabelInstanceSize:                         "8xlarge",
 			v1.LabelInstanceCPU:                          "32",
+			v1.LabelInstanceThreadsPerCore:               "2",
 			v1.LabelInstanceCPUManufacturer:              "intel",
 			v1.LabelInstanceCPUSustainedClockSpeedMhz:    "2500",
 			v1.LabelInstanceMemory:                       "131072",
@@ -949,6 +951,133 @@ var _ = Describe("InstanceTypeProvider", func() {
 		Expect(node.Labels[corev1.LabelInstanceTypeStable]).To(Equal("m6idn.32xlarge"))
 		Expect(*node.Status.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("7600G")))
 	})
+	It("should only count half of the instance storage for ephemeral storage when disks are mounted as RAID10", func() {
+		nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1.InstanceStorePolicyRAID10)
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		pod := coretest.UnschedulablePod(coretest.PodOptions{
+			ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("3000Gi")},
+			},
+		})
+		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+		node := ExpectScheduled(ctx, env.Client, pod)
+		Expect(node.Labels[corev1.LabelInstanceTypeStable]).To(Equal("m6idn.32xlarge"))
+		Expect(*node.Status.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("3800G")))
+	})
+	It("should expose individually mounted NVMe disks as a resource when using the Mount instance store policy", func() {
+		instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{})
+		Expect(err).To(BeNil())
+		for _, policy := range []*v1.InstanceStorePolicy{nil, lo.ToPtr(v1.InstanceStorePolicyRAID0), lo.ToPtr(v1.InstanceStorePolicyMount), lo.ToPtr(v1.InstanceStorePolicyNone)} {
+			for _, info := range instanceInfo.InstanceTypes {
+				it := instancetype.NewInstanceType(ctx,
+					info,
+					fake.DefaultRegion,
+					nil,
+					nil,
+					nodeClass.Spec.BlockDeviceMappings,
+					policy,
+					nodeClass.Spec.CPUOptions,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
+					nodeClass.AMIFamily(),
+					nil,
+				)
+				var disks int64
+				if info.InstanceStorageInfo != nil && info.InstanceStorageInfo.NvmeSupport != ec2types.EphemeralNvmeSupportUnsupported {
+					disks = lo.SumBy(info.InstanceStorageInfo.Disks, func(d ec2types.DiskInfo) int64 { return int64(lo.FromPtr(d.Count)) })
+				}
+				if lo.FromPtr(policy) == v1.InstanceStorePolicyMount {
+					Expect(it.Capacity.Name(v1.ResourceInstanceLocalNVMe, resource.DecimalSI).Value()).To(Equal(disks))
+				} else {
+					Expect(it.Capacity.Name(v1.ResourceInstanceLocalNVMe, resource.DecimalSI).IsZero()).To(BeTrue())
+				}
+				if lo.FromPtr(policy) != v1.InstanceStorePolicyRAID0 || info.InstanceStorageInfo == nil {
+					Expect(it.Capacity.StorageEphemeral().String()).To(Equal("20Gi"))
+				}
+			}
+		}
+	})
+	It("should scale ephemeral storage with the instance type when the root volume has a size policy", func() {
+		instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{})
+		Expect(err).To(BeNil())
+		nodeClass.Spec.BlockDeviceMappings = []*v1.BlockDeviceMapping{
+			{
+				DeviceName: aws.String("/dev/xvda"),
+				EBS: &v1.BlockDevice{
+					VolumeSize: lo.ToPtr(resource.MustParse("20Gi")),
+					VolumeSizePolicy: &v1.VolumeSizePolicy{
+						PerVCPU: lo.ToPtr(resource.MustParse("5Gi")),
+						Max:     lo.ToPtr(resource.MustParse("500Gi")),
+					},
+				},
+				RootVolume: true,
+			},
+		}
+		for _, info := range instanceInfo.InstanceTypes {
+			it := instancetype.NewInstanceType(ctx,
+				info,
+				fake.DefaultRegion,
+				nil,
+				nil,
+				nodeClass.Spec.BlockDeviceMappings,
+				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nodeClass.AMIFamily(),
+				nil,
+			)
+			expected := min(20+5*int64(lo.FromPtr(info.VCpuInfo.DefaultVCpus)), 500)
+			Expect(it.Capacity.StorageEphemeral().String()).To(Equal(fmt.Sprintf("%dGi", expected)))
+		}
+	})
+	It("should scale ephemeral storage with the vCPUs of the instance type once launched with the CPU options", func() {
+		instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{})
+		Expect(err).To(BeNil())
+		nodeClass.Spec.CPUOptions = &v1.CPUOptions{ThreadsPerCore: lo.ToPtr[int32](1)}
+		nodeClass.Spec.BlockDeviceMappings = []*v1.BlockDeviceMapping{
+			{
+				DeviceName: aws.String("/dev/xvda"),
+				EBS: &v1.BlockDevice{
+					VolumeSizePolicy: &v1.VolumeSizePolicy{PerVCPU: lo.ToPtr(resource.MustParse("5Gi"))},
+				},
+				RootVolume: true,
+			},
+		}
+		for _, info := range instanceInfo.InstanceTypes {
+			if info.VCpuInfo.DefaultCores == nil {
+				continue
+			}
+			it := instancetype.NewInstanceType(ctx,
+				info,
+				fake.DefaultRegion,
+				nil,
+				nil,
+				nodeClass.Spec.BlockDeviceMappings,
+				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nodeClass.AMIFamily(),
+				nil,
+			)
+			// The launch template's root volume is sized by the instance-cpu label
+			Expect(it.Requirements.Get(v1.LabelInstanceCPU).Any()).To(Equal(fmt.Sprint(lo.FromPtr(info.VCpuInfo.DefaultCores))))
+			Expect(it.Capacity.StorageEphemeral().String()).To(Equal(fmt.Sprintf("%dGi", max(5*int64(lo.FromPtr(info.VCpuInfo.DefaultCores)), 1))))
+		}
+	})
 	It("should not set pods to 110 if using ENI-based pod density", func() {
 		instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{})
 		Expect(err).To(BeNil())
@@ -961,6 +1090,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				nil,
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodeClass.Spec.Kubelet.MaxPods,
 				nodeClass.Spec.Kubelet.PodsPerCore,
 				nodeClass.Spec.Kubelet.KubeReserved,
@@ -985,6 +1115,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				nil,
 				windowsNodeClass.Spec.BlockDeviceMappings,
 				windowsNodeClass.Spec.InstanceStorePolicy,
+				windowsNodeClass.Spec.CPUOptions,
 				nodeClass.Spec.Kubelet.MaxPods,
 				nodeClass.Spec.Kubelet.PodsPerCore,
 				nodeClass.Spec.Kubelet.KubeReserved,
@@ -1070,6 +1201,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1098,6 +1230,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1122,6 +1255,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1155,6 +1289,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1195,6 +1330,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1225,6 +1361,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1255,6 +1392,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1285,6 +1423,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1317,6 +1456,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1350,6 +1490,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1380,6 +1521,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1414,6 +1556,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1435,6 +1578,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1470,6 +1614,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1503,6 +1648,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1536,6 +1682,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1561,6 +1708,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1580,6 +1728,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1607,6 +1756,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1633,6 +1783,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1664,6 +1815,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				nil,
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodeClass.Spec.Kubelet.MaxPods,
 				nodeClass.Spec.Kubelet.PodsPerCore,
 				nodeClass.Spec.Kubelet.KubeReserved,
@@ -1700,6 +1852,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				nil,
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodeClass.Spec.Kubelet.MaxPods,
 				nodeClass.Spec.Kubelet.PodsPerCore,
 				nodeClass.Spec.Kubelet.KubeReserved,
@@ -1732,6 +1885,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1759,6 +1913,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1786,6 +1941,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nil,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodeClass.Spec.Kubelet.MaxPods,
 					nodeClass.Spec.Kubelet.PodsPerCore,
 					nodeClass.Spec.Kubelet.KubeReserved,
@@ -1814,6 +1970,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1833,6 +1990,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nil,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodeClass.Spec.Kubelet.MaxPods,
 						nodeClass.Spec.Kubelet.PodsPerCore,
 						nodeClass.Spec.Kubelet.KubeReserved,
@@ -1934,6 +2092,230 @@ var _ = Describe("InstanceTypeProvider", func() {
 			}
 		})
 	})
+	Context("CPU Options", func() {
+		BeforeEach(func() {
+			instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{})
+			Expect(err).To(BeNil())
+			awsEnv.EC2API.DescribeInstanceTypesOutput.Set(&ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: lo.Map(instanceInfo.InstanceTypes, func(info ec2types.InstanceTypeInfo, _ int) ec2types.InstanceTypeInfo {
+					switch info.InstanceType {
+					case "m5.large":
+						info.VCpuInfo = &ec2types.VCpuInfo{DefaultCores: aws.Int32(1), DefaultThreadsPerCore: aws.Int32(2), DefaultVCpus: aws.Int32(2), ValidCores: []int32{1}, ValidThreadsPerCore: []int32{1, 2}}
+					case "m5.xlarge":
+						info.VCpuInfo = &ec2types.VCpuInfo{DefaultCores: aws.Int32(2), DefaultThreadsPerCore: aws.Int32(2), DefaultVCpus: aws.Int32(4), ValidCores: []int32{2}, ValidThreadsPerCore: []int32{1, 2}}
+						processorInfo := *info.ProcessorInfo
+						processorInfo.SupportedFeatures = []ec2types.SupportedAdditionalProcessorFeature{ec2types.SupportedAdditionalProcessorFeatureAmdSevSnp}
+						info.ProcessorInfo = &processorInfo
+					}
+					return info
+				}),
+			})
+			Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypes(ctx)).To(Succeed())
+		})
+		It("should only consider instance types which support the requested threads per core", func() {
+			nodeClass.Spec.CPUOptions = &v1.CPUOptions{ThreadsPerCore: aws.Int32(1)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			its, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			Expect(lo.Map(its, func(it *corecloudprovider.InstanceType, _ int) string { return it.Name })).To(ConsistOf("m5.large", "m5.xlarge"))
+		})
+		It("should report the reduced cpu capacity when simultaneous multithreading is disabled", func() {
+			nodeClass.Spec.CPUOptions = &v1.CPUOptions{ThreadsPerCore: aws.Int32(1)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			its, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			it, ok := lo.Find(its, func(it *corecloudprovider.InstanceType) bool { return it.Name == "m5.xlarge" })
+			Expect(ok).To(BeTrue())
+			Expect(it.Capacity.Cpu().Value()).To(BeNumerically("==", 2))
+			Expect(it.Requirements.Get(v1.LabelInstanceCPU).Any()).To(Equal("2"))
+			Expect(it.Requirements.Get(v1.LabelInstanceThreadsPerCore).Any()).To(Equal("1"))
+		})
+		It("should pass the core count and threads per core to the launch template", func() {
+			nodeClass.Spec.CPUOptions = &v1.CPUOptions{ThreadsPerCore: aws.Int32(1)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{corev1.LabelInstanceTypeStable: "m5.xlarge"}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(v1.LabelInstanceThreadsPerCore, "1"))
+			Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeNumerically(">=", 1))
+			awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.CpuOptions).ToNot(BeNil())
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.CpuOptions.CoreCount)).To(BeNumerically("==", 2))
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.CpuOptions.ThreadsPerCore)).To(BeNumerically("==", 1))
+			})
+		})
+		It("should only consider instance types which support AMD SEV-SNP when enabled", func() {
+			nodeClass.Spec.CPUOptions = &v1.CPUOptions{AMDSEVSNP: lo.ToPtr("enabled")}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			its, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			Expect(lo.Map(its, func(it *corecloudprovider.InstanceType, _ int) string { return it.Name })).To(ConsistOf("m5.xlarge"))
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.CpuOptions.AmdSevSnp).To(Equal(ec2types.AmdSevSnpSpecificationEnabled))
+				Expect(ltInput.LaunchTemplateData.CpuOptions.CoreCount).To(BeNil())
+			})
+		})
+		It("should not filter instance types when cpu options are not configured", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			its, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			Expect(len(its)).To(BeNumerically(">", 2))
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.CpuOptions).To(BeNil())
+			})
+		})
+	})
+	Context("Instance Type Selector", func() {
+		instanceTypeNames := func() []string {
+			GinkgoHelper()
+			its, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+			Expect(err).To(BeNil())
+			return lo.Map(its, func(it *corecloudprovider.InstanceType, _ int) string { return it.Name })
+		}
+		It("should only consider instance types which match an allowed pattern", func() {
+			nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{Allow: []string{"m5.*", "t3.large"}}
+			names := instanceTypeNames()
+			Expect(names).To(ContainElements("m5.large", "m5.xlarge", "m5.metal", "t3.large"))
+			Expect(lo.EveryBy(names, func(name string) bool { return strings.HasPrefix(name, "m5.") || name == "t3.large" })).To(BeTrue())
+		})
+		It("should not consider instance types which match a denied pattern", func() {
+			nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{Deny: []string{"m5.*"}}
+			names := instanceTypeNames()
+			Expect(names).ToNot(BeEmpty())
+			Expect(names).ToNot(ContainElement(HavePrefix("m5.")))
+		})
+		It("should prefer denied patterns over allowed patterns", func() {
+			nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{Allow: []string{"m5.*"}, Deny: []string{"m5.?large"}}
+			Expect(instanceTypeNames()).To(ConsistOf("m5.large", "m5.metal"))
+		})
+		It("should not consider bare metal instance types when excluded", func() {
+			nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{ExcludeBareMetal: lo.ToPtr(true)}
+			names := instanceTypeNames()
+			Expect(names).To(ContainElement("m5.large"))
+			Expect(names).ToNot(ContainElement("m5.metal"))
+		})
+		It("should not consider previous generation instance types when excluded", func() {
+			instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{})
+			Expect(err).To(BeNil())
+			awsEnv.EC2API.DescribeInstanceTypesOutput.Set(&ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: lo.Map(instanceInfo.InstanceTypes, func(info ec2types.InstanceTypeInfo, _ int) ec2types.InstanceTypeInfo {
+					info.CurrentGeneration = lo.ToPtr(info.InstanceType != "m5.large")
+					return info
+				}),
+			})
+			Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypes(ctx)).To(Succeed())
+			nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{ExcludePreviousGeneration: lo.ToPtr(true)}
+			names := instanceTypeNames()
+			Expect(names).To(ContainElement("m5.xlarge"))
+			Expect(names).ToNot(ContainElement("m5.large"))
+		})
+		It("should not launch instance types which aren't selected", func() {
+			nodeClass.Spec.InstanceTypeSelector = &v1.InstanceTypeSelector{Allow: []string{"c*"}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{corev1.LabelInstanceTypeStable: "m5.large"}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+		It("should not filter instance types when the selector is not configured", func() {
+			names := instanceTypeNames()
+			Expect(names).To(ContainElements("m5.large", "m5.metal", "t3.large"))
+		})
+	})
+	Context("Kubelet Instance Type Overrides", func() {
+		findInstanceType := func(its []*corecloudprovider.InstanceType, name string) *corecloudprovider.InstanceType {
+			it, ok := lo.Find(its, func(it *corecloudprovider.InstanceType) bool { return it.Name == name })
+			Expect(ok).To(BeTrue())
+			return it
+		}
+		It("should apply an override to the instance types it matches", func() {
+			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+				MaxPods:      lo.ToPtr[int32](10),
+				KubeReserved: map[string]string{string(corev1.ResourceMemory): "1Gi"},
+				InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{{
+					InstanceFamilies: []string{"m5"},
+					InstanceSizes:    []string{"xlarge"},
+					MaxPods:          lo.ToPtr[int32](20),
+					KubeReserved:     map[string]string{string(corev1.ResourceMemory): "2Gi"},
+					SystemReserved:   map[string]string{string(corev1.ResourceMemory): "500Mi"},
+				}},
+			}
+			its, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+			Expect(err).To(BeNil())
+
+			matched := findInstanceType(its, "m5.xlarge")
+			Expect(matched.Capacity.Pods().Value()).To(BeNumerically("==", 20))
+			Expect(matched.Overhead.KubeReserved.Memory().String()).To(Equal("2Gi"))
+			Expect(matched.Overhead.SystemReserved.Memory().String()).To(Equal("500Mi"))
+
+			unmatched := findInstanceType(its, "m5.large")
+			Expect(unmatched.Capacity.Pods().Value()).To(BeNumerically("==", 10))
+			Expect(unmatched.Overhead.KubeReserved.Memory().String()).To(Equal("1Gi"))
+			Expect(unmatched.Overhead.SystemReserved.Memory().IsZero()).To(BeTrue())
+		})
+		It("should match overrides by vCPU and memory ranges", func() {
+			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+				InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{
+					{MinVCPU: lo.ToPtr[int32](4), MaxVCPU: lo.ToPtr[int32](4), MaxPods: lo.ToPtr[int32](20)},
+					{MinMemory: lo.ToPtr(resource.MustParse("256Gi")), MaxPods: lo.ToPtr[int32](30)},
+				},
+			}
+			its, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(findInstanceType(its, "m5.xlarge").Capacity.Pods().Value()).To(BeNumerically("==", 20))
+			Expect(findInstanceType(its, "m5.metal").Capacity.Pods().Value()).To(BeNumerically("==", 30))
+			Expect(findInstanceType(its, "m5.large").Capacity.Pods().Value()).ToNot(BeElementOf(int64(20), int64(30)))
+		})
+		It("should apply the first override which matches an instance type", func() {
+			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+				InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{
+					{InstanceSizes: []string{"xlarge"}, MaxPods: lo.ToPtr[int32](20)},
+					{InstanceFamilies: []string{"m5"}, MaxPods: lo.ToPtr[int32](30)},
+				},
+			}
+			its, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(findInstanceType(its, "m5.xlarge").Capacity.Pods().Value()).To(BeNumerically("==", 20))
+			Expect(findInstanceType(its, "m5.large").Capacity.Pods().Value()).To(BeNumerically("==", 30))
+		})
+		It("should not reuse cached instance types when overrides are re-ordered", func() {
+			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+				InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{
+					{InstanceSizes: []string{"xlarge"}, MaxPods: lo.ToPtr[int32](20)},
+					{InstanceFamilies: []string{"m5"}, MaxPods: lo.ToPtr[int32](30)},
+				},
+			}
+			its, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(findInstanceType(its, "m5.xlarge").Capacity.Pods().Value()).To(BeNumerically("==", 20))
+
+			overrides := nodeClass.Spec.Kubelet.InstanceTypeOverrides
+			overrides[0], overrides[1] = overrides[1], overrides[0]
+			its, err = awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(findInstanceType(its, "m5.xlarge").Capacity.Pods().Value()).To(BeNumerically("==", 30))
+		})
+		It("should only replace the values an override sets", func() {
+			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+				MaxPods:      lo.ToPtr[int32](10),
+				KubeReserved: map[string]string{string(corev1.ResourceMemory): "1Gi"},
+				InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{{
+					InstanceSizes: []string{"xlarge"},
+					MaxPods:       lo.ToPtr[int32](20),
+				}},
+			}
+			its, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+			Expect(err).To(BeNil())
+			it := findInstanceType(its, "m5.xlarge")
+			Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 20))
+			Expect(it.Overhead.KubeReserved.Memory().String()).To(Equal("1Gi"))
+		})
+	})
 	Context("Insufficient Capacity Error Cache", func() {
 		It("should launch instances of different type on second reconciliation attempt with Insufficient Capacity Error Cache fallback", func() {
 			awsEnv.EC2API.InsufficientCapacityPools.Set([]fake.CapacityPool{{CapacityType: karpv1.CapacityTypeOnDemand, InstanceType: "inf2.24xlarge", Zone: "test-zone-1a"}})
@@ -2300,6 +2682,22 @@ var _ = Describe("InstanceTypeProvider", func() {
 				Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.VolumeInitializationRate).To(Equal(int32(100)))
 			})
 		})
+		It("should default to EBS defaults when volumeSize is not defined in blockDeviceMappings for Ubuntu Root volume", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@latest"}}
+			nodeClass.Spec.BlockDeviceMappings[0].DeviceName = aws.String("/dev/sda1")
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(*node.Status.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("20Gi")))
+			Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeNumerically(">=", 1))
+			awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.BlockDeviceMappings).To(HaveLen(1))
+				Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].DeviceName).To(Equal("/dev/sda1"))
+				Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.SnapshotId).To(Equal("snap-xxxxxxxx"))
+				Expect(*ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.VolumeInitializationRate).To(Equal(int32(100)))
+			})
+		})
 		It("should default to EBS defaults when volumeSize is not defined in blockDeviceMappings for Bottlerocket Root volume", func() {
 			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "bottlerocket@latest"}}
 			nodeClass.Spec.BlockDeviceMappings[0].DeviceName = aws.String("/dev/xvdb")
@@ -2437,6 +2835,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 		It("changes to nodeclass fields should result in a different set of instances types", func() {
 			// We should expect these nodeclass fields to change the result of the instance type
 			// nodeClass.instanceStorePolicy
+			// nodeClass.instanceTypeSelector
 			// nodeClass.amiSelectorTerms (alias)
 			// nodeClass.blockDeviceMapping.rootVolume
 			// nodeClass.blockDeviceMapping.volumeSize
@@ -2451,6 +2850,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 			nodeClassChanges := []*v1.EC2NodeClass{
 				{}, // Testing the base case black EC2NodeClass
 				{Spec: v1.EC2NodeClassSpec{InstanceStorePolicy: lo.ToPtr(v1.InstanceStorePolicyRAID0)}},
+				{Spec: v1.EC2NodeClassSpec{InstanceTypeSelector: &v1.InstanceTypeSelector{ExcludeBareMetal: lo.ToPtr(true)}}},
 				{Spec: v1.EC2NodeClassSpec{AMISelectorTerms: []v1.AMISelectorTerm{{Alias: "bottlerocket@latest"}}}},
 				{
 					Spec: v1.EC2NodeClassSpec{
@@ -80,11 +80,16 @@ func (d *DefaultResolver) CacheKey(nodeClass *v1.EC2NodeClass) string {
 	kcHash, _ := hashstructure.Hash(kc, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	blockDeviceMappingsHash, _ := hashstructure.Hash(nodeClass.Spec.BlockDeviceMappings, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	capacityReservationHash, _ := hashstructure.Hash(nodeClass.Status.CapacityReservations, hashstructure.FormatV2, nil)
+	cpuOptionsHash, _ := hashstructure.Hash(nodeClass.Spec.CPUOptions, hashstructure.FormatV2, nil)
+	instanceTypeSelectorHash, _ := hashstructure.Hash(nodeClass.Spec.InstanceTypeSelector, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	return fmt.Sprintf(
-		"%016x-%016x-%016x-%s-%s",
+		"%016x-%016x-%016x-%016x-%016x-%016x-%s-%s",
 		kcHash,
+		kc.InstanceTypeOverridesHash(),
 		blockDeviceMappingsHash,
 		capacityReservationHash,
+		cpuOptionsHash,
+		instanceTypeSelectorHash,
 		lo.FromPtr((*string)(nodeClass.Spec.InstanceStorePolicy)),
 		nodeClass.AMIFamily(),
 	)
@@ -97,7 +102,12 @@ func (d *DefaultResolver) Resolve(ctx context.Context, info ec2types.InstanceTyp
 	// !!! Important !!!
 	kc := &v1.KubeletConfiguration{}
 	if nodeClass.Spec.Kubelet != nil {
-		kc = nodeClass.Spec.Kubelet
+		family, size, _ := strings.Cut(string(info.InstanceType), ".")
+		kc = nodeClass.Spec.Kubelet.ForInstanceType(family, size, int64(vcpus(info, nodeClass.Spec.CPUOptions)), lo.FromPtr(info.MemoryInfo.SizeInMiB))
+	}
+	// Instance types which can't be launched with the requested CPU options are filtered out
+	if !supportsCPUOptions(info, nodeClass.Spec.CPUOptions) {
+		return nil
 	}
 	return NewInstanceType(
 		ctx,
@@ -107,6 +117,7 @@ func (d *DefaultResolver) Resolve(ctx context.Context, info ec2types.InstanceTyp
 		zonesToZoneIDs,
 		nodeClass.Spec.BlockDeviceMappings,
 		nodeClass.Spec.InstanceStorePolicy,
+		nodeClass.Spec.CPUOptions,
 		kc.MaxPods,
 		kc.PodsPerCore,
 		kc.KubeReserved,
@@ -128,6 +139,7 @@ func NewInstanceType(
 	subnetZonesToZoneIDs map[string]string,
 	blockDeviceMappings []*v1.BlockDeviceMapping,
 	instanceStorePolicy *v1.InstanceStorePolicy,
+	cpuOptions *v1.CPUOptions,
 	maxPods *int32,
 	podsPerCore *int32,
 	kubeReserved map[string]string,
@@ -140,12 +152,12 @@ func NewInstanceType(
 	amiFamily := amifamily.GetAMIFamily(amiFamilyType, &amifamily.Options{})
 	it := &cloudprovider.InstanceType{
 		Name:         string(info.InstanceType),
-		Requirements: computeRequirements(info, region, offeringZones, subnetZonesToZoneIDs, amiFamily, capacityReservations),
-		Capacity:     computeCapacity(ctx, info, amiFamily, blockDeviceMappings, instanceStorePolicy, maxPods, podsPerCore),
+		Requirements: computeRequirements(info, region, offeringZones, subnetZonesToZoneIDs, amiFamily, capacityReservations, cpuOptions),
+		Capacity:     computeCapacity(ctx, info, amiFamily, blockDeviceMappings, instanceStorePolicy, cpuOptions, maxPods, podsPerCore),
 		Overhead: &cloudprovider.InstanceTypeOverhead{
-			KubeReserved:      kubeReservedResources(cpu(info), pods(ctx, info, amiFamily, maxPods, podsPerCore), ENILimitedPods(ctx, info), amiFamily, kubeReserved),
+			KubeReserved:      kubeReservedResources(cpu(info, cpuOptions), pods(ctx, info, amiFamily, cpuOptions, maxPods, podsPerCore), ENILimitedPods(ctx, info), amiFamily, kubeReserved),
 			SystemReserved:    systemReservedResources(systemReserved),
-			EvictionThreshold: evictionThreshold(memory(ctx, info), ephemeralStorage(info, amiFamily, blockDeviceMappings, instanceStorePolicy), amiFamily, evictionHard, evictionSoft),
+			EvictionThreshold: evictionThreshold(memory(ctx, info), ephemeralStorage(info, amiFamily, blockDeviceMappings, instanceStorePolicy, cpuOptions), amiFamily, evictionHard, evictionSoft),
 		},
 	}
 	if it.Requirements.Compatible(scheduling.NewRequirements(scheduling.NewRequirement(corev1.LabelOSStable, corev1.NodeSelectorOpIn, string(corev1.Windows)))) == nil {
@@ -162,6 +174,7 @@ func computeRequirements(
 	subnetZonesToZoneIDs map[string]string,
 	amiFamily amifamily.AMIFamily,
 	capacityReservations []v1.CapacityReservation,
+	cpuOptions *v1.CPUOptions,
 ) scheduling.Requirements {
 	capacityTypes := lo.FilterMap(info.SupportedUsageClasses, func(uc ec2types.UsageClassType, _ int) (string, bool) {
 		if uc != ec2types.UsageClassTypeOnDemand && uc != ec2types.UsageClassTypeSpot {
@@ -187,7 +200,8 @@ func computeRequirements(
 		// Well Known to Karpenter
 		scheduling.NewRequirement(karpv1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, capacityTypes...),
 		// Well Known to AWS
-		scheduling.NewRequirement(v1.LabelInstanceCPU, corev1.NodeSelectorOpIn, fmt.Sprint(lo.FromPtr(info.VCpuInfo.DefaultVCpus))),
+		scheduling.NewRequirement(v1.LabelInstanceCPU, corev1.NodeSelectorOpIn, fmt.Sprint(vcpus(info, cpuOptions))),
+		scheduling.NewRequirement(v1.LabelInstanceThreadsPerCore, corev1.NodeSelectorOpIn, fmt.Sprint(threadsPerCore(info, cpuOptions))),
 		scheduling.NewRequirement(v1.LabelInstanceCPUManufacturer, corev1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1.LabelInstanceCPUSustainedClockSpeedMhz, corev1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1.LabelInstanceMemory, corev1.NodeSelectorOpIn, fmt.Sprint(lo.FromPtr(info.MemoryInfo.SizeInMiB))),
@@ -304,13 +318,13 @@ func getArchitecture(info ec2types.InstanceTypeInfo) string {
 
 func computeCapacity(ctx context.Context, info ec2types.InstanceTypeInfo, amiFamily amifamily.AMIFamily,
 	blockDeviceMapping []*v1.BlockDeviceMapping, instanceStorePolicy *v1.InstanceStorePolicy,
-	maxPods *int32, podsPerCore *int32) corev1.ResourceList {
+	cpuOptions *v1.CPUOptions, maxPods *int32, podsPerCore *int32) corev1.ResourceList {
 
 	resourceList := corev1.ResourceList{
-		corev1.ResourceCPU:              *cpu(info),
+		corev1.ResourceCPU:              *cpu(info, cpuOptions),
 		corev1.ResourceMemory:           *memory(ctx, info),
-		corev1.ResourceEphemeralStorage: *ephemeralStorage(info, amiFamily, blockDeviceMapping, instanceStorePolicy),
-		corev1.ResourcePods:             *pods(ctx, info, amiFamily, maxPods, podsPerCore),
+		corev1.ResourceEphemeralStorage: *ephemeralStorage(info, amiFamily, blockDeviceMapping, instanceStorePolicy, cpuOptions),
+		corev1.ResourcePods:             *pods(ctx, info, amiFamily, cpuOptions, maxPods, podsPerCore),
 		v1.ResourceAWSPodENI:            *awsPodENI(string(info.InstanceType)),
 		v1.ResourceNVIDIAGPU:            *nvidiaGPUs(info),
 		v1.ResourceAMDGPU:               *amdGPUs(info),
@@ -318,12 +332,68 @@ func computeCapacity(ctx context.Context, info ec2types.InstanceTypeInfo, amiFam
 		v1.ResourceAWSNeuronCore:        *awsNeuronCores(info),
 		v1.ResourceHabanaGaudi:          *habanaGaudis(info),
 		v1.ResourceEFA:                  *efas(info),
+		v1.ResourceInstanceLocalNVMe:    *instanceLocalNVMes(info, instanceStorePolicy),
 	}
 	return resourceList
 }
 
-func cpu(info ec2types.InstanceTypeInfo) *resource.Quantity {
-	return resources.Quantity(fmt.Sprint(*info.VCpuInfo.DefaultVCpus))
+func cpu(info ec2types.InstanceTypeInfo, cpuOptions *v1.CPUOptions) *resource.Quantity {
+	return resources.Quantity(fmt.Sprint(vcpus(info, cpuOptions)))
+}
+
+// vcpus returns the number of vCPUs that an instance will have once launched with the given CPU options
+func vcpus(info ec2types.InstanceTypeInfo, cpuOptions *v1.CPUOptions) int32 {
+	if cpuOptions == nil || (cpuOptions.CoreCount == nil && cpuOptions.ThreadsPerCore == nil) {
+		return lo.FromPtr(info.VCpuInfo.DefaultVCpus)
+	}
+	return cores(info, cpuOptions) * threadsPerCore(info, cpuOptions)
+}
+
+func cores(info ec2types.InstanceTypeInfo, cpuOptions *v1.CPUOptions) int32 {
+	if cpuOptions != nil && cpuOptions.CoreCount != nil {
+		return lo.FromPtr(cpuOptions.CoreCount)
+	}
+	if info.VCpuInfo.DefaultCores != nil {
+		return lo.FromPtr(info.VCpuInfo.DefaultCores)
+	}
+	return lo.FromPtr(info.VCpuInfo.DefaultVCpus) / defaultThreadsPerCore(info)
+}
+
+func threadsPerCore(info ec2types.InstanceTypeInfo, cpuOptions *v1.CPUOptions) int32 {
+	if cpuOptions != nil && cpuOptions.ThreadsPerCore != nil {
+		return lo.FromPtr(cpuOptions.ThreadsPerCore)
+	}
+	return defaultThreadsPerCore(info)
+}
+
+func defaultThreadsPerCore(info ec2types.InstanceTypeInfo) int32 {
+	if info.VCpuInfo.DefaultThreadsPerCore != nil {
+		return lo.FromPtr(info.VCpuInfo.DefaultThreadsPerCore)
+	}
+	// Older DescribeInstanceTypes responses don't include the default threads per core, so we derive it from the
+	// default vCPU and core counts
+	if c := lo.FromPtr(info.VCpuInfo.DefaultCores); c != 0 {
+		return lo.FromPtr(info.VCpuInfo.DefaultVCpus) / c
+	}
+	return 1
+}
+
+// supportsCPUOptions returns true if an instance type can be launched with the given CPU options. EC2 only reports
+// valid core and thread counts for instance types which support optimizing CPU options.
+func supportsCPUOptions(info ec2types.InstanceTypeInfo, cpuOptions *v1.CPUOptions) bool {
+	if cpuOptions == nil {
+		return true
+	}
+	if cpuOptions.CoreCount != nil && !lo.Contains(info.VCpuInfo.ValidCores, lo.FromPtr(cpuOptions.CoreCount)) {
+		return false
+	}
+	if cpuOptions.ThreadsPerCore != nil && !lo.Contains(info.VCpuInfo.ValidThreadsPerCore, lo.FromPtr(cpuOptions.ThreadsPerCore)) {
+		return false
+	}
+	if lo.FromPtr(cpuOptions.AMDSEVSNP) == string(ec2types.AmdSevSnpSpecificationEnabled) {
+		return info.ProcessorInfo != nil && lo.Contains(info.ProcessorInfo.SupportedFeatures, ec2types.SupportedAdditionalProcessorFeatureAmdSevSnp)
+	}
+	return true
 }
 
 func memory(ctx context.Context, info ec2types.InstanceTypeInfo) *resource.Quantity {
@@ -339,19 +409,25 @@ func memory(ctx context.Context, info ec2types.InstanceTypeInfo) *resource.Quant
 }
 
 // Setting ephemeral-storage to be either the default value, what is defined in blockDeviceMappings, or the combined size of local store volumes.
-func ephemeralStorage(info ec2types.InstanceTypeInfo, amiFamily amifamily.AMIFamily, blockDeviceMappings []*v1.BlockDeviceMapping, instanceStorePolicy *v1.InstanceStorePolicy) *resource.Quantity {
-	// If local store disks have been configured for node ephemeral-storage, use the total size of the disks.
-	if lo.FromPtr(instanceStorePolicy) == v1.InstanceStorePolicyRAID0 {
-		if info.InstanceStorageInfo != nil && info.InstanceStorageInfo.TotalSizeInGB != nil {
+func ephemeralStorage(info ec2types.InstanceTypeInfo, amiFamily amifamily.AMIFamily, blockDeviceMappings []*v1.BlockDeviceMapping, instanceStorePolicy *v1.InstanceStorePolicy, cpuOptions *v1.CPUOptions) *resource.Quantity {
+	// If local store disks have been configured for node ephemeral-storage, use the usable size of the array. RAID10
+	// mirrors each disk, so only half of the total size is usable.
+	if info.InstanceStorageInfo != nil && info.InstanceStorageInfo.TotalSizeInGB != nil {
+		switch lo.FromPtr(instanceStorePolicy) {
+		case v1.InstanceStorePolicyRAID0:
 			return resources.Quantity(fmt.Sprintf("%dG", *info.InstanceStorageInfo.TotalSizeInGB))
+		case v1.InstanceStorePolicyRAID10:
+			return resources.Quantity(fmt.Sprintf("%dG", *info.InstanceStorageInfo.TotalSizeInGB/2))
 		}
 	}
 	if len(blockDeviceMappings) != 0 {
 		// First check if there's a root volume configured in blockDeviceMappings.
 		if blockDeviceMapping, ok := lo.Find(blockDeviceMappings, func(bdm *v1.BlockDeviceMapping) bool {
 			return bdm.RootVolume
-		}); ok && blockDeviceMapping.EBS.VolumeSize != nil {
-			return blockDeviceMapping.EBS.VolumeSize
+		}); ok && (blockDeviceMapping.EBS.VolumeSize != nil || blockDeviceMapping.EBS.VolumeSizePolicy != nil) {
+			// The root volume's size may be scaled with the instance type. The vCPUs are those of the instance once launched
+			// with the CPU options, which the launch template's root volume is sized by (see the instance-cpu label).
+			return amifamily.ResolveVolumeSize(blockDeviceMapping.EBS, int64(vcpus(info, cpuOptions)), lo.FromPtr(info.MemoryInfo.SizeInMiB))
 		}
 		switch amiFamily.(type) {
 		case *amifamily.Custom:
@@ -450,6 +526,18 @@ func efas(info ec2types.InstanceTypeInfo) *resource.Quantity {
 	return resources.Quantity(fmt.Sprint(count))
 }
 
+// instanceLocalNVMes returns the number of NVMe instance store disks which are mounted individually. Disks are only
+// exposed as a resource when using the Mount instance store policy, since they're otherwise consumed by the node.
+func instanceLocalNVMes(info ec2types.InstanceTypeInfo, instanceStorePolicy *v1.InstanceStorePolicy) *resource.Quantity {
+	count := int32(0)
+	if lo.FromPtr(instanceStorePolicy) == v1.InstanceStorePolicyMount && info.InstanceStorageInfo != nil && info.InstanceStorageInfo.NvmeSupport != ec2types.EphemeralNvmeSupportUnsupported {
+		for _, disk := range info.InstanceStorageInfo.Disks {
+			count += lo.FromPtr(disk.Count)
+		}
+	}
+	return resources.Quantity(fmt.Sprint(count))
+}
+
 func ENILimitedPods(ctx context.Context, info ec2types.InstanceTypeInfo) *resource.Quantity {
 	// The number of pods per node is calculated using the formula:
 	// max number of ENIs * (IPv4 Addresses per ENI -1) + 2
@@ -545,7 +633,7 @@ func evictionThreshold(memory *resource.Quantity, storage *resource.Quantity, am
 	return lo.Assign(overhead, override)
 }
 
-func pods(ctx context.Context, info ec2types.InstanceTypeInfo, amiFamily amifamily.AMIFamily, maxPods *int32, podsPerCore *int32) *resource.Quantity {
+func pods(ctx context.Context, info ec2types.InstanceTypeInfo, amiFamily amifamily.AMIFamily, cpuOptions *v1.CPUOptions, maxPods *int32, podsPerCore *int32) *resource.Quantity {
 	var count int64
 	switch {
 	case maxPods != nil:
@@ -557,7 +645,7 @@ func pods(ctx context.Context, info ec2types.InstanceTypeInfo, amiFamily amifami
 
 	}
 	if lo.FromPtr(podsPerCore) > 0 && amiFamily.FeatureFlags().PodsPerCoreEnabled {
-		count = lo.Min([]int64{int64(lo.FromPtr(podsPerCore)) * int64(lo.FromPtr(info.VCpuInfo.DefaultVCpus)), count})
+		count = lo.Min([]int64{int64(lo.FromPtr(podsPerCore)) * int64(vcpus(info, cpuOptions)), count})
 	}
 	return resources.Quantity(fmt.Sprint(count))
 }
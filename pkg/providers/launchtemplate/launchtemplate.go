@@ -45,7 +45,9 @@ import (
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	awserrors "github.com/aws/karpenter-provider-aws/pkg/errors"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily/bootstrap"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 	"github.com/aws/karpenter-provider-aws/pkg/utils"
@@ -63,6 +65,8 @@ type Provider interface {
 	InvalidateCache(context.Context, string, string)
 	ResolveClusterCIDR(context.Context) error
 	CreateAMIOptions(context.Context, *v1.EC2NodeClass, map[string]string, map[string]string) (*amifamily.Options, error)
+	ResolveAll(context.Context, *v1.EC2NodeClass, *karpv1.NodeClaim, []*cloudprovider.InstanceType, string, map[string]string) ([]*amifamily.LaunchTemplate, error)
+	CreateLaunchTemplateInput(context.Context, *amifamily.LaunchTemplate) (*ec2.CreateLaunchTemplateInput, error)
 }
 type LaunchTemplate struct {
 	Name                  string
@@ -85,6 +89,10 @@ type DefaultProvider struct {
 	ClusterEndpoint       string
 	ClusterCIDR           atomic.Pointer[string]
 	ClusterIPFamily       corev1.IPFamily
+
+	// assumeRoles tracks the role of cached launch templates that were created in another account, so that they're
+	// deleted from that account when they're evicted
+	assumeRoles map[string]*v1.AssumeRole
 }
 
 func NewDefaultProvider(ctx context.Context, cache *cache.Cache, ec2api sdk.EC2API, eksapi sdk.EKSAPI, amiFamily amifamily.Resolver,
@@ -97,6 +105,7 @@ func NewDefaultProvider(ctx context.Context, cache *cache.Cache, ec2api sdk.EC2A
 		securityGroupProvider: securityGroupProvider,
 		subnetProvider:        subnetProvider,
 		cache:                 cache,
+		assumeRoles:           map[string]*v1.AssumeRole{},
 		CABundle:              caBundle,
 		cm:                    pretty.NewChangeMonitor(),
 		KubeDNSIP:             kubeDNSIP,
@@ -126,15 +135,7 @@ func (p *DefaultProvider) EnsureAll(
 	p.Lock()
 	defer p.Unlock()
 
-	opts, err := p.CreateAMIOptions(ctx, nodeClass, lo.Assign(
-		nodeClaim.Labels,
-		scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Labels(), // Inject single-value requirements into userData
-		map[string]string{karpv1.CapacityTypeLabelKey: capacityType},
-	), tags)
-	if err != nil {
-		return nil, err
-	}
-	resolvedLaunchTemplates, err := p.amiFamily.Resolve(nodeClass, nodeClaim, instanceTypes, capacityType, opts)
+	resolvedLaunchTemplates, err := p.ResolveAll(ctx, nodeClass, nodeClaim, instanceTypes, capacityType, tags)
 	if err != nil {
 		return nil, err
 	}
@@ -155,6 +156,26 @@ func (p *DefaultProvider) EnsureAll(
 	return launchTemplates, nil
 }
 
+// ResolveAll resolves the launch templates for the instance types without ensuring that they exist
+func (p *DefaultProvider) ResolveAll(
+	ctx context.Context,
+	nodeClass *v1.EC2NodeClass,
+	nodeClaim *karpv1.NodeClaim,
+	instanceTypes []*cloudprovider.InstanceType,
+	capacityType string,
+	tags map[string]string,
+) ([]*amifamily.LaunchTemplate, error) {
+	opts, err := p.CreateAMIOptions(ctx, nodeClass, lo.Assign(
+		nodeClaim.Labels,
+		scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Labels(), // Inject single-value requirements into userData
+		map[string]string{karpv1.CapacityTypeLabelKey: capacityType},
+	), tags)
+	if err != nil {
+		return nil, err
+	}
+	return p.amiFamily.Resolve(nodeClass, nodeClaim, instanceTypes, capacityType, opts)
+}
+
 // InvalidateCache deletes a launch template from cache if it exists
 func (p *DefaultProvider) InvalidateCache(ctx context.Context, ltName string, ltID string) {
 	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("launch-template-name", ltName, "launch-template-id", ltID))
@@ -164,6 +185,7 @@ func (p *DefaultProvider) InvalidateCache(ctx context.Context, ltName string, lt
 	p.cache.OnEvicted(nil)
 	log.FromContext(ctx).V(1).Info("invalidating launch template in the cache because it no longer exists")
 	p.cache.Delete(ltName)
+	delete(p.assumeRoles, ltName)
 }
 func LaunchTemplateName(options *amifamily.LaunchTemplate) string {
 	return fmt.Sprintf("%s/%d", v1.LaunchTemplateNamePrefix, lo.Must(hashstructure.Hash(options, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})))
@@ -178,12 +200,9 @@ func (p *DefaultProvider) CreateAMIOptions(ctx context.Context, nodeClass *v1.EC
 		}
 	}
 	labels = InjectDoNotSyncTaintsLabel(nodeClass.AMIFamily(), labels)
-	// Relying on the status rather than an API call means that Karpenter is subject to a race
-	// condition where EC2NodeClass spec changes haven't propagated to the status once a node
-	// has launched.
-	// If a user changes their EC2NodeClass and shortly after Karpenter launches a node,
-	// in the worst case, the node could be drifted and re-created.
-	// TODO @aengeda: add status generation fields to gate node creation until the status is updated from a spec change
+	// Relying on the status rather than an API call means that the status may lag a spec change.
+	// The CloudProvider gates launches on status.observedGeneration to avoid launching nodes that
+	// would be immediately drifted.
 	// Get constrained security groups
 	if len(nodeClass.Status.SecurityGroups) == 0 {
 		return nil, fmt.Errorf("no security groups are present in the status")
@@ -194,6 +213,8 @@ func (p *DefaultProvider) CreateAMIOptions(ctx context.Context, nodeClass *v1.EC
 		ClusterCIDR:              p.ClusterCIDR.Load(),
 		InstanceProfile:          nodeClass.Status.InstanceProfile,
 		InstanceStorePolicy:      nodeClass.Spec.InstanceStorePolicy,
+		Bottlerocket:             nodeClass.Spec.Bottlerocket,
+		Registries:               nodeClass.Spec.Registries,
 		SecurityGroups:           nodeClass.Status.SecurityGroups,
 		Tags:                     tags,
 		Labels:                   labels,
@@ -213,6 +234,11 @@ func (p *DefaultProvider) ensureLaunchTemplate(ctx context.Context, options *ami
 		p.cache.SetDefault(name, launchTemplate)
 		return launchTemplate.(ec2types.LaunchTemplate), nil
 	}
+	// Launch template names include the tags of the launch template, which identify the role of other accounts,
+	// so the name of a launch template is never shared across accounts
+	if role := account.FromContext(ctx); role != nil {
+		p.assumeRoles[name] = role
+	}
 	// Attempt to find an existing LT.
 	output, err := p.ec2api.DescribeLaunchTemplates(ctx, &ec2.DescribeLaunchTemplatesInput{
 		LaunchTemplateNames: []string{name},
@@ -238,11 +264,10 @@ func (p *DefaultProvider) ensureLaunchTemplate(ctx context.Context, options *ami
 }
 
 func (p *DefaultProvider) createLaunchTemplate(ctx context.Context, options *amifamily.LaunchTemplate) (ec2types.LaunchTemplate, error) {
-	userData, err := options.UserData.Script()
+	createLaunchTemplateInput, err := p.CreateLaunchTemplateInput(ctx, options)
 	if err != nil {
 		return ec2types.LaunchTemplate{}, err
 	}
-	createLaunchTemplateInput := GetCreateLaunchTemplateInput(ctx, options, p.ClusterIPFamily, userData)
 	output, err := p.ec2api.CreateLaunchTemplate(ctx, createLaunchTemplateInput)
 	if err != nil {
 		return ec2types.LaunchTemplate{}, err
@@ -251,6 +276,18 @@ func (p *DefaultProvider) createLaunchTemplate(ctx context.Context, options *ami
 	return lo.FromPtr(output.LaunchTemplate), nil
 }
 
+// CreateLaunchTemplateInput renders the user data of a resolved launch template and returns the request that creates it
+func (p *DefaultProvider) CreateLaunchTemplateInput(ctx context.Context, options *amifamily.LaunchTemplate) (*ec2.CreateLaunchTemplateInput, error) {
+	userData, err := options.UserData.Script()
+	if err != nil {
+		return nil, err
+	}
+	if err = bootstrap.ValidateUserDataSize(userData); err != nil {
+		return nil, err
+	}
+	return GetCreateLaunchTemplateInput(ctx, options, p.ClusterIPFamily, userData), nil
+}
+
 // you need UserData, AmiID, tags, blockdevicemappings, instance profile,
 func GetCreateLaunchTemplateInput(
 	ctx context.Context,
@@ -275,6 +312,7 @@ func GetCreateLaunchTemplateInput(
 			Monitoring: &ec2types.LaunchTemplatesMonitoringRequest{
 				Enabled: aws.Bool(options.DetailedMonitoring),
 			},
+			CpuOptions: cpuOptions(options.CPUOptions),
 			// If the network interface is defined, the security groups are defined within it
 			SecurityGroupIds: lo.Ternary(networkInterfaces != nil, nil, lo.Map(options.SecurityGroups, func(s v1.SecurityGroup, _ int) string { return s.ID })),
 			UserData:         aws.String(userData),
@@ -357,6 +395,17 @@ func generateNetworkInterfaces(options *amifamily.LaunchTemplate, clusterIPFamil
 	}
 }
 
+func cpuOptions(cpuOptions *v1.CPUOptions) *ec2types.LaunchTemplateCpuOptionsRequest {
+	if cpuOptions == nil {
+		return nil
+	}
+	return &ec2types.LaunchTemplateCpuOptionsRequest{
+		CoreCount:      cpuOptions.CoreCount,
+		ThreadsPerCore: cpuOptions.ThreadsPerCore,
+		AmdSevSnp:      ec2types.AmdSevSnpSpecification(lo.FromPtr(cpuOptions.AMDSEVSNP)),
+	}
+}
+
 func blockDeviceMappings(blockDeviceMappings []*v1.BlockDeviceMapping) []ec2types.LaunchTemplateBlockDeviceMappingRequest {
 	if len(blockDeviceMappings) == 0 {
 		// The EC2 API fails with empty slices and expects nil.
@@ -432,6 +481,8 @@ func (p *DefaultProvider) cachedEvictedFunc(ctx context.Context) func(string, in
 			return
 		}
 		launchTemplate := lt.(ec2types.LaunchTemplate)
+		ctx := account.IntoContext(ctx, p.assumeRoles[key])
+		delete(p.assumeRoles, key)
 		if _, err := p.ec2api.DeleteLaunchTemplate(ctx, &ec2.DeleteLaunchTemplateInput{LaunchTemplateId: launchTemplate.LaunchTemplateId}); awserrors.IgnoreNotFound(err) != nil {
 			log.FromContext(ctx).WithValues("launch-template", launchTemplate.LaunchTemplateName).Error(err, "failed to delete launch template")
 			return
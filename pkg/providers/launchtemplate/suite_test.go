@@ -16,6 +16,7 @@ package launchtemplate_test
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -37,6 +38,7 @@ import (
 	opstatus "github.com/awslabs/operatorpkg/status"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pelletier/go-toml/v2"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -213,6 +215,12 @@ var _ = Describe("LaunchTemplate Provider", func() {
 		Expect(labels).Should(HaveKeyWithValue(karpv1.NodeDoNotSyncTaintsLabelKey, "true"))
 	})
 
+	It("should add the do not sync taints label to nodes when AMI type is ubuntu", func() {
+		labels := launchtemplate.InjectDoNotSyncTaintsLabel("Ubuntu", make(map[string]string))
+		Expect(labels).To(HaveLen(1))
+		Expect(labels).Should(HaveKeyWithValue(karpv1.NodeDoNotSyncTaintsLabelKey, "true"))
+	})
+
 	It("should add the do not sync taints label to nodes when AMI type is br", func() {
 		labels := launchtemplate.InjectDoNotSyncTaintsLabel("Bottlerocket", make(map[string]string))
 		Expect(labels).To(HaveLen(1))
@@ -662,6 +670,42 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				Expect(lo.FromPtr(ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.Iops)).To(Equal(int32(0)))
 			})
 		})
+		It("should default Ubuntu block device mappings", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@latest"}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeNumerically("==", 5))
+			awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(len(ltInput.LaunchTemplateData.BlockDeviceMappings)).To(Equal(1))
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.BlockDeviceMappings[0].DeviceName)).To(Equal("/dev/sda1"))
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize)).To(Equal(int32(20)))
+				Expect(ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.VolumeType).To(Equal(ec2types.VolumeType("gp3")))
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.Iops)).To(Equal(int32(0)))
+			})
+		})
+		It("should use the Ubuntu root device for a custom root volume", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@latest"}}
+			nodeClass.Spec.BlockDeviceMappings = []*v1.BlockDeviceMapping{{
+				DeviceName: aws.String("/dev/sda1"),
+				EBS: &v1.BlockDevice{
+					VolumeSize: lo.ToPtr(resource.MustParse("100Gi")),
+					VolumeType: aws.String("gp3"),
+				},
+				RootVolume: true,
+			}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeNumerically(">=", 1))
+			awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.BlockDeviceMappings).To(HaveLen(1))
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.BlockDeviceMappings[0].DeviceName)).To(Equal("/dev/sda1"))
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize)).To(Equal(int32(100)))
+			})
+		})
 		It("should use custom block device mapping", func() {
 			nodeClass.Spec.BlockDeviceMappings = []*v1.BlockDeviceMapping{
 				{
@@ -809,6 +853,45 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				Expect(lo.FromPtr(ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.KmsKeyId)).To(Equal("arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"))
 			})
 		})
+		It("should size the root volume per instance type when it has a size policy", func() {
+			nodeClass.Spec.BlockDeviceMappings = []*v1.BlockDeviceMapping{
+				{
+					DeviceName: aws.String("/dev/xvda"),
+					EBS: &v1.BlockDevice{
+						VolumeType: aws.String("gp3"),
+						VolumeSizePolicy: &v1.VolumeSizePolicy{
+							PerVCPU: lo.ToPtr(resource.MustParse("10Gi")),
+							Min:     lo.ToPtr(resource.MustParse("30Gi")),
+							Max:     lo.ToPtr(resource.MustParse("200Gi")),
+						},
+					},
+					RootVolume: true,
+				},
+				{
+					DeviceName: aws.String("/dev/xvdb"),
+					EBS: &v1.BlockDevice{
+						VolumeType: aws.String("gp3"),
+						VolumeSize: lo.ToPtr(resource.MustParse("50Gi")),
+					},
+				},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			rootVolumeSizes := sets.New[int32]()
+			awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.BlockDeviceMappings).To(HaveLen(2))
+				rootVolumeSize := lo.FromPtr(ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize)
+				Expect(rootVolumeSize).To(BeNumerically(">=", 30))
+				Expect(rootVolumeSize).To(BeNumerically("<=", 200))
+				Expect(rootVolumeSize % 10).To(BeZero())
+				rootVolumeSizes.Insert(rootVolumeSize)
+				// Only the root volume is scaled
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.BlockDeviceMappings[1].Ebs.VolumeSize)).To(Equal(int32(50)))
+			})
+			Expect(rootVolumeSizes.Len()).To(BeNumerically(">", 1))
+		})
 	})
 	Context("Ephemeral Storage", func() {
 		It("should pack pods when a daemonset has an ephemeral-storage request", func() {
@@ -1052,6 +1135,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				nil,
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodeClass.Spec.Kubelet.MaxPods,
 				nodeClass.Spec.Kubelet.PodsPerCore,
 				nodeClass.Spec.Kubelet.KubeReserved,
@@ -1105,6 +1189,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				nil,
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodeClass.Spec.Kubelet.MaxPods,
 				nodeClass.Spec.Kubelet.PodsPerCore,
 				nodeClass.Spec.Kubelet.KubeReserved,
@@ -1132,6 +1217,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				nil,
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodeClass.Spec.Kubelet.MaxPods,
 				nodeClass.Spec.Kubelet.PodsPerCore,
 				nodeClass.Spec.Kubelet.KubeReserved,
@@ -1146,6 +1232,15 @@ var _ = Describe("LaunchTemplate Provider", func() {
 		})
 	})
 	Context("User Data", func() {
+		It("should bootstrap with bootstrap.sh when using the Ubuntu AMIFamily", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@latest"}}
+			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{MaxPods: aws.Int32(10)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("/etc/eks/bootstrap.sh", "--use-max-pods false", "--max-pods=10")
+		})
 		It("should specify --use-max-pods=false when using ENI-based pod density", func() {
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
 			pod := coretest.UnschedulablePod()
@@ -1228,6 +1323,39 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				}
 			})
 		})
+		It("should apply kubelet instance type overrides to the instance types they match", func() {
+			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+				MaxPods: aws.Int32(10),
+				InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{{
+					InstanceFamilies: []string{"m5"},
+					InstanceSizes:    []string{"xlarge"},
+					MaxPods:          aws.Int32(20),
+					KubeReserved:     map[string]string{string(corev1.ResourceMemory): "2Gi"},
+				}},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{corev1.LabelInstanceTypeStable: "m5.xlarge"}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("--max-pods=20", `--kube-reserved="memory=2Gi"`)
+			ExpectLaunchTemplatesCreatedWithUserDataNotContaining("--max-pods=10")
+		})
+		It("should not apply kubelet instance type overrides to the instance types they don't match", func() {
+			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+				MaxPods: aws.Int32(10),
+				InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{{
+					InstanceSizes: []string{"xlarge"},
+					MaxPods:       aws.Int32(20),
+					KubeReserved:  map[string]string{string(corev1.ResourceMemory): "2Gi"},
+				}},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{corev1.LabelInstanceTypeStable: "m5.large"}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("--max-pods=10")
+			ExpectLaunchTemplatesCreatedWithUserDataNotContaining("--max-pods=20", "--kube-reserved")
+		})
 		It("should pass eviction hard threshold values when specified", func() {
 			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
 				EvictionHard: map[string]string{
@@ -1396,6 +1524,36 @@ var _ = Describe("LaunchTemplate Provider", func() {
 			ExpectScheduled(ctx, env.Client, pod)
 			ExpectLaunchTemplatesCreatedWithUserDataContaining("--cpu-cfs-quota=false")
 		})
+		It("should pass CPU manager, topology manager, log rotation, and image pull flags when specified", func() {
+			nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+				CPUManagerPolicy:          lo.ToPtr("static"),
+				CPUManagerReconcilePeriod: &metav1.Duration{Duration: 5 * time.Second},
+				TopologyManagerPolicy:     lo.ToPtr("single-numa-node"),
+				TopologyManagerScope:      lo.ToPtr("pod"),
+				ContainerLogMaxSize:       lo.ToPtr("50Mi"),
+				ContainerLogMaxFiles:      lo.ToPtr[int32](3),
+				RegistryPullQPS:           lo.ToPtr[int32](10),
+				RegistryBurst:             lo.ToPtr[int32](20),
+				SerializeImagePulls:       lo.ToPtr(false),
+				AllowedUnsafeSysctls:      []string{"net.core.somaxconn", "kernel.msg*"},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining(
+				"--cpu-manager-policy=static",
+				"--cpu-manager-reconcile-period=5s",
+				"--topology-manager-policy=single-numa-node",
+				"--topology-manager-scope=pod",
+				"--container-log-max-size=50Mi",
+				"--container-log-max-files=3",
+				"--registry-qps=10",
+				"--registry-burst=20",
+				"--serialize-image-pulls=false",
+				"--allowed-unsafe-sysctls=net.core.somaxconn,kernel.msg*",
+			)
+		})
 		It("should not pass any labels prefixed with the node-restriction.kubernetes.io domain", func() {
 			nodePool.Spec.Template.Labels = lo.Assign(nodePool.Spec.Template.Labels, map[string]string{
 				corev1.LabelNamespaceNodeRestriction + "/team":                        "team-1",
@@ -1427,6 +1585,38 @@ var _ = Describe("LaunchTemplate Provider", func() {
 			Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeNumerically("==", 5))
 			ExpectLaunchTemplatesCreatedWithUserDataContaining("--local-disks raid0")
 		})
+		DescribeTable("should specify --local-disks for the instance-store policy on AL2", func(policy v1.InstanceStorePolicy, strategy string) {
+			nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(policy)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining(fmt.Sprintf("--local-disks %s", strategy))
+		},
+			Entry("RAID10", v1.InstanceStorePolicyRAID10, "raid10"),
+			Entry("Mount", v1.InstanceStorePolicyMount, "mount"),
+		)
+		It("should not specify --local-disks when the instance-store policy is None on AL2", func() {
+			nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1.InstanceStorePolicyNone)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataNotContaining("--local-disks")
+		})
+		DescribeTable("should specify --local-disks for the instance-store policy on Ubuntu", func(policy v1.InstanceStorePolicy, strategy string) {
+			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@latest"}}
+			nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(policy)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining(fmt.Sprintf("--local-disks %s", strategy))
+		},
+			Entry("RAID0", v1.InstanceStorePolicyRAID0, "raid0"),
+			Entry("RAID10", v1.InstanceStorePolicyRAID10, "raid10"),
+			Entry("Mount", v1.InstanceStorePolicyMount, "mount"),
+		)
 		It("should specify RAID0 bootstrap-command when instance-store policy is set on Bottlerocket", func() {
 			nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "bottlerocket@latest"}}
 			nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1.InstanceStorePolicyRAID0)
@@ -1526,6 +1716,107 @@ essential = true
 				// This will not be scheduled since userData cannot be generated for the prospective node.
 				ExpectNotScheduled(ctx, env.Client, pod)
 			})
+			Context("Typed Settings", func() {
+				type renderedSettings struct {
+					Settings struct {
+						Kernel              bootstrap.BottlerocketKernel                        `toml:"kernel"`
+						ContainerRegistry   bootstrap.BottlerocketContainerRegistry             `toml:"container-registry"`
+						BootstrapContainers map[string]bootstrap.BottlerocketBootstrapContainer `toml:"bootstrap-containers"`
+						HostContainers      map[string]bootstrap.BottlerocketHostContainer      `toml:"host-containers"`
+						NTP                 bootstrap.BottlerocketNTP                           `toml:"ntp"`
+						Kubernetes          bootstrap.BottlerocketKubernetes                    `toml:"kubernetes"`
+					} `toml:"settings"`
+				}
+				expectRenderedSettings := func(assertions func(renderedSettings)) {
+					GinkgoHelper()
+					Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeNumerically(">", 0))
+					awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+						userData, err := base64.StdEncoding.DecodeString(*ltInput.LaunchTemplateData.UserData)
+						Expect(err).To(BeNil())
+						rendered := renderedSettings{}
+						Expect(toml.Unmarshal(userData, &rendered)).To(Succeed())
+						assertions(rendered)
+					})
+				}
+				BeforeEach(func() {
+					nodeClass.Spec.Bottlerocket = &v1.BottlerocketSettings{
+						Kernel: &v1.BottlerocketKernel{
+							Sysctl: map[string]string{"net.core.somaxconn": "1024"},
+						},
+						ContainerRegistry: &v1.BottlerocketContainerRegistry{
+							Mirrors: []v1.BottlerocketRegistryMirror{{
+								Registry:  "docker.io",
+								Endpoints: []string{"https://mirror.example.com"},
+							}},
+						},
+						BootstrapContainers: map[string]v1.BottlerocketBootstrapContainer{
+							"setup": {Source: "public.ecr.aws/example/setup:latest", Essential: lo.ToPtr(true)},
+						},
+						HostContainers: map[string]v1.BottlerocketHostContainer{
+							"admin": {Enabled: lo.ToPtr(true)},
+						},
+						NTP: &v1.BottlerocketNTP{TimeServers: []string{"169.254.169.123"}},
+					}
+				})
+				It("should render typed settings into user data", func() {
+					ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+					pod := coretest.UnschedulablePod()
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+					ExpectScheduled(ctx, env.Client, pod)
+					expectRenderedSettings(func(rendered renderedSettings) {
+						Expect(rendered.Settings.Kernel.Sysctl).To(Equal(map[string]string{"net.core.somaxconn": "1024"}))
+						Expect(rendered.Settings.ContainerRegistry.Mirrors).To(ConsistOf(bootstrap.BottlerocketRegistryMirror{
+							Registry: "docker.io",
+							Endpoint: []string{"https://mirror.example.com"},
+						}))
+						Expect(rendered.Settings.BootstrapContainers).To(HaveKeyWithValue("setup", bootstrap.BottlerocketBootstrapContainer{
+							Source:    "public.ecr.aws/example/setup:latest",
+							Mode:      "always",
+							Essential: lo.ToPtr(true),
+						}))
+						Expect(rendered.Settings.HostContainers).To(HaveKeyWithValue("admin", bootstrap.BottlerocketHostContainer{Enabled: lo.ToPtr(true)}))
+						Expect(rendered.Settings.NTP.TimeServers).To(ConsistOf("169.254.169.123"))
+						Expect(lo.FromPtr(rendered.Settings.Kubernetes.ClusterName)).To(Equal("test-cluster"))
+					})
+				})
+				It("should merge typed settings over user data", func() {
+					nodeClass.Spec.UserData = aws.String(`
+[settings.kernel.sysctl]
+"net.core.somaxconn" = "512"
+"vm.max_map_count" = "262144"
+
+[settings.ntp]
+time-servers = ["time.example.com"]
+
+[settings.host-containers.control]
+enabled = false
+`)
+					ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+					pod := coretest.UnschedulablePod()
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+					ExpectScheduled(ctx, env.Client, pod)
+					expectRenderedSettings(func(rendered renderedSettings) {
+						Expect(rendered.Settings.Kernel.Sysctl).To(Equal(map[string]string{
+							"net.core.somaxconn": "1024",
+							"vm.max_map_count":   "262144",
+						}))
+						Expect(rendered.Settings.NTP.TimeServers).To(ConsistOf("169.254.169.123"))
+						Expect(rendered.Settings.HostContainers).To(HaveKeyWithValue("control", bootstrap.BottlerocketHostContainer{Enabled: lo.ToPtr(false)}))
+						Expect(rendered.Settings.HostContainers).To(HaveKeyWithValue("admin", bootstrap.BottlerocketHostContainer{Enabled: lo.ToPtr(true)}))
+					})
+				})
+				It("should render typed settings deterministically", func() {
+					ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+					pod := coretest.UnschedulablePod()
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+					ExpectScheduled(ctx, env.Client, pod)
+					userData := sets.New[string]()
+					awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+						userData.Insert(lo.FromPtr(ltInput.LaunchTemplateData.UserData))
+					})
+					Expect(userData).To(HaveLen(1))
+				})
+			})
 			It("should override system reserved values in user data", func() {
 				nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
 					SystemReserved: map[string]string{
@@ -1729,6 +2020,44 @@ eviction-max-pod-grace-period = 10
 					Expect(*config.Settings.Kubernetes.CPUCFSQuota).To(BeFalse())
 				})
 			})
+			It("should pass CPU manager, topology manager, shutdown, log rotation, and registry settings when specified", func() {
+				nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+					CPUManagerPolicy:                lo.ToPtr("static"),
+					CPUManagerReconcilePeriod:       &metav1.Duration{Duration: 5 * time.Second},
+					TopologyManagerPolicy:           lo.ToPtr("best-effort"),
+					TopologyManagerScope:            lo.ToPtr("container"),
+					ShutdownGracePeriod:             &metav1.Duration{Duration: time.Minute},
+					ShutdownGracePeriodCriticalPods: &metav1.Duration{Duration: 20 * time.Second},
+					ContainerLogMaxSize:             lo.ToPtr("50Mi"),
+					ContainerLogMaxFiles:            lo.ToPtr[int32](3),
+					RegistryPullQPS:                 lo.ToPtr[int32](10),
+					RegistryBurst:                   lo.ToPtr[int32](20),
+					AllowedUnsafeSysctls:            []string{"net.core.somaxconn"},
+				}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeNumerically("==", 5))
+				awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					userData, err := base64.StdEncoding.DecodeString(*ltInput.LaunchTemplateData.UserData)
+					Expect(err).To(BeNil())
+					config := &bootstrap.BottlerocketConfig{}
+					Expect(config.UnmarshalTOML(userData)).To(Succeed())
+					k := config.Settings.Kubernetes
+					Expect(lo.FromPtr(k.CPUManagerPolicy)).To(Equal("static"))
+					Expect(lo.FromPtr(k.CPUManagerReconcilePeriod)).To(Equal("5s"))
+					Expect(lo.FromPtr(k.TopologyManagerPolicy)).To(Equal("best-effort"))
+					Expect(lo.FromPtr(k.TopologyManagerScope)).To(Equal("container"))
+					Expect(lo.FromPtr(k.ShutdownGracePeriod)).To(Equal("1m0s"))
+					Expect(lo.FromPtr(k.ShutdownGracePeriodForCriticalPods)).To(Equal("20s"))
+					Expect(lo.FromPtr(k.ContainerLogMaxSize)).To(Equal("50Mi"))
+					Expect(lo.FromPtr(k.ContainerLogMaxFiles)).To(Equal(3))
+					Expect(lo.FromPtr(k.RegistryQPS)).To(Equal(10))
+					Expect(lo.FromPtr(k.RegistryBurst)).To(Equal(20))
+					Expect(k.AllowedUnsafeSysctls).To(ConsistOf("net.core.somaxconn"))
+				})
+			})
 			It("should specify labels in the Kubelet flags when specified in NodePool", func() {
 				desiredLabels := map[string]string{
 					"test-label-1": "value-1",
@@ -1831,6 +2160,32 @@ eviction-max-pod-grace-period = 10
 				ExpectLaunchTemplatesCreatedWithUserData(expectedUserData)
 			})
 		})
+		Context("Ubuntu Custom UserData", func() {
+			BeforeEach(func() {
+				nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@latest"}}
+				nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{MaxPods: lo.ToPtr[int32](110)}
+			})
+			// Canonical's EKS AMIs bootstrap with the same bootstrap.sh as AL2, so they share the AL2 golden files
+			DescribeTable("should merge in custom user data", func(inputFile *string, mergedFile string) {
+				if inputFile != nil {
+					content, err := os.ReadFile("testdata/" + *inputFile)
+					Expect(err).To(BeNil())
+					nodeClass.Spec.UserData = aws.String(string(content))
+				}
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				content, err := os.ReadFile("testdata/" + mergedFile)
+				Expect(err).To(BeNil())
+				ExpectLaunchTemplatesCreatedWithUserData(fmt.Sprintf(string(content), nodeClass.Name, karpv1.NodePoolLabelKey, nodePool.Name))
+			},
+				Entry("MIME", lo.ToPtr("al2_userdata_input.golden"), "al2_userdata_merged.golden"),
+				Entry("MIME with Content-Type first", lo.ToPtr("al2_userdata_content_type_first_input.golden"), "al2_userdata_merged.golden"),
+				Entry("shell", lo.ToPtr("al2_no_mime_userdata_input.golden"), "al2_userdata_merged.golden"),
+				Entry("empty", nil, "al2_userdata_unmerged.golden"),
+			)
+		})
 		Context("AL2023", func() {
 			BeforeEach(func() {
 				nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "al2023@latest"}}
@@ -2017,7 +2372,57 @@ eviction-max-pod-grace-period = 10
 					Entry("cpuCFSQuota", "cpuCFSQuota", v1.KubeletConfiguration{
 						CPUCFSQuota: lo.ToPtr(false),
 					}),
+					Entry("cpuManagerPolicy", "cpuManagerPolicy", v1.KubeletConfiguration{
+						CPUManagerPolicy: lo.ToPtr("static"),
+					}),
+					Entry("topologyManagerPolicy", "topologyManagerPolicy", v1.KubeletConfiguration{
+						TopologyManagerPolicy: lo.ToPtr("restricted"),
+					}),
+					Entry("shutdownGracePeriod", "shutdownGracePeriod", v1.KubeletConfiguration{
+						ShutdownGracePeriod: &metav1.Duration{Duration: time.Minute},
+					}),
+					Entry("shutdownGracePeriodCriticalPods", "shutdownGracePeriodCriticalPods", v1.KubeletConfiguration{
+						ShutdownGracePeriod:             &metav1.Duration{Duration: time.Minute},
+						ShutdownGracePeriodCriticalPods: &metav1.Duration{Duration: 20 * time.Second},
+					}),
+					Entry("containerLogMaxSize", "containerLogMaxSize", v1.KubeletConfiguration{
+						ContainerLogMaxSize: lo.ToPtr("50Mi"),
+					}),
+					Entry("registryPullQPS", "registryPullQPS", v1.KubeletConfiguration{
+						RegistryPullQPS: lo.ToPtr[int32](10),
+					}),
+					Entry("serializeImagePulls", "serializeImagePulls", v1.KubeletConfiguration{
+						SerializeImagePulls: lo.ToPtr(false),
+					}),
+					Entry("maxParallelImagePulls", "maxParallelImagePulls", v1.KubeletConfiguration{
+						SerializeImagePulls:   lo.ToPtr(false),
+						MaxParallelImagePulls: lo.ToPtr[int32](5),
+					}),
+					Entry("allowedUnsafeSysctls", "allowedUnsafeSysctls", v1.KubeletConfiguration{
+						AllowedUnsafeSysctls: []string{"net.core.somaxconn"},
+					}),
 				)
+				It("should resolve kubelet instance type overrides rather than passing them to nodeadm", func() {
+					nodeClass.Spec.Kubelet = &v1.KubeletConfiguration{
+						InstanceTypeOverrides: []v1.KubeletInstanceTypeOverride{{
+							MinVCPU:        lo.ToPtr[int32](4),
+							MaxVCPU:        lo.ToPtr[int32](4),
+							MaxPods:        lo.ToPtr[int32](20),
+							SystemReserved: map[string]string{string(corev1.ResourceMemory): "1Gi"},
+						}},
+					}
+					ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+					pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{corev1.LabelInstanceTypeStable: "m5.xlarge"}})
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+					ExpectScheduled(ctx, env.Client, pod)
+					for _, userData := range ExpectUserDataExistsFromCreatedLaunchTemplates() {
+						configs := ExpectUserDataCreatedWithNodeConfigs(userData)
+						Expect(len(configs)).To(Equal(1))
+						Expect(configs[0].Spec.Kubelet.Config).ToNot(HaveKey("instanceTypeOverrides"))
+						Expect(configs[0].Spec.Kubelet.Config["maxPods"]).To(Equal(runtime.RawExtension{Raw: []byte("20")}))
+						Expect(configs[0].Spec.Kubelet.Config["systemReserved"]).To(Equal(runtime.RawExtension{Raw: []byte(`{"memory":"1Gi"}`)}))
+					}
+				})
 			})
 			It("should set LocalDiskStrategy to Raid0 when specified by the InstanceStorePolicy", func() {
 				nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1.InstanceStorePolicyRAID0)
@@ -2031,6 +2436,22 @@ eviction-max-pod-grace-period = 10
 					Expect(configs[0].Spec.Instance.LocalStorage.Strategy).To(Equal(admv1alpha1.LocalStorageRAID0))
 				}
 			})
+			DescribeTable("should set LocalDiskStrategy for the InstanceStorePolicy", func(policy v1.InstanceStorePolicy, strategy admv1alpha1.LocalStorageStrategy) {
+				nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(policy)
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				for _, userData := range ExpectUserDataExistsFromCreatedLaunchTemplates() {
+					configs := ExpectUserDataCreatedWithNodeConfigs(userData)
+					Expect(len(configs)).To(Equal(1))
+					Expect(configs[0].Spec.Instance.LocalStorage.Strategy).To(Equal(strategy))
+				}
+			},
+				Entry("RAID10", v1.InstanceStorePolicyRAID10, admv1alpha1.LocalStorageStrategy("RAID10")),
+				Entry("Mount", v1.InstanceStorePolicyMount, admv1alpha1.LocalStorageMount),
+				Entry("None", v1.InstanceStorePolicyNone, admv1alpha1.LocalStorageStrategy("")),
+			)
 			DescribeTable(
 				"should merge custom user data",
 				func(inputFile *string, mergedFile string) {
@@ -2200,7 +2621,7 @@ eviction-max-pod-grace-period = 10
 				nodeClass.Spec.AMIFamily = lo.ToPtr(v1.AMIFamilyCustom)
 				nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Tags: map[string]string{"*": "*"}}}
 				ExpectApplied(ctx, env.Client, nodeClass)
-				controller := nodeclass.NewController(awsEnv.Clock, env.Client, cloudProvider, recorder, fake.DefaultRegion, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.InstanceTypesProvider, awsEnv.LaunchTemplateProvider, awsEnv.CapacityReservationProvider, awsEnv.EC2API, awsEnv.ValidationCache, awsEnv.AMIResolver)
+				controller := nodeclass.NewController(awsEnv.Clock, env.Client, cloudProvider, recorder, fake.DefaultRegion, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.InstanceTypesProvider, awsEnv.LaunchTemplateProvider, awsEnv.CapacityReservationProvider, awsEnv.PermissionProvider, awsEnv.EC2API, awsEnv.KMSAPI, awsEnv.ValidationCache, awsEnv.AMIResolver)
 				ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 				nodePool.Spec.Template.Spec.Requirements = []karpv1.NodeSelectorRequirementWithMinValues{
 					{
@@ -2306,6 +2727,246 @@ eviction-max-pod-grace-period = 10
 				ExpectLaunchTemplatesCreatedWithUserDataContaining("--dns-cluster-ip '10.0.10.100'")
 			})
 		})
+		Context("Registries", func() {
+			BeforeEach(func() {
+				nodeClass.Spec.Registries = &v1.RegistryConfiguration{
+					Mirrors: []v1.RegistryMirror{
+						{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}},
+						{Registry: "*", Endpoints: []string{"http://mirror.internal:5000"}},
+					},
+					CredentialProviders: []v1.CredentialProvider{{
+						Name:        "registry-credential-provider",
+						MatchImages: []string{"*.registry.example.com"},
+						Env:         map[string]string{"REGISTRY_REGION": "us-west-2"},
+					}},
+				}
+			})
+			It("should write containerd hosts and the credential provider config when using AL2", func() {
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(
+					"cat > '/etc/containerd/certs.d/docker.io/hosts.toml'",
+					`[host."https://mirror.example.com"]`,
+					"cat > '/etc/containerd/certs.d/_default/hosts.toml'",
+					`[host."http://mirror.internal:5000"]`,
+					`"name": "ecr-credential-provider"`,
+					`"name": "registry-credential-provider"`,
+					`"name": "REGISTRY_REGION"`,
+					"--image-credential-provider-config=/etc/karpenter/image-credential-provider/config.json",
+				)
+			})
+			It("should write containerd hosts and the credential provider config when using Ubuntu", func() {
+				nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "ubuntu@latest"}}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(
+					"cat > '/etc/containerd/certs.d/docker.io/hosts.toml'",
+					`[host."https://mirror.example.com"]`,
+					`"name": "registry-credential-provider"`,
+					"--image-credential-provider-config=/etc/karpenter/image-credential-provider/config.json",
+				)
+			})
+			It("should not configure the default ECR credential provider when it is overridden", func() {
+				nodeClass.Spec.Registries.CredentialProviders = []v1.CredentialProvider{{
+					Name:        "ecr-credential-provider",
+					MatchImages: []string{"123456789012.dkr.ecr.us-west-2.amazonaws.com"},
+				}}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(`"123456789012.dkr.ecr.us-west-2.amazonaws.com"`)
+				ExpectLaunchTemplatesCreatedWithUserDataNotContaining(`"*.dkr.ecr.*.amazonaws.com"`)
+			})
+			It("should write containerd hosts through a shell script and keep the AMI's containerd config when using AL2023", func() {
+				nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "al2023@latest"}}
+				awsEnv.LaunchTemplateProvider.CABundle = lo.ToPtr("Y2EtYnVuZGxlCg==")
+				awsEnv.LaunchTemplateProvider.ClusterCIDR.Store(lo.ToPtr("10.100.0.0/16"))
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				for _, userData := range ExpectUserDataExistsFromCreatedLaunchTemplates() {
+					archive, err := mime.NewArchive(userData)
+					Expect(err).To(BeNil())
+					scripts := lo.Filter(archive, func(entry mime.Entry, _ int) bool { return entry.ContentType == mime.ContentTypeShellScript })
+					Expect(scripts).To(HaveLen(1))
+					Expect(scripts[0].Content).To(ContainSubstring("cat > '/etc/containerd/certs.d/docker.io/hosts.toml'"))
+					Expect(scripts[0].Content).To(ContainSubstring(`"name": "registry-credential-provider"`))
+					configs := ExpectUserDataCreatedWithNodeConfigs(userData)
+					Expect(configs[0].Spec.Kubelet.Flags).To(ContainElement("--image-credential-provider-config=/etc/karpenter/image-credential-provider/config.json"))
+					// Leaving the containerd config untouched keeps config_path, so certs.d hosts from the AMI or other UserData are still read
+					Expect(configs[0].Spec.Containerd.Config).To(BeEmpty())
+				}
+			})
+			It("should configure container registry mirrors and credential providers when using Bottlerocket", func() {
+				nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "bottlerocket@latest"}}
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					userData, err := base64.StdEncoding.DecodeString(*ltInput.LaunchTemplateData.UserData)
+					Expect(err).To(BeNil())
+					rendered := struct {
+						Settings struct {
+							ContainerRegistry bootstrap.BottlerocketContainerRegistry `toml:"container-registry"`
+							Kubernetes        bootstrap.BottlerocketKubernetes        `toml:"kubernetes"`
+						} `toml:"settings"`
+					}{}
+					Expect(toml.Unmarshal(userData, &rendered)).To(Succeed())
+					Expect(rendered.Settings.ContainerRegistry.Mirrors).To(ConsistOf(
+						bootstrap.BottlerocketRegistryMirror{Registry: "docker.io", Endpoint: []string{"https://mirror.example.com"}},
+						bootstrap.BottlerocketRegistryMirror{Registry: "*", Endpoint: []string{"http://mirror.internal:5000"}},
+					))
+					Expect(rendered.Settings.Kubernetes.CredentialProviders).To(HaveKeyWithValue("registry-credential-provider", bootstrap.BottlerocketCredentialProvider{
+						Enabled:       lo.ToPtr(true),
+						CacheDuration: lo.ToPtr("12h0m0s"),
+						ImagePatterns: []string{"*.registry.example.com"},
+						Environment:   map[string]string{"REGISTRY_REGION": "us-west-2"},
+					}))
+				})
+			})
+			It("should write containerd hosts when using Windows", func() {
+				nodeClass.Spec.Registries.CredentialProviders = nil
+				nodePool.Spec.Template.Spec.Requirements = []karpv1.NodeSelectorRequirementWithMinValues{{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelOSStable, Operator: corev1.NodeSelectorOpIn, Values: []string{string(corev1.Windows)}}}}
+				nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "windows2022@latest"}}
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod(coretest.PodOptions{
+					NodeSelector: map[string]string{
+						corev1.LabelOSStable:     string(corev1.Windows),
+						corev1.LabelWindowsBuild: "10.0.20348",
+					},
+				})
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(
+					`Set-Content -Path "$env:ProgramFiles\containerd\certs.d\docker.io\hosts.toml"`,
+					`Set-Content -Path "$env:ProgramFiles\containerd\certs.d\_default\hosts.toml"`,
+					`[host."https://mirror.example.com"]`,
+				)
+			})
+		})
+		Context("Templated UserData", func() {
+			BeforeEach(func() {
+				nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho \"nodepool={{ .NodePoolName }} nodeclass={{ .NodeClassName }} capacity-type={{ .CapacityType | upper }}\"\n")
+			})
+			It("should render userData when templating is enabled", func() {
+				nodeClass.Spec.TemplateUserData = lo.ToPtr(true)
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(fmt.Sprintf(`echo "nodepool=%s nodeclass=%s capacity-type=ON-DEMAND"`, nodePool.Name, nodeClass.Name))
+			})
+			It("should render the resolved AMI ID into userData", func() {
+				nodeClass.Spec.TemplateUserData = lo.ToPtr(true)
+				nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho \"ami={{ .AMIID }}\"\n")
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+					userData, err := base64.StdEncoding.DecodeString(*ltInput.LaunchTemplateData.UserData)
+					Expect(err).To(BeNil())
+					Expect(string(userData)).To(ContainSubstring(fmt.Sprintf(`echo "ami=%s"`, *ltInput.LaunchTemplateData.ImageId)))
+				})
+			})
+			It("should render the zone into userData when the NodeClaim is constrained to a single zone", func() {
+				nodeClass.Spec.TemplateUserData = lo.ToPtr(true)
+				nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho \"zone={{ .Zone }}\"\n")
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{corev1.LabelTopologyZone: "test-zone-1b"}})
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(`echo "zone=test-zone-1b"`)
+			})
+			It("should render the launch template's instance types into userData", func() {
+				nodeClass.Spec.TemplateUserData = lo.ToPtr(true)
+				nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho \"instance-types={{ join \",\" .InstanceTypes }}\"\n")
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{corev1.LabelInstanceTypeStable: "m5.large"}})
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(`echo "instance-types=m5.large"`)
+			})
+			It("should not resolve additional launch templates when templating is enabled", func() {
+				its, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+				Expect(err).To(BeNil())
+				nodeClaim := coretest.NodeClaim()
+				untemplated, err := awsEnv.AMIResolver.Resolve(nodeClass, nodeClaim, its, karpv1.CapacityTypeOnDemand, &amifamily.Options{})
+				Expect(err).To(BeNil())
+
+				nodeClass.Spec.TemplateUserData = lo.ToPtr(true)
+				nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho \"instance-types={{ join \",\" .InstanceTypes }}\"\n")
+				templated, err := awsEnv.AMIResolver.Resolve(nodeClass, nodeClaim, its, karpv1.CapacityTypeOnDemand, &amifamily.Options{})
+				Expect(err).To(BeNil())
+				Expect(templated).To(HaveLen(len(untemplated)))
+			})
+			It("should pass userData through verbatim when templating is disabled", func() {
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining(`echo "nodepool={{ .NodePoolName }} nodeclass={{ .NodeClassName }} capacity-type={{ .CapacityType | upper }}"`)
+			})
+			It("should fail to launch when the userData template fails to render", func() {
+				nodeClass.Spec.TemplateUserData = lo.ToPtr(true)
+				nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho {{ .NodePool }}\n")
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectNotScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeZero())
+			})
+		})
+		Context("UserData Size", func() {
+			It("should compress userData parts when userData is close to the EC2 limit on AL2", func() {
+				nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\n" + strings.Repeat("echo 'configuring node'\n", 600))
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining("Content-Type: application/x-gzip", "Content-Transfer-Encoding: base64")
+				ExpectLaunchTemplatesCreatedWithUserDataNotContaining("/etc/eks/bootstrap.sh", "echo 'configuring node'")
+			})
+			It("should compress userData parts other than the NodeConfig when userData is close to the EC2 limit on AL2023", func() {
+				awsEnv.LaunchTemplateProvider.CABundle = lo.ToPtr("Y2EtYnVuZGxlCg==")
+				awsEnv.LaunchTemplateProvider.ClusterCIDR.Store(lo.ToPtr("10.100.0.0/16"))
+				nodeClass.Spec.AMISelectorTerms = []v1.AMISelectorTerm{{Alias: "al2023@latest"}}
+				nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\n" + strings.Repeat("echo 'configuring node'\n", 600))
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining("Content-Type: application/x-gzip", "Content-Type: application/node.eks.aws", "kind: NodeConfig")
+				ExpectLaunchTemplatesCreatedWithUserDataNotContaining("echo 'configuring node'")
+			})
+			It("should not compress userData when it's well within the EC2 limit", func() {
+				nodeClass.Spec.UserData = lo.ToPtr("#!/bin/bash\necho 'configuring node'\n")
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				ExpectLaunchTemplatesCreatedWithUserDataContaining("/etc/eks/bootstrap.sh", "echo 'configuring node'")
+				ExpectLaunchTemplatesCreatedWithUserDataNotContaining("application/x-gzip")
+			})
+			It("should not create launch templates when userData exceeds the EC2 limit after compression", func() {
+				content := make([]byte, 24*1024)
+				_, err := rand.Read(content)
+				Expect(err).ToNot(HaveOccurred())
+				nodeClass.Spec.UserData = lo.ToPtr(fmt.Sprintf("#!/bin/bash\necho '%s'\n", base64.StdEncoding.EncodeToString(content)))
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectNotScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeZero())
+			})
+		})
 		Context("Windows Custom UserData", func() {
 			BeforeEach(func() {
 				nodePool.Spec.Template.Spec.Requirements = []karpv1.NodeSelectorRequirementWithMinValues{{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelOSStable, Operator: corev1.NodeSelectorOpIn, Values: []string{string(corev1.Windows)}}}}
@@ -2347,6 +3008,57 @@ eviction-max-pod-grace-period = 10
 			})
 		})
 	})
+	Context("AMI Rollout", func() {
+		BeforeEach(func() {
+			nodeClass.Spec.AMIRolloutPolicy = &v1.AMIRolloutPolicy{SoakDuration: metav1.Duration{Duration: time.Hour}}
+			nodeClass.Status.AMIRollout = &v1.AMIRollout{
+				CandidateAMIs: lo.Map(nodeClass.Status.AMIs, func(ami v1.AMI, _ int) v1.AMI {
+					ami.ID = fmt.Sprintf("%s-candidate", ami.ID)
+					return ami
+				}),
+				SoakStartTime: metav1.Now(),
+			}
+		})
+		It("should launch with candidate AMIs when the canary percentage is 100", func() {
+			nodeClass.Spec.AMIRolloutPolicy.CanaryPercentage = lo.ToPtr[int32](100)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeNumerically(">", 0))
+			awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.ImageId)).To(HaveSuffix("-candidate"))
+			})
+		})
+		It("should launch with stable AMIs when the canary percentage is 0", func() {
+			nodeClass.Spec.AMIRolloutPolicy.CanaryPercentage = lo.ToPtr[int32](0)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.Len()).To(BeNumerically(">", 0))
+			awsEnv.EC2API.CreateLaunchTemplateBehavior.CalledWithInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(lo.FromPtr(ltInput.LaunchTemplateData.ImageId)).ToNot(HaveSuffix("-candidate"))
+			})
+		})
+		It("should consistently select AMIs for a NodeClaim", func() {
+			nodeClass.Spec.AMIRolloutPolicy.CanaryPercentage = lo.ToPtr[int32](50)
+			its, err := awsEnv.InstanceTypesProvider.List(ctx, nodeClass)
+			Expect(err).To(BeNil())
+			its = lo.Filter(its, func(it *corecloudprovider.InstanceType, _ int) bool { return it.Name == "m5.large" })
+			Expect(its).To(HaveLen(1))
+			for i := 0; i < 10; i++ {
+				nodeClaim := coretest.NodeClaim()
+				var amiIDs []string
+				for j := 0; j < 5; j++ {
+					lts, err := awsEnv.AMIResolver.Resolve(nodeClass, nodeClaim, its, karpv1.CapacityTypeOnDemand, &amifamily.Options{})
+					Expect(err).To(BeNil())
+					amiIDs = append(amiIDs, lo.Map(lts, func(lt *amifamily.LaunchTemplate, _ int) string { return lt.AMIID })...)
+				}
+				Expect(lo.Uniq(amiIDs)).To(HaveLen(1))
+			}
+		})
+	})
 	Context("Detailed Monitoring", func() {
 		It("should default detailed monitoring to off", func() {
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
@@ -0,0 +1,227 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permission
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
+	awserrors "github.com/aws/karpenter-provider-aws/pkg/errors"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
+)
+
+const (
+	principalCacheKey = "principal"
+)
+
+// dryRuns authorize checks with a dry run of the request rather than by simulating the controller's policies. EC2
+// evaluates dry runs against the policies of the caller, including callers in other accounts.
+var dryRuns = map[string]func(context.Context, sdk.EC2API) error{
+	"ec2:DescribeCapacityReservations": func(ctx context.Context, ec2api sdk.EC2API) error {
+		// Adding NopRetryer to avoid aggressive retry when rate limited
+		_, err := ec2api.DescribeCapacityReservations(ctx, &ec2.DescribeCapacityReservationsInput{DryRun: lo.ToPtr(true)}, func(o *ec2.Options) {
+			o.Retryer = aws.NopRetryer{}
+		})
+		return err
+	},
+}
+
+// Check is an action that the controller needs to be authorized to perform on a resource
+type Check struct {
+	Action string
+	// Resource is either an ARN, "*", or a resource relative to the account and region of the controller
+	// (e.g. "volume/*"), which is resolved to an ARN in the service of the action
+	Resource string
+	// Context is the condition context that the request is made with, e.g. the tags that are applied
+	Context map[string][]string
+	// ResourceBasedPolicy is true when the resource can grant access with its own policy (e.g. KMS key policies),
+	// which isn't considered when simulating the controller's policies. Checks for these resources are only reported
+	// as missing if they're explicitly denied.
+	ResourceBasedPolicy bool
+}
+
+func (c Check) String() string {
+	return fmt.Sprintf("%s on %s", c.Action, c.Resource)
+}
+
+type Provider interface {
+	Missing(context.Context, ...Check) ([]Check, error)
+}
+
+// DefaultProvider simulates the IAM policies of the controller's principal. Simulation is best effort: if the
+// controller isn't authorized to simulate its own policies, no checks are reported as missing.
+type DefaultProvider struct {
+	sync.Mutex
+	ec2api sdk.EC2API
+	iamapi sdk.IAMAPI
+	stsapi sdk.STSAPI
+	region string
+	cache  *cache.Cache
+}
+
+func NewDefaultProvider(ec2api sdk.EC2API, iamapi sdk.IAMAPI, stsapi sdk.STSAPI, region string, cache *cache.Cache) *DefaultProvider {
+	return &DefaultProvider{
+		ec2api: ec2api,
+		iamapi: iamapi,
+		stsapi: stsapi,
+		region: region,
+		cache:  cache,
+	}
+}
+
+// Missing returns the checks which the controller isn't authorized to perform. Policies of other accounts can't be
+// simulated, so only dry run checks are made with a context that's scoped to an assumed role.
+func (p *DefaultProvider) Missing(ctx context.Context, checks ...Check) ([]Check, error) {
+	var missing []Check
+	var simulated []Check
+	for _, check := range checks {
+		dryRun, ok := dryRuns[check.Action]
+		if !ok {
+			simulated = append(simulated, check)
+			continue
+		}
+		if err := dryRun(ctx, p.ec2api); awserrors.IgnoreDryRunError(err) != nil {
+			if awserrors.IgnoreUnauthorizedOperationError(err) != nil {
+				return nil, fmt.Errorf("validating %s authorization, %w", check.Action, err)
+			}
+			missing = append(missing, check)
+		}
+	}
+	if len(simulated) == 0 || account.FromContext(ctx) != nil {
+		return missing, nil
+	}
+	principal, err := p.principal(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting caller identity, %w", err)
+	}
+	var denied []Check
+	for _, check := range simulated {
+		allowed, err := p.allowed(ctx, principal, check)
+		// The path of a role isn't included in the ARN of its sessions, so roles with paths aren't found
+		if awserrors.IsIAMAccessDenied(err) || awserrors.IsNotFound(err) {
+			log.FromContext(ctx).WithValues("principal", principal.String()).V(1).Info("unable to simulate controller policies, skipping permission checks")
+			return missing, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("simulating %s, %w", check, err)
+		}
+		if !allowed {
+			denied = append(denied, check)
+		}
+	}
+	return append(missing, denied...), nil
+}
+
+func (p *DefaultProvider) allowed(ctx context.Context, principal arn.ARN, check Check) (bool, error) {
+	out, err := p.iamapi.SimulatePrincipalPolicy(ctx, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: lo.ToPtr(principal.String()),
+		ActionNames:     []string{check.Action},
+		ResourceArns:    []string{p.resourceARN(principal, check)},
+		ContextEntries:  contextEntries(check.Context),
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, result := range out.EvaluationResults {
+		switch result.EvalDecision {
+		case iamtypes.PolicyEvaluationDecisionTypeAllowed:
+			continue
+		case iamtypes.PolicyEvaluationDecisionTypeImplicitDeny:
+			// The result is inconclusive if the decision depends on context that isn't known until the request is made,
+			// or on a resource-based policy that isn't simulated
+			if len(result.MissingContextValues) > 0 || check.ResourceBasedPolicy {
+				continue
+			}
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// principal returns the ARN of the controller's IAM principal. Policies are attached to the role, rather than the
+// role's session that's returned by GetCallerIdentity.
+func (p *DefaultProvider) principal(ctx context.Context) (arn.ARN, error) {
+	p.Lock()
+	defer p.Unlock()
+	if principal, ok := p.cache.Get(principalCacheKey); ok {
+		return principal.(arn.ARN), nil
+	}
+	out, err := p.stsapi.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return arn.ARN{}, err
+	}
+	principal, err := arn.Parse(lo.FromPtr(out.Arn))
+	if err != nil {
+		return arn.ARN{}, fmt.Errorf("parsing caller identity arn, %w", err)
+	}
+	if parts := strings.Split(principal.Resource, "/"); principal.Service == "sts" && parts[0] == "assumed-role" && len(parts) == 3 {
+		principal = arn.ARN{Partition: principal.Partition, Service: "iam", AccountID: principal.AccountID, Resource: fmt.Sprintf("role/%s", parts[1])}
+	}
+	p.cache.SetDefault(principalCacheKey, principal)
+	return principal, nil
+}
+
+// resourceARN resolves the resource of the check to an ARN in the service of the check's action
+func (p *DefaultProvider) resourceARN(principal arn.ARN, check Check) string {
+	if check.Resource == "*" || arn.IsARN(check.Resource) {
+		return check.Resource
+	}
+	service, _, _ := strings.Cut(check.Action, ":")
+	return arn.ARN{
+		Partition: principal.Partition,
+		Service:   service,
+		// IAM resources are global
+		Region: lo.Ternary(service == "iam", "", p.region),
+		// Public SSM parameters are owned by AWS rather than the account
+		AccountID: lo.Ternary(service == "ssm" && strings.HasPrefix(check.Resource, "parameter/aws/"), "", principal.AccountID),
+		Resource:  check.Resource,
+	}.String()
+}
+
+// RequestTagContext returns the condition context of a request that applies the tags
+func RequestTagContext(tags map[string]string) map[string][]string {
+	keys := lo.Keys(tags)
+	sort.Strings(keys)
+	return lo.Assign(
+		lo.MapEntries(tags, func(k, v string) (string, []string) { return fmt.Sprintf("aws:RequestTag/%s", k), []string{v} }),
+		map[string][]string{"aws:TagKeys": keys},
+	)
+}
+
+func contextEntries(context map[string][]string) []iamtypes.ContextEntry {
+	keys := lo.Keys(context)
+	sort.Strings(keys)
+	return lo.Map(keys, func(key string, _ int) iamtypes.ContextEntry {
+		return iamtypes.ContextEntry{
+			ContextKeyName:   lo.ToPtr(key),
+			ContextKeyValues: context[key],
+			ContextKeyType:   lo.Ternary(len(context[key]) > 1 || key == "aws:TagKeys", iamtypes.ContextKeyTypeEnumStringList, iamtypes.ContextKeyTypeEnumString),
+		}
+	})
+}
@@ -0,0 +1,211 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permission_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+
+	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
+	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/permission"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var ec2api *fake.EC2API
+var iamapi *fake.IAMAPI
+var stsapi *fake.STSAPI
+var permissionProvider *permission.DefaultProvider
+
+func TestPermission(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Permission")
+}
+
+var _ = BeforeEach(func() {
+	ec2api = fake.NewEC2API()
+	iamapi = fake.NewIAMAPI()
+	stsapi = fake.NewSTSAPI()
+	permissionProvider = permission.NewDefaultProvider(ec2api, iamapi, stsapi, fake.DefaultRegion, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+})
+
+var _ = Describe("Permission", func() {
+	It("should not report any checks as missing when all actions are allowed", func() {
+		missing, err := permissionProvider.Missing(ctx,
+			permission.Check{Action: "ec2:CreateTags", Resource: "volume/*"},
+			permission.Check{Action: "iam:PassRole", Resource: "role/KarpenterNodeRole"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+		Expect(iamapi.SimulatePrincipalPolicyBehavior.Calls()).To(Equal(2))
+	})
+	It("should report the checks of denied actions as missing", func() {
+		iamapi.DeniedActions.Insert("iam:PassRole")
+		missing, err := permissionProvider.Missing(ctx,
+			permission.Check{Action: "ec2:CreateTags", Resource: "volume/*"},
+			permission.Check{Action: "iam:PassRole", Resource: "role/KarpenterNodeRole"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(HaveLen(1))
+		Expect(missing[0].String()).To(Equal("iam:PassRole on role/KarpenterNodeRole"))
+	})
+	It("should simulate the policies of the role rather than the role's session", func() {
+		_, err := permissionProvider.Missing(ctx, permission.Check{Action: "ec2:CreateTags", Resource: "volume/*"})
+		Expect(err).ToNot(HaveOccurred())
+		input := iamapi.SimulatePrincipalPolicyBehavior.CalledWithInput.Pop()
+		Expect(lo.FromPtr(input.PolicySourceArn)).To(Equal(fmt.Sprintf("arn:aws:iam::%s:role/KarpenterControllerRole", fake.DefaultAccount)))
+	})
+	It("should only get the caller identity once", func() {
+		for range 3 {
+			_, err := permissionProvider.Missing(ctx, permission.Check{Action: "ec2:CreateTags", Resource: "volume/*"})
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(stsapi.GetCallerIdentityBehavior.Calls()).To(Equal(1))
+	})
+	It("should resolve relative resources to ARNs in the service of the action", func() {
+		_, err := permissionProvider.Missing(ctx,
+			permission.Check{Action: "ec2:CreateTags", Resource: "volume/*"},
+			permission.Check{Action: "iam:PassRole", Resource: "role/KarpenterNodeRole"},
+			permission.Check{Action: "ec2:TerminateInstances", Resource: "*"},
+			permission.Check{Action: "kms:CreateGrant", Resource: "arn:aws:kms:us-east-1:111122223333:key/test-key"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		var resources []string
+		for iamapi.SimulatePrincipalPolicyBehavior.CalledWithInput.Len() > 0 {
+			resources = append(resources, iamapi.SimulatePrincipalPolicyBehavior.CalledWithInput.Pop().ResourceArns...)
+		}
+		Expect(resources).To(ConsistOf(
+			fmt.Sprintf("arn:aws:ec2:%s:%s:volume/*", fake.DefaultRegion, fake.DefaultAccount),
+			fmt.Sprintf("arn:aws:iam::%s:role/KarpenterNodeRole", fake.DefaultAccount),
+			"*",
+			"arn:aws:kms:us-east-1:111122223333:key/test-key",
+		))
+	})
+	It("should simulate the request with the context of the check", func() {
+		_, err := permissionProvider.Missing(ctx, permission.Check{
+			Action:   "ec2:CreateTags",
+			Resource: "volume/*",
+			Context: map[string][]string{
+				"ec2:CreateAction": {"CreateFleet"},
+				"aws:TagKeys":      {"karpenter.sh/nodepool"},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		input := iamapi.SimulatePrincipalPolicyBehavior.CalledWithInput.Pop()
+		Expect(input.ContextEntries).To(Equal([]iamtypes.ContextEntry{
+			{ContextKeyName: lo.ToPtr("aws:TagKeys"), ContextKeyValues: []string{"karpenter.sh/nodepool"}, ContextKeyType: iamtypes.ContextKeyTypeEnumStringList},
+			{ContextKeyName: lo.ToPtr("ec2:CreateAction"), ContextKeyValues: []string{"CreateFleet"}, ContextKeyType: iamtypes.ContextKeyTypeEnumString},
+		}))
+	})
+	It("should not report checks as missing when the decision depends on missing context", func() {
+		iamapi.SimulatePrincipalPolicyBehavior.Output.Set(&iam.SimulatePrincipalPolicyOutput{
+			EvaluationResults: []iamtypes.EvaluationResult{{
+				EvalActionName:       lo.ToPtr("ec2:CreateTags"),
+				EvalDecision:         iamtypes.PolicyEvaluationDecisionTypeImplicitDeny,
+				MissingContextValues: []string{"aws:RequestTag/Name"},
+			}},
+		})
+		missing, err := permissionProvider.Missing(ctx, permission.Check{Action: "ec2:CreateTags", Resource: "volume/*"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+	})
+	It("should only report checks with resource-based policies as missing when they're explicitly denied", func() {
+		iamapi.DeniedActions.Insert("kms:CreateGrant")
+		missing, err := permissionProvider.Missing(ctx, permission.Check{Action: "kms:CreateGrant", Resource: "key/test-key", ResourceBasedPolicy: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+
+		iamapi.SimulatePrincipalPolicyBehavior.Output.Set(&iam.SimulatePrincipalPolicyOutput{
+			EvaluationResults: []iamtypes.EvaluationResult{{
+				EvalActionName: lo.ToPtr("kms:CreateGrant"),
+				EvalDecision:   iamtypes.PolicyEvaluationDecisionTypeExplicitDeny,
+			}},
+		})
+		missing, err = permissionProvider.Missing(ctx, permission.Check{Action: "kms:CreateGrant", Resource: "key/test-key", ResourceBasedPolicy: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(HaveLen(1))
+	})
+	It("should skip the checks when the controller isn't authorized to simulate its policies", func() {
+		iamapi.DeniedActions.Insert("ec2:CreateTags")
+		iamapi.SimulatePrincipalPolicyBehavior.Error.Set(&smithy.GenericAPIError{Code: "AccessDenied"})
+		missing, err := permissionProvider.Missing(ctx, permission.Check{Action: "ec2:CreateTags", Resource: "volume/*"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+	})
+	It("should return an error when the policies can't be simulated", func() {
+		iamapi.SimulatePrincipalPolicyBehavior.Error.Set(&smithy.GenericAPIError{Code: "ServiceFailure"})
+		_, err := permissionProvider.Missing(ctx, permission.Check{Action: "ec2:CreateTags", Resource: "volume/*"})
+		Expect(err).To(HaveOccurred())
+	})
+	It("should not include the account in the ARNs of public SSM parameters", func() {
+		_, err := permissionProvider.Missing(ctx,
+			permission.Check{Action: "ssm:GetParameter", Resource: "parameter/aws/service/eks/optimized-ami/*"},
+			permission.Check{Action: "ssm:GetParameter", Resource: "parameter/custom/ami"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		var resources []string
+		for iamapi.SimulatePrincipalPolicyBehavior.CalledWithInput.Len() > 0 {
+			resources = append(resources, iamapi.SimulatePrincipalPolicyBehavior.CalledWithInput.Pop().ResourceArns...)
+		}
+		Expect(resources).To(ConsistOf(
+			fmt.Sprintf("arn:aws:ssm:%s::parameter/aws/service/eks/optimized-ami/*", fake.DefaultRegion),
+			fmt.Sprintf("arn:aws:ssm:%s:%s:parameter/custom/ami", fake.DefaultRegion, fake.DefaultAccount),
+		))
+	})
+	It("should check capacity reservation permissions with a dry run", func() {
+		ec2api.NextError.Set(&smithy.GenericAPIError{Code: "UnauthorizedOperation"})
+		missing, err := permissionProvider.Missing(ctx, permission.Check{Action: "ec2:DescribeCapacityReservations", Resource: "*"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(HaveLen(1))
+		Expect(iamapi.SimulatePrincipalPolicyBehavior.Calls()).To(Equal(0))
+	})
+	It("should return an error when a dry run fails for any other reason", func() {
+		ec2api.NextError.Set(&smithy.GenericAPIError{Code: "InternalError"})
+		_, err := permissionProvider.Missing(ctx, permission.Check{Action: "ec2:DescribeCapacityReservations", Resource: "*"})
+		Expect(err).To(HaveOccurred())
+	})
+	It("should skip the checks when the context is scoped to an assumed role", func() {
+		iamapi.DeniedActions.Insert("ec2:CreateTags")
+		missing, err := permissionProvider.Missing(account.IntoContext(ctx, &v1.AssumeRole{RoleARN: "arn:aws:iam::111122223333:role/KarpenterNodeLauncher"}),
+			permission.Check{Action: "ec2:CreateTags", Resource: "volume/*"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+		Expect(iamapi.SimulatePrincipalPolicyBehavior.Calls()).To(Equal(0))
+		Expect(stsapi.GetCallerIdentityBehavior.Calls()).To(Equal(0))
+	})
+	It("should make dry run checks when the context is scoped to an assumed role", func() {
+		ec2api.NextError.Set(&smithy.GenericAPIError{Code: "UnauthorizedOperation"})
+		missing, err := permissionProvider.Missing(account.IntoContext(ctx, &v1.AssumeRole{RoleARN: "arn:aws:iam::111122223333:role/KarpenterNodeLauncher"}),
+			permission.Check{Action: "ec2:DescribeCapacityReservations", Resource: "*"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(missing).To(HaveLen(1))
+	})
+})
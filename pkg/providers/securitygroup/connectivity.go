@@ -0,0 +1,84 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securitygroup
+
+import (
+	"fmt"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	KubeletPort   = 10250
+	APIServerPort = 443
+)
+
+// ValidateClusterConnectivity returns the paths between the control plane and the nodes that the rules of their
+// security groups don't allow. The control plane must reach the kubelet, and the nodes must reach the API server. Rules
+// with a CIDR block or prefix list source are assumed to allow the traffic, since the addresses of the control plane
+// and the nodes aren't known ahead of time.
+func ValidateClusterConnectivity(nodeSecurityGroups, clusterSecurityGroups []ec2types.SecurityGroup) []string {
+	nodeIDs := sets.New(lo.Map(nodeSecurityGroups, func(sg ec2types.SecurityGroup, _ int) string { return lo.FromPtr(sg.GroupId) })...)
+	clusterIDs := sets.New(lo.Map(clusterSecurityGroups, func(sg ec2types.SecurityGroup, _ int) string { return lo.FromPtr(sg.GroupId) })...)
+	ingress := func(sg ec2types.SecurityGroup) []ec2types.IpPermission { return sg.IpPermissions }
+	egress := func(sg ec2types.SecurityGroup) []ec2types.IpPermission { return sg.IpPermissionsEgress }
+
+	var missing []string
+	if !allows(nodeSecurityGroups, ingress, KubeletPort, clusterIDs) {
+		missing = append(missing, fmt.Sprintf("ingress to nodes from the control plane on tcp/%d", KubeletPort))
+	}
+	if !allows(clusterSecurityGroups, egress, KubeletPort, nodeIDs) {
+		missing = append(missing, fmt.Sprintf("egress from the control plane to nodes on tcp/%d", KubeletPort))
+	}
+	if !allows(nodeSecurityGroups, egress, APIServerPort, clusterIDs) {
+		missing = append(missing, fmt.Sprintf("egress from nodes to the control plane on tcp/%d", APIServerPort))
+	}
+	if !allows(clusterSecurityGroups, ingress, APIServerPort, nodeIDs) {
+		missing = append(missing, fmt.Sprintf("ingress to the control plane from nodes on tcp/%d", APIServerPort))
+	}
+	return missing
+}
+
+// allows returns true if any rule of the security groups allows tcp traffic on the port with one of the peers. The
+// rules of every security group attached to an interface apply, so a single rule is enough.
+func allows(securityGroups []ec2types.SecurityGroup, rules func(ec2types.SecurityGroup) []ec2types.IpPermission, port int32, peers sets.Set[string]) bool {
+	return lo.ContainsBy(securityGroups, func(sg ec2types.SecurityGroup) bool {
+		return lo.ContainsBy(rules(sg), func(rule ec2types.IpPermission) bool {
+			return allowsPort(rule, port) && allowsPeer(rule, peers)
+		})
+	})
+}
+
+func allowsPort(rule ec2types.IpPermission, port int32) bool {
+	switch lo.FromPtr(rule.IpProtocol) {
+	case "-1":
+		return true
+	case "tcp", "6":
+		return lo.FromPtr(rule.FromPort) <= port && port <= lo.FromPtr(rule.ToPort)
+	default:
+		return false
+	}
+}
+
+func allowsPeer(rule ec2types.IpPermission, peers sets.Set[string]) bool {
+	if len(rule.IpRanges) > 0 || len(rule.Ipv6Ranges) > 0 || len(rule.PrefixListIds) > 0 {
+		return true
+	}
+	return lo.ContainsBy(rule.UserIdGroupPairs, func(pair ec2types.UserIdGroupPair) bool {
+		return peers.Has(lo.FromPtr(pair.GroupId))
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/mitchellh/hashstructure/v2"
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
@@ -31,22 +32,30 @@ import (
 
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	sdk "github.com/aws/karpenter-provider-aws/pkg/aws"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/account"
 )
 
+// clusterSecurityGroupsKey is the cache key of the security groups of the EKS control plane
+const clusterSecurityGroupsKey = "cluster-security-groups"
+
 type Provider interface {
 	List(context.Context, *v1.EC2NodeClass) ([]ec2types.SecurityGroup, error)
+	ListClusterSecurityGroups(context.Context) ([]ec2types.SecurityGroup, error)
 }
 
 type DefaultProvider struct {
 	sync.Mutex
 	ec2api sdk.EC2API
+	eksapi sdk.EKSAPI
 	cache  *cache.Cache
 	cm     *pretty.ChangeMonitor
 }
 
-func NewDefaultProvider(ec2api sdk.EC2API, cache *cache.Cache) *DefaultProvider {
+func NewDefaultProvider(ec2api sdk.EC2API, eksapi sdk.EKSAPI, cache *cache.Cache) *DefaultProvider {
 	return &DefaultProvider{
 		ec2api: ec2api,
+		eksapi: eksapi,
 		cm:     pretty.NewChangeMonitor(),
 		// TODO: Remove cache cache when we utilize the security groups from the EC2NodeClass.status
 		cache: cache,
@@ -72,12 +81,45 @@ func (p *DefaultProvider) List(ctx context.Context, nodeClass *v1.EC2NodeClass)
 	return securityGroups, nil
 }
 
+// ListClusterSecurityGroups returns the security groups that are attached to the EKS control plane, including the
+// cluster security group. The security groups are always described in the account of the controller, since that's
+// the account of the cluster.
+func (p *DefaultProvider) ListClusterSecurityGroups(ctx context.Context) ([]ec2types.SecurityGroup, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if sg, ok := p.cache.Get(clusterSecurityGroupsKey); ok {
+		return append([]ec2types.SecurityGroup{}, sg.([]ec2types.SecurityGroup)...), nil
+	}
+	ctx = account.IntoContext(ctx, nil)
+	out, err := p.eksapi.DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: aws.String(options.FromContext(ctx).ClusterName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describing cluster, %w", err)
+	}
+	var securityGroupIDs []string
+	if config := out.Cluster.ResourcesVpcConfig; config != nil {
+		securityGroupIDs = lo.Uniq(append(lo.Compact([]string{aws.ToString(config.ClusterSecurityGroupId)}), config.SecurityGroupIds...))
+	}
+	if len(securityGroupIDs) == 0 {
+		p.cache.SetDefault(clusterSecurityGroupsKey, []ec2types.SecurityGroup{})
+		return nil, nil
+	}
+	securityGroups, err := p.getSecurityGroups(ctx, [][]ec2types.Filter{{{Name: aws.String("group-id"), Values: securityGroupIDs}}})
+	if err != nil {
+		return nil, err
+	}
+	p.cache.SetDefault(clusterSecurityGroupsKey, securityGroups)
+	return securityGroups, nil
+}
+
 func (p *DefaultProvider) getSecurityGroups(ctx context.Context, filterSets [][]ec2types.Filter) ([]ec2types.SecurityGroup, error) {
 	hash, err := hashstructure.Hash(filterSets, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	if err != nil {
 		return nil, err
 	}
-	if sg, ok := p.cache.Get(fmt.Sprint(hash)); ok {
+	if sg, ok := p.cache.Get(account.CacheKey(ctx, fmt.Sprint(hash))); ok {
 		// Ensure what's returned from this function is a shallow-copy of the slice (not a deep-copy of the data itself)
 		// so that modifications to the ordering of the data don't affect the original
 		return append([]ec2types.SecurityGroup{}, sg.([]ec2types.SecurityGroup)...), nil
@@ -98,7 +140,7 @@ func (p *DefaultProvider) getSecurityGroups(ctx context.Context, filterSets [][]
 			}
 		}
 	}
-	p.cache.SetDefault(fmt.Sprint(hash), lo.Values(securityGroups))
+	p.cache.SetDefault(account.CacheKey(ctx, fmt.Sprint(hash)), lo.Values(securityGroups))
 	return lo.Values(securityGroups), nil
 }
 
@@ -25,11 +25,14 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/samber/lo"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis"
 	v1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
 
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
@@ -483,6 +486,107 @@ var _ = Describe("SecurityGroupProvider", func() {
 		}, securityGroups)
 		Expect(awsEnv.EC2API.DescribeSecurityGroupsBehavior.Calls()).To(Equal(3))
 	})
+	Context("Cluster Security Groups", func() {
+		It("should resolve the cluster security group and the additional security groups of the control plane", func() {
+			awsEnv.EKSAPI.DescribeClusterBehavior.Output.Set(&eks.DescribeClusterOutput{Cluster: &ekstypes.Cluster{
+				ResourcesVpcConfig: &ekstypes.VpcConfigResponse{
+					ClusterSecurityGroupId: aws.String("sg-test1"),
+					SecurityGroupIds:       []string{"sg-test2"},
+				},
+			}})
+			securityGroups, err := awsEnv.SecurityGroupProvider.ListClusterSecurityGroups(ctx)
+			Expect(err).To(BeNil())
+			ExpectConsistsOfSecurityGroups([]ec2types.SecurityGroup{
+				{
+					GroupId:   aws.String("sg-test1"),
+					GroupName: aws.String("securityGroup-test1"),
+				},
+				{
+					GroupId:   aws.String("sg-test2"),
+					GroupName: aws.String("securityGroup-test2"),
+				},
+			}, securityGroups)
+		})
+		It("should resolve the cluster security groups from cache", func() {
+			awsEnv.EKSAPI.DescribeClusterBehavior.Output.Set(&eks.DescribeClusterOutput{Cluster: &ekstypes.Cluster{
+				ResourcesVpcConfig: &ekstypes.VpcConfigResponse{ClusterSecurityGroupId: aws.String("sg-test1")},
+			}})
+			for range 3 {
+				_, err := awsEnv.SecurityGroupProvider.ListClusterSecurityGroups(ctx)
+				Expect(err).To(BeNil())
+			}
+			Expect(awsEnv.EKSAPI.DescribeClusterBehavior.Calls()).To(Equal(1))
+			Expect(awsEnv.EC2API.DescribeSecurityGroupsBehavior.Calls()).To(Equal(1))
+		})
+		It("should return no security groups when the cluster doesn't have any", func() {
+			securityGroups, err := awsEnv.SecurityGroupProvider.ListClusterSecurityGroups(ctx)
+			Expect(err).To(BeNil())
+			Expect(securityGroups).To(BeEmpty())
+			Expect(awsEnv.EC2API.DescribeSecurityGroupsBehavior.Calls()).To(BeZero())
+		})
+	})
+	Context("Cluster Connectivity", func() {
+		rule := func(protocol string, from, to int32, groupIDs ...string) ec2types.IpPermission {
+			return ec2types.IpPermission{
+				IpProtocol: aws.String(protocol),
+				FromPort:   aws.Int32(from),
+				ToPort:     aws.Int32(to),
+				UserIdGroupPairs: lo.Map(groupIDs, func(id string, _ int) ec2types.UserIdGroupPair {
+					return ec2types.UserIdGroupPair{GroupId: aws.String(id)}
+				}),
+			}
+		}
+		allTraffic := ec2types.IpPermission{IpProtocol: aws.String("-1"), IpRanges: []ec2types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}
+		securityGroup := func(id string, ingress ...ec2types.IpPermission) ec2types.SecurityGroup {
+			return ec2types.SecurityGroup{GroupId: aws.String(id), IpPermissions: ingress, IpPermissionsEgress: []ec2types.IpPermission{allTraffic}}
+		}
+
+		DescribeTable("should validate the paths between the control plane and the nodes",
+			func(node, cluster []ec2types.SecurityGroup, expected []string) {
+				Expect(securitygroup.ValidateClusterConnectivity(node, cluster)).To(Equal(expected))
+			},
+			Entry("when the nodes use the cluster security group",
+				[]ec2types.SecurityGroup{securityGroup("sg-cluster", rule("-1", 0, 0, "sg-cluster"))},
+				[]ec2types.SecurityGroup{securityGroup("sg-cluster", rule("-1", 0, 0, "sg-cluster"))},
+				nil,
+			),
+			Entry("when the rules reference each security group",
+				[]ec2types.SecurityGroup{securityGroup("sg-node", rule("tcp", 1025, 65535, "sg-cluster"))},
+				[]ec2types.SecurityGroup{securityGroup("sg-cluster", rule("tcp", 443, 443, "sg-node"))},
+				nil,
+			),
+			Entry("when the node security group doesn't allow the kubelet port",
+				[]ec2types.SecurityGroup{securityGroup("sg-node", rule("tcp", 22, 22, "sg-cluster"))},
+				[]ec2types.SecurityGroup{securityGroup("sg-cluster", rule("tcp", 443, 443, "sg-node"))},
+				[]string{"ingress to nodes from the control plane on tcp/10250"},
+			),
+			Entry("when the kubelet port is only allowed from other security groups",
+				[]ec2types.SecurityGroup{securityGroup("sg-node", rule("tcp", 10250, 10250, "sg-other"))},
+				[]ec2types.SecurityGroup{securityGroup("sg-cluster", rule("tcp", 443, 443, "sg-node"))},
+				[]string{"ingress to nodes from the control plane on tcp/10250"},
+			),
+			Entry("when the kubelet port is allowed from a CIDR block",
+				[]ec2types.SecurityGroup{securityGroup("sg-node", ec2types.IpPermission{IpProtocol: aws.String("tcp"), FromPort: aws.Int32(10250), ToPort: aws.Int32(10250), IpRanges: []ec2types.IpRange{{CidrIp: aws.String("10.0.0.0/16")}}})},
+				[]ec2types.SecurityGroup{securityGroup("sg-cluster", rule("tcp", 443, 443, "sg-node"))},
+				nil,
+			),
+			Entry("when the cluster security group doesn't allow the nodes",
+				[]ec2types.SecurityGroup{securityGroup("sg-node", rule("tcp", 10250, 10250, "sg-cluster"))},
+				[]ec2types.SecurityGroup{securityGroup("sg-cluster", rule("udp", 443, 443, "sg-node"))},
+				[]string{"ingress to the control plane from nodes on tcp/443"},
+			),
+			Entry("when no egress is allowed",
+				[]ec2types.SecurityGroup{{GroupId: aws.String("sg-node"), IpPermissions: []ec2types.IpPermission{rule("tcp", 10250, 10250, "sg-cluster")}}},
+				[]ec2types.SecurityGroup{{GroupId: aws.String("sg-cluster"), IpPermissions: []ec2types.IpPermission{rule("tcp", 443, 443, "sg-node")}}},
+				[]string{"egress from the control plane to nodes on tcp/10250", "egress from nodes to the control plane on tcp/443"},
+			),
+			Entry("when any of the node security groups allows the traffic",
+				[]ec2types.SecurityGroup{securityGroup("sg-node-1"), securityGroup("sg-node-2", rule("tcp", 10250, 10250, "sg-cluster"))},
+				[]ec2types.SecurityGroup{securityGroup("sg-cluster", rule("tcp", 443, 443, "sg-node-1"))},
+				nil,
+			),
+		)
+	})
 })
 
 func ExpectConsistsOfSecurityGroups(expected, actual []ec2types.SecurityGroup) {
@@ -36,6 +36,7 @@ import (
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/permission"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 	ssmp "github.com/aws/karpenter-provider-aws/pkg/providers/ssm"
@@ -63,6 +64,8 @@ type Environment struct {
 	EKSAPI     *fake.EKSAPI
 	SSMAPI     *fake.SSMAPI
 	IAMAPI     *fake.IAMAPI
+	KMSAPI     *fake.KMSAPI
+	STSAPI     *fake.STSAPI
 	PricingAPI *fake.PricingAPI
 
 	// Cache
@@ -81,6 +84,7 @@ type Environment struct {
 	CapacityReservationCache             *cache.Cache
 	CapacityReservationAvailabilityCache *cache.Cache
 	ValidationCache                      *cache.Cache
+	PermissionCache                      *cache.Cache
 
 	// Providers
 	CapacityReservationProvider *capacityreservation.DefaultProvider
@@ -95,6 +99,7 @@ type Environment struct {
 	AMIResolver                 *amifamily.DefaultResolver
 	VersionProvider             *version.DefaultProvider
 	LaunchTemplateProvider      *launchtemplate.DefaultProvider
+	PermissionProvider          *permission.DefaultProvider
 }
 
 func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment {
@@ -106,6 +111,8 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 	eksapi := fake.NewEKSAPI()
 	ssmapi := fake.NewSSMAPI()
 	iamapi := fake.NewIAMAPI()
+	kmsapi := fake.NewKMSAPI()
+	stsapi := fake.NewSTSAPI()
 
 	// cache
 	ec2Cache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
@@ -123,13 +130,14 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 	capacityReservationCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
 	capacityReservationAvailabilityCache := cache.New(24*time.Hour, awscache.DefaultCleanupInterval)
 	validationCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
+	permissionCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
 	fakePricingAPI := &fake.PricingAPI{}
 	eventRecorder := coretest.NewEventRecorder()
 
 	// Providers
 	pricingProvider := pricing.NewDefaultProvider(fakePricingAPI, ec2api, fake.DefaultRegion, false)
 	subnetProvider := subnet.NewDefaultProvider(ec2api, subnetCache, availableIPAdressCache, associatePublicIPAddressCache)
-	securityGroupProvider := securitygroup.NewDefaultProvider(ec2api, securityGroupCache)
+	securityGroupProvider := securitygroup.NewDefaultProvider(ec2api, eksapi, securityGroupCache)
 	versionProvider := version.NewDefaultProvider(env.KubernetesInterface, eksapi)
 	// Ensure we're able to hydrate the version before starting any reliant controllers.
 	// Version updates are hydrated asynchronously after this, in the event of a failure
@@ -155,6 +163,7 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 		net.ParseIP("10.0.100.10"),
 		"https://test-cluster",
 	)
+	permissionProvider := permission.NewDefaultProvider(ec2api, iamapi, stsapi, fake.DefaultRegion, permissionCache)
 	instanceProvider := instance.NewDefaultProvider(
 		ctx,
 		"",
@@ -174,6 +183,8 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 		EKSAPI:     eksapi,
 		SSMAPI:     ssmapi,
 		IAMAPI:     iamapi,
+		KMSAPI:     kmsapi,
+		STSAPI:     stsapi,
 		PricingAPI: fakePricingAPI,
 
 		EC2Cache:          ec2Cache,
@@ -192,6 +203,7 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 		CapacityReservationCache:             capacityReservationCache,
 		CapacityReservationAvailabilityCache: capacityReservationAvailabilityCache,
 		ValidationCache:                      validationCache,
+		PermissionCache:                      permissionCache,
 
 		CapacityReservationProvider: capacityReservationProvider,
 		InstanceTypesResolver:       instanceTypesResolver,
@@ -205,6 +217,7 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 		AMIProvider:                 amiProvider,
 		AMIResolver:                 amiResolver,
 		VersionProvider:             versionProvider,
+		PermissionProvider:          permissionProvider,
 	}
 }
 
@@ -214,6 +227,8 @@ func (env *Environment) Reset() {
 	env.EKSAPI.Reset()
 	env.SSMAPI.Reset()
 	env.IAMAPI.Reset()
+	env.KMSAPI.Reset()
+	env.STSAPI.Reset()
 	env.PricingAPI.Reset()
 	env.PricingProvider.Reset()
 	env.InstanceTypesProvider.Reset()
@@ -231,6 +246,7 @@ func (env *Environment) Reset() {
 	env.DiscoveredCapacityCache.Flush()
 	env.CapacityReservationCache.Flush()
 	env.ValidationCache.Flush()
+	env.PermissionCache.Flush()
 	mfs, err := crmetrics.Registry.Gather()
 	if err != nil {
 		for _, mf := range mfs {
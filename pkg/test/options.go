@@ -32,6 +32,9 @@ type OptionsFields struct {
 	VMMemoryOverheadPercent *float64
 	InterruptionQueue       *string
 	ReservedENIs            *int
+	ReportInstanceTypes     *bool
+	SubnetIPThreshold       *int
+	ValidateSecurityGroups  *bool
 }
 
 func Options(overrides ...OptionsFields) *options.Options {
@@ -50,5 +53,8 @@ func Options(overrides ...OptionsFields) *options.Options {
 		VMMemoryOverheadPercent: lo.FromPtrOr(opts.VMMemoryOverheadPercent, 0.075),
 		InterruptionQueue:       lo.FromPtrOr(opts.InterruptionQueue, ""),
 		ReservedENIs:            lo.FromPtrOr(opts.ReservedENIs, 0),
+		ReportInstanceTypes:     lo.FromPtrOr(opts.ReportInstanceTypes, false),
+		SubnetIPThreshold:       lo.FromPtrOr(opts.SubnetIPThreshold, 0),
+		ValidateSecurityGroups:  lo.FromPtrOr(opts.ValidateSecurityGroups, false),
 	}
 }
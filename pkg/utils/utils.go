@@ -119,5 +119,9 @@ func GetTags(nodeClass *v1.EC2NodeClass, nodeClaim *karpv1.NodeClaim, clusterNam
 		v1.EKSClusterNameTagKey:                              clusterName,
 		v1.LabelNodeClass:                                    nodeClass.Name,
 	}
+	// Identifies the account of the instance when it's listed, since the providerID doesn't include the account
+	if nodeClass.Spec.AssumeRole != nil {
+		staticTags[v1.AssumeRoleARNTagKey] = nodeClass.Spec.AssumeRole.RoleARN
+	}
 	return lo.Assign(nodeClass.Spec.Tags, staticTags), nil
 }
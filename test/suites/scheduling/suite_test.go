@@ -115,6 +115,7 @@ var _ = Describe("Scheduling", Ordered, ContinueOnFailure, func() {
 				v1.LabelInstanceFamily:                    "c5",
 				v1.LabelInstanceSize:                      "large",
 				v1.LabelInstanceCPU:                       "2",
+				v1.LabelInstanceThreadsPerCore:            "2",
 				v1.LabelInstanceCPUManufacturer:           "intel",
 				v1.LabelInstanceCPUSustainedClockSpeedMhz: "3400",
 				v1.LabelInstanceMemory:                    "4096",